@@ -5,6 +5,7 @@ import (
 
 	"github.com/abdisetiakawan/go-clean-arch/internal/config"
 	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
+	"github.com/abdisetiakawan/go-clean-arch/internal/realtime"
 )
 
 func main() {
@@ -16,6 +17,9 @@ func main() {
     jwt := helper.NewJWTHelper(viperConfig)
     redisClient := config.NewRedisClient(viperConfig, log)
     cache := helper.NewCacheHelper(redisClient)
+    events := realtime.NewBroker(redisClient, log)
+    searchIndex := config.NewSearchIndex(viperConfig, db, log)
+    mailer := config.NewMailer(viperConfig, log)
 
     config.Bootstrap(&config.BootstrapConfig{
         DB:       db,
@@ -25,6 +29,9 @@ func main() {
         Config:   viperConfig,
         Jwt:      jwt,
         Cache:    cache,
+        Search:   searchIndex,
+        Events:   events,
+        Mailer:   mailer,
     })
 
     webPort := viperConfig.GetInt("web.port")
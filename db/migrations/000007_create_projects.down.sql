@@ -0,0 +1,7 @@
+ALTER TABLE tags DROP FOREIGN KEY fk_tags_project;
+ALTER TABLE tags DROP COLUMN project_id;
+ALTER TABLE tasks DROP FOREIGN KEY fk_tasks_project;
+ALTER TABLE tasks DROP COLUMN project_id;
+DROP TABLE IF EXISTS project_invitations;
+DROP TABLE IF EXISTS project_members;
+DROP TABLE IF EXISTS projects;
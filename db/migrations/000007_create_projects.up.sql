@@ -0,0 +1,56 @@
+CREATE TABLE projects (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    owner_email VARCHAR(150) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    is_personal BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (owner_email) REFERENCES users(email) ON DELETE CASCADE
+);
+
+CREATE TABLE project_members (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    project_id INT NOT NULL,
+    email VARCHAR(150) NOT NULL,
+    role ENUM('owner', 'editor', 'viewer') NOT NULL DEFAULT 'viewer',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_project_members_project_email (project_id, email),
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (email) REFERENCES users(email) ON DELETE CASCADE
+);
+
+CREATE TABLE project_invitations (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    project_id INT NOT NULL,
+    email VARCHAR(150) NOT NULL,
+    invited_by VARCHAR(150) NOT NULL,
+    role ENUM('editor', 'viewer') NOT NULL DEFAULT 'viewer',
+    status ENUM('pending', 'accepted', 'declined') NOT NULL DEFAULT 'pending',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_project_invitations_email (email),
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+INSERT INTO projects (owner_email, name, is_personal)
+SELECT email, 'Personal', TRUE FROM users;
+
+INSERT INTO project_members (project_id, email, role)
+SELECT id, owner_email, 'owner' FROM projects WHERE is_personal = TRUE;
+
+ALTER TABLE tasks ADD COLUMN project_id INT NULL AFTER email;
+UPDATE tasks
+JOIN projects ON projects.owner_email = tasks.email AND projects.is_personal = TRUE
+SET tasks.project_id = projects.id;
+ALTER TABLE tasks
+    MODIFY project_id INT NOT NULL,
+    ADD CONSTRAINT fk_tasks_project FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE;
+
+ALTER TABLE tags ADD COLUMN project_id INT NULL AFTER email;
+UPDATE tags
+JOIN projects ON projects.owner_email = tags.email AND projects.is_personal = TRUE
+SET tags.project_id = projects.id;
+ALTER TABLE tags
+    MODIFY project_id INT NOT NULL,
+    ADD CONSTRAINT fk_tags_project FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE;
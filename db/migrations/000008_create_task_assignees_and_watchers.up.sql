@@ -0,0 +1,21 @@
+CREATE TABLE task_assignees (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    task_id INT NOT NULL,
+    email VARCHAR(150) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_task_assignees_task_email (task_id, email),
+    INDEX idx_task_assignees_email (email),
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    FOREIGN KEY (email) REFERENCES users(email) ON DELETE CASCADE
+);
+
+CREATE TABLE task_watchers (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    task_id INT NOT NULL,
+    email VARCHAR(150) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_task_watchers_task_email (task_id, email),
+    INDEX idx_task_watchers_email (email),
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    FOREIGN KEY (email) REFERENCES users(email) ON DELETE CASCADE
+);
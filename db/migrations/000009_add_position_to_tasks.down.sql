@@ -0,0 +1,2 @@
+DROP INDEX idx_tasks_project_status_position ON tasks;
+ALTER TABLE tasks DROP COLUMN position;
@@ -0,0 +1,3 @@
+ALTER TABLE tasks ADD COLUMN position VARCHAR(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '' AFTER status;
+UPDATE tasks SET position = LPAD(id, 10, '0');
+CREATE INDEX idx_tasks_project_status_position ON tasks (project_id, status, position);
@@ -0,0 +1,3 @@
+DROP INDEX ft_tags_name ON tags;
+DROP INDEX ft_task_comments_body ON task_comments;
+DROP INDEX ft_tasks_title_description ON tasks;
@@ -0,0 +1,3 @@
+CREATE FULLTEXT INDEX ft_tasks_title_description ON tasks (title, description);
+CREATE FULLTEXT INDEX ft_task_comments_body ON task_comments (body);
+CREATE FULLTEXT INDEX ft_tags_name ON tags (name);
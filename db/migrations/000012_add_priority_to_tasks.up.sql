@@ -0,0 +1 @@
+ALTER TABLE tasks ADD COLUMN priority ENUM('low', 'medium', 'high', 'urgent') NOT NULL DEFAULT 'medium' AFTER status;
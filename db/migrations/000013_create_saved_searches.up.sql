@@ -0,0 +1,12 @@
+CREATE TABLE saved_searches (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    email VARCHAR(150) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    query VARCHAR(500) NOT NULL DEFAULT '',
+    sort VARCHAR(20) NOT NULL DEFAULT '',
+    pinned BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_saved_searches_email_name (email, name),
+    FOREIGN KEY (email) REFERENCES users(email) ON DELETE CASCADE
+);
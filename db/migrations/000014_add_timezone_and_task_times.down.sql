@@ -0,0 +1,2 @@
+ALTER TABLE tasks DROP COLUMN start_date, DROP COLUMN due_time;
+ALTER TABLE users DROP COLUMN locale, DROP COLUMN timezone;
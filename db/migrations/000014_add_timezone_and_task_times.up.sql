@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC' AFTER password, ADD COLUMN locale VARCHAR(35) NOT NULL DEFAULT 'en' AFTER timezone;
+ALTER TABLE tasks ADD COLUMN due_time TIME NULL AFTER due_date, ADD COLUMN start_date DATE NULL AFTER due_time;
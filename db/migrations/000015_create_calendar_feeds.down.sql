@@ -0,0 +1,2 @@
+ALTER TABLE tasks DROP INDEX uq_tasks_project_uid, DROP COLUMN recurrence, DROP COLUMN uid;
+DROP TABLE IF EXISTS calendar_feeds;
@@ -0,0 +1,9 @@
+CREATE TABLE calendar_feeds (
+    email VARCHAR(150) NOT NULL PRIMARY KEY,
+    token VARCHAR(64) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_calendar_feeds_token (token),
+    FOREIGN KEY (email) REFERENCES users(email) ON DELETE CASCADE
+);
+ALTER TABLE tasks ADD COLUMN uid VARCHAR(255) NULL AFTER project_id, ADD COLUMN recurrence VARCHAR(500) NULL AFTER start_date, ADD UNIQUE KEY uq_tasks_project_uid (project_id, uid);
@@ -0,0 +1,2 @@
+ALTER TABLE tasks DROP COLUMN version;
+ALTER TABLE tags DROP COLUMN version;
\ No newline at end of file
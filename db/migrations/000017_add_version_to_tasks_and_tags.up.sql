@@ -0,0 +1,2 @@
+ALTER TABLE tasks ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1 AFTER recurrence;
+ALTER TABLE tags ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1 AFTER name;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE tasks DROP FOREIGN KEY fk_tasks_parent, DROP COLUMN parent_id;
\ No newline at end of file
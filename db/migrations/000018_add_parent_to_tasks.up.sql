@@ -0,0 +1 @@
+ALTER TABLE tasks ADD COLUMN parent_id INT NULL AFTER project_id, ADD CONSTRAINT fk_tasks_parent FOREIGN KEY (parent_id) REFERENCES tasks(id) ON DELETE CASCADE;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS task_templates;
\ No newline at end of file
@@ -0,0 +1,16 @@
+CREATE TABLE task_templates (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    email VARCHAR(150) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    title VARCHAR(150) NOT NULL,
+    description TEXT NULL,
+    status ENUM('pending', 'in_progress', 'completed') NOT NULL DEFAULT 'pending',
+    priority ENUM('low', 'medium', 'high', 'urgent') NOT NULL DEFAULT 'medium',
+    due_in VARCHAR(20) NOT NULL DEFAULT '',
+    tags TEXT NULL,
+    subtasks MEDIUMTEXT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_task_templates_email_name (email, name),
+    FOREIGN KEY (email) REFERENCES users(email) ON DELETE CASCADE
+);
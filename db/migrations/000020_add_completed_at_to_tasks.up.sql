@@ -0,0 +1,3 @@
+ALTER TABLE tasks ADD COLUMN completed_at DATETIME NULL AFTER recurrence;
+UPDATE tasks SET completed_at = updated_at WHERE status = 'completed';
+CREATE INDEX idx_tasks_project_completed_at ON tasks (project_id, completed_at);
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE activities (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    project_id INT NOT NULL,
+    actor VARCHAR(100) NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id INT NOT NULL,
+    summary VARCHAR(255) NOT NULL,
+    data TEXT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_activities_project (project_id, id),
+    INDEX idx_activities_entity (entity_type, entity_id, id),
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
@@ -0,0 +1,43 @@
+CREATE TABLE notifications (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    email VARCHAR(150) NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    body TEXT NULL,
+    data TEXT NULL,
+    dedupe_key VARCHAR(150) NULL,
+    in_app BOOLEAN NOT NULL DEFAULT TRUE,
+    read_at DATETIME NULL,
+    email_status ENUM('none', 'pending', 'digest', 'sent', 'failed') NOT NULL DEFAULT 'none',
+    webhook_status ENUM('none', 'pending', 'sent', 'failed') NOT NULL DEFAULT 'none',
+    deliver_after DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_notifications_dedupe (email, dedupe_key),
+    INDEX idx_notifications_inbox (email, in_app, id),
+    INDEX idx_notifications_deliver (deliver_after),
+    FOREIGN KEY (email) REFERENCES users(email) ON DELETE CASCADE
+);
+
+CREATE TABLE notification_preferences (
+    email VARCHAR(150) NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    in_app BOOLEAN NOT NULL DEFAULT TRUE,
+    by_email BOOLEAN NOT NULL DEFAULT FALSE,
+    by_webhook BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (email, type),
+    FOREIGN KEY (email) REFERENCES users(email) ON DELETE CASCADE
+);
+
+CREATE TABLE notification_settings (
+    email VARCHAR(150) PRIMARY KEY,
+    digest BOOLEAN NOT NULL DEFAULT FALSE,
+    digest_time VARCHAR(5) NOT NULL DEFAULT '08:00',
+    last_digest_at DATETIME NULL,
+    quiet_start VARCHAR(5) NULL,
+    quiet_end VARCHAR(5) NULL,
+    webhook_url VARCHAR(2048) NULL,
+    webhook_secret VARCHAR(100) NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (email) REFERENCES users(email) ON DELETE CASCADE
+);
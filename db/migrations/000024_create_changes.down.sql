@@ -0,0 +1 @@
+DROP TABLE IF EXISTS changes;
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE changes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    project_id INT NOT NULL,
+    entity_type ENUM('task', 'tag', 'task_tag') NOT NULL,
+    task_id INT NULL,
+    tag_id INT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_changes_project (project_id, id)
+);
@@ -0,0 +1,2 @@
+DROP INDEX idx_import_jobs_status_lease ON import_jobs;
+ALTER TABLE import_jobs DROP COLUMN runs, DROP COLUMN lease_until, DROP COLUMN create_tags, DROP COLUMN payload;
@@ -0,0 +1,7 @@
+ALTER TABLE import_jobs
+    ADD COLUMN payload LONGTEXT NULL AFTER error,
+    ADD COLUMN create_tags BOOLEAN NOT NULL DEFAULT FALSE AFTER payload,
+    ADD COLUMN lease_until DATETIME NULL AFTER create_tags,
+    ADD COLUMN runs INT NOT NULL DEFAULT 0 AFTER lease_until;
+UPDATE import_jobs SET status = 'failed', error = 'interrupted by a restart', finished_at = CURRENT_TIMESTAMP WHERE status IN ('queued', 'running');
+CREATE INDEX idx_import_jobs_status_lease ON import_jobs (status, lease_until);
@@ -0,0 +1,8 @@
+DROP TABLE change_sequence;
+
+ALTER TABLE changes
+    ADD INDEX idx_changes_project (project_id, id),
+    DROP INDEX idx_changes_created,
+    DROP INDEX idx_changes_project_seq,
+    DROP INDEX idx_changes_seq,
+    DROP COLUMN seq;
@@ -0,0 +1,16 @@
+ALTER TABLE changes
+    ADD COLUMN seq INT NULL AFTER id,
+    ADD INDEX idx_changes_seq (seq),
+    ADD INDEX idx_changes_project_seq (project_id, seq),
+    ADD INDEX idx_changes_created (created_at),
+    DROP INDEX idx_changes_project;
+
+UPDATE changes SET seq = id;
+
+CREATE TABLE change_sequence (
+    id INT PRIMARY KEY,
+    value INT NOT NULL,
+    pruned INT NOT NULL DEFAULT 0
+);
+
+INSERT INTO change_sequence (id, value) SELECT 1, COALESCE(MAX(id), 0) FROM changes;
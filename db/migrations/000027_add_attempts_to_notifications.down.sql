@@ -0,0 +1,3 @@
+ALTER TABLE notifications
+    DROP COLUMN webhook_attempts,
+    DROP COLUMN email_attempts;
@@ -0,0 +1,3 @@
+ALTER TABLE notifications
+    ADD COLUMN email_attempts INT NOT NULL DEFAULT 0 AFTER webhook_status,
+    ADD COLUMN webhook_attempts INT NOT NULL DEFAULT 0 AFTER email_attempts;
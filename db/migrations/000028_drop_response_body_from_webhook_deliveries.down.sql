@@ -0,0 +1,2 @@
+ALTER TABLE webhook_deliveries
+    ADD COLUMN response_body TEXT NULL AFTER response_code;
@@ -0,0 +1,3 @@
+ALTER TABLE changes
+    DROP INDEX idx_changes_tag,
+    DROP INDEX idx_changes_task;
@@ -0,0 +1,3 @@
+ALTER TABLE changes
+    ADD INDEX idx_changes_task (task_id),
+    ADD INDEX idx_changes_tag (tag_id);
@@ -1,11 +1,15 @@
 package config
 
 import (
+	"context"
+
 	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http"
 	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/middleware"
 	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/route"
 	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
+	"github.com/abdisetiakawan/go-clean-arch/internal/realtime"
 	"github.com/abdisetiakawan/go-clean-arch/internal/repository"
+	"github.com/abdisetiakawan/go-clean-arch/internal/search"
 	"github.com/abdisetiakawan/go-clean-arch/internal/usecase"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -22,33 +26,131 @@ type BootstrapConfig struct {
     Config   *viper.Viper
     Jwt      *helper.JwtHelper
     Cache    *helper.CacheHelper
+    Search   search.Index
+    Events   *realtime.Broker
+    Mailer   helper.Mailer
 }
 
 func Bootstrap(config *BootstrapConfig) {
     userRepository := repository.NewUserRepository(config.Log)
-    userUseCase := usecase.NewUserUseCase(config.DB, config.Log, config.Validate, userRepository, config.Jwt, config.Cache)
+    taskRepository := repository.NewTaskRepository(config.Log)
+    taskAssigneeRepository := repository.NewTaskAssigneeRepository(config.Log)
+    notificationRepository := repository.NewNotificationRepository(config.Log)
+    notificationSettingRepository := repository.NewNotificationSettingRepository(config.Log)
+    notificationPreferenceRepository := repository.NewNotificationPreferenceRepository(config.Log)
+    notificationUseCase := usecase.NewNotificationUseCase(config.DB, config.Log, config.Validate, notificationRepository, notificationSettingRepository, notificationPreferenceRepository, userRepository, taskRepository, taskAssigneeRepository, config.Mailer)
+    go notificationUseCase.Run(context.Background())
+    notificationController := http.NewNotificationController(notificationUseCase, config.Log)
+    notifier := notificationUseCase
+
+    projectRepository := repository.NewProjectRepository(config.Log)
+    projectMemberRepository := repository.NewProjectMemberRepository(config.Log)
+    projectInvitationRepository := repository.NewProjectInvitationRepository(config.Log)
+    projectUseCase := usecase.NewProjectUseCase(config.DB, config.Log, config.Validate, projectRepository, projectMemberRepository, projectInvitationRepository, config.Cache, notifier)
+    projectController := http.NewProjectController(projectUseCase, config.Log)
+
+    userUseCase := usecase.NewUserUseCase(config.DB, config.Log, config.Validate, userRepository, projectRepository, projectMemberRepository, config.Jwt, config.Cache)
     userController := http.NewUserController(userUseCase, config.Log)
 
-    taskRepository := repository.NewTaskRepository(config.Log)
-    taskUseCase := usecase.NewTaskUseCase(config.DB, config.Log, config.Validate, taskRepository, config.Cache)
+    taskTagRepository := repository.NewtaskTagRepository(config.Log)
+    commentRepository := repository.NewCommentRepository(config.Log)
+    activityRepository := repository.NewActivityRepository(config.Log)
+    eventUseCase := usecase.NewEventUseCase(config.DB, config.Log, projectMemberRepository, config.Events)
+    go config.Events.Run(context.Background())
+    eventController := http.NewEventController(eventUseCase, config.Log)
+    searchUseCase := usecase.NewSearchUseCase(config.DB, config.Log, config.Validate, config.Search, taskRepository, taskTagRepository, commentRepository, projectMemberRepository)
+    if err := searchUseCase.Reindex(context.Background()); err != nil {
+        config.Log.Fatalf("Failed to build search index: %v", err)
+    }
+    searchController := http.NewSearchController(searchUseCase, config.Log)
+
+    taskWatcherRepository := repository.NewTaskWatcherRepository(config.Log)
+    taskUseCase := usecase.NewTaskUseCase(config.DB, config.Log, config.Validate, taskRepository, projectMemberRepository, taskAssigneeRepository, taskWatcherRepository, notifier, searchUseCase, config.Cache, activityRepository, eventUseCase, taskTagRepository)
     taskController := http.NewTaskController(taskUseCase, config.Log)
 
+    taskAssigneeUseCase := usecase.NewTaskAssigneeUseCase(config.DB, config.Log, config.Validate, taskRepository, taskAssigneeRepository, taskWatcherRepository, projectMemberRepository, notifier, config.Cache, activityRepository, eventUseCase)
+    taskAssigneeController := http.NewTaskAssigneeController(taskAssigneeUseCase, config.Log)
+
     tagRepository := repository.NewTagRepository(config.Log)
-    tagUseCase := usecase.NewTagUseCase(config.DB, config.Log, config.Validate, tagRepository, config.Cache)
+    tagUseCase := usecase.NewTagUseCase(config.DB, config.Log, config.Validate, tagRepository, projectMemberRepository, searchUseCase, config.Cache, activityRepository, eventUseCase)
     tagController := http.NewTagsController(tagUseCase, config.Log)
 
-    taskTagRepository := repository.NewtaskTagRepository(config.Log)
-    taskTagUseCase := usecase.NewTaskTagUseCase(config.DB, config.Log, config.Validate, taskTagRepository, config.Cache)
+    taskTagUseCase := usecase.NewTaskTagUseCase(config.DB, config.Log, config.Validate, taskTagRepository, projectMemberRepository, searchUseCase, config.Cache, activityRepository, eventUseCase)
     taskTagController := http.NewTaskTagController(taskTagUseCase, config.Log)
+
+    commentUseCase := usecase.NewCommentUseCase(config.DB, config.Log, config.Validate, commentRepository, taskRepository, projectMemberRepository, searchUseCase, activityRepository, eventUseCase, notifier)
+    commentController := http.NewCommentController(commentUseCase, config.Log)
+
+    savedSearchRepository := repository.NewSavedSearchRepository(config.Log)
+    savedSearchUseCase := usecase.NewSavedSearchUseCase(config.DB, config.Log, config.Validate, savedSearchRepository, taskRepository)
+    savedSearchController := http.NewSavedSearchController(savedSearchUseCase, config.Log)
+
+    calendarFeedRepository := repository.NewCalendarFeedRepository(config.Log)
+    icalUseCase := usecase.NewICalUseCase(config.DB, config.Log, config.Validate, calendarFeedRepository, userRepository, taskRepository, tagRepository, taskTagRepository, projectMemberRepository, searchUseCase, config.Cache, activityRepository, eventUseCase)
+    icalController := http.NewICalController(icalUseCase, config.Log)
+
+    transferUseCase := usecase.NewTransferUseCase(config.DB, config.Log, config.Validate, taskRepository, tagRepository, taskTagRepository, projectMemberRepository, searchUseCase, config.Cache, activityRepository, eventUseCase)
+    transferController := http.NewTransferController(transferUseCase, config.Log)
+
+    importJobRepository := repository.NewImportJobRepository(config.Log)
+    importJobUseCase := usecase.NewImportJobUseCase(config.DB, config.Log, config.Validate, importJobRepository, projectMemberRepository, transferUseCase)
+    go importJobUseCase.Run(context.Background())
+    importJobController := http.NewImportJobController(importJobUseCase, config.Log)
+
+    taskBatchUseCase := usecase.NewTaskBatchUseCase(config.DB, config.Log, config.Validate, taskRepository, tagRepository, taskTagRepository, taskAssigneeRepository, projectMemberRepository, searchUseCase, config.Cache, activityRepository, eventUseCase)
+    taskBatchController := http.NewTaskBatchController(taskBatchUseCase, config.Log)
+
+    taskTemplateRepository := repository.NewTaskTemplateRepository(config.Log)
+    taskTemplateUseCase := usecase.NewTaskTemplateUseCase(config.DB, config.Log, config.Validate, taskTemplateRepository, taskRepository, tagRepository, taskTagRepository, projectMemberRepository, searchUseCase, config.Cache, activityRepository, eventUseCase)
+    taskTemplateController := http.NewTaskTemplateController(taskTemplateUseCase, config.Log)
+
+    quickAddUseCase := usecase.NewQuickAddUseCase(config.DB, config.Log, config.Validate, taskRepository, tagRepository, taskTagRepository, projectMemberRepository, searchUseCase, config.Cache, activityRepository, eventUseCase)
+    quickAddController := http.NewQuickAddController(quickAddUseCase, config.Log)
+
+    statsUseCase := usecase.NewStatsUseCase(config.DB, config.Log, config.Validate, taskRepository, config.Cache)
+    statsController := http.NewStatsController(statsUseCase, config.Log)
+
+    activityUseCase := usecase.NewActivityUseCase(config.DB, config.Log, config.Validate, activityRepository)
+    activityController := http.NewActivityController(activityUseCase, config.Log)
+
+    webhookRepository := repository.NewWebhookRepository(config.Log)
+    webhookDeliveryRepository := repository.NewWebhookDeliveryRepository(config.Log)
+    webhookUseCase := usecase.NewWebhookUseCase(config.DB, config.Log, config.Validate, webhookRepository, webhookDeliveryRepository, projectMemberRepository)
+    go webhookUseCase.Run(context.Background())
+    webhookController := http.NewWebhookController(webhookUseCase, config.Log)
+
+    changeRepository := repository.NewChangeRepository(config.Log)
+    syncUseCase := usecase.NewSyncUseCase(config.DB, config.Log, config.Validate, changeRepository, projectMemberRepository, taskRepository, tagRepository, taskTagRepository, taskUseCase, tagUseCase, taskTagUseCase)
+    go syncUseCase.Run(context.Background())
+    syncController := http.NewSyncController(syncUseCase, config.Log)
     
     authMiddleware := middleware.NewAuth(userUseCase, config.Config, config.Cache)
+    idempotencyMiddleware := middleware.NewIdempotency(config.Cache, config.Log)
     routeConfig := route.RouteConfig{
         App:            config.App,
         UserController: userController,
         TaskController: taskController,
         TagsController: tagController,
         TaskTagController: taskTagController,
+        ProjectController: projectController,
+        TaskAssigneeController: taskAssigneeController,
+        CommentController: commentController,
+        SearchController: searchController,
+        SavedSearchController: savedSearchController,
+        ICalController: icalController,
+        TransferController: transferController,
+        ImportJobController: importJobController,
+        TaskBatchController: taskBatchController,
+        TaskTemplateController: taskTemplateController,
+        QuickAddController: quickAddController,
+        StatsController: statsController,
+        ActivityController: activityController,
+        EventController: eventController,
+        WebhookController: webhookController,
+        NotificationController: notificationController,
+        SyncController: syncController,
         AuthMiddleware: authMiddleware,
+        IdempotencyMiddleware: idempotencyMiddleware,
     }
     routeConfig.Setup()
 }
\ No newline at end of file
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// NewMailer picks the mailer from mail.driver. "log" (the default) writes
+// emails to the log, "smtp" sends them through mail.smtp.
+func NewMailer(viper *viper.Viper, log *logrus.Logger) helper.Mailer {
+	switch driver := viper.GetString("mail.driver"); driver {
+	case "", "log":
+		return helper.NewLogMailer(log)
+	case "smtp":
+		return helper.NewSMTPMailer(
+			viper.GetString("mail.smtp.host"),
+			viper.GetInt("mail.smtp.port"),
+			viper.GetString("mail.smtp.username"),
+			viper.GetString("mail.smtp.password"),
+			viper.GetString("mail.from"),
+		)
+	default:
+		log.Fatalf("unknown mail driver: %s", driver)
+		return nil
+	}
+}
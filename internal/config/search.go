@@ -0,0 +1,23 @@
+package config
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/search"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// NewSearchIndex picks the full-text backend from search.backend. MySQL
+// FULLTEXT indexes are the default, "memory" keeps an in-process index
+// that is rebuilt on start up.
+func NewSearchIndex(viper *viper.Viper, db *gorm.DB, log *logrus.Logger) search.Index {
+	switch backend := viper.GetString("search.backend"); backend {
+	case "", "mysql":
+		return search.NewMySQLIndex(db)
+	case "memory":
+		return search.NewMemoryIndex()
+	default:
+		log.Fatalf("unknown search backend: %s", backend)
+		return nil
+	}
+}
@@ -1,10 +1,15 @@
 package config
 
 import (
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
 	"github.com/go-playground/validator/v10"
 	"github.com/spf13/viper"
 )
 
 func NewValidator(viper *viper.Viper) *validator.Validate {
-	return validator.New()
+	validate := validator.New()
+	validate.RegisterCustomTypeFunc(model.OptionalValue, model.Optional[string]{}, model.Optional[time.Time]{})
+	return validate
 }
@@ -0,0 +1,47 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/middleware"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type ActivityController struct {
+	UseCase *usecase.ActivityUseCase
+	Log     *logrus.Logger
+}
+
+func NewActivityController(useCase *usecase.ActivityUseCase, logger *logrus.Logger) *ActivityController {
+	return &ActivityController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// List returns the activity feed. type takes a comma separated list.
+func (c *ActivityController) List(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.SearchActivityRequest{
+		Email:      auth.Email,
+		ProjectId:  uint(ctx.QueryInt("project_id", 0)),
+		EntityType: ctx.Query("entity_type", ""),
+		EntityId:   uint(ctx.QueryInt("entity_id", 0)),
+		Page:       ctx.QueryInt("page", 1),
+		Size:       ctx.QueryInt("size", 20),
+		Cursor:     ctx.Query("cursor", ""),
+		Total:      ctx.Query("total", ""),
+	}
+	if types := ctx.Query("type", ""); types != "" {
+		request.Types = strings.Split(types, ",")
+	}
+	responses, paging, err := c.UseCase.Search(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to list activities : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(responses, "Successfully get activities", fiber.StatusOK, paging))
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/middleware"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type CommentController struct {
+	UseCase *usecase.CommentUseCase
+	Log     *logrus.Logger
+}
+
+func NewCommentController(useCase *usecase.CommentUseCase, logger *logrus.Logger) *CommentController {
+	return &CommentController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *CommentController) Create(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.CreateCommentRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return model.ErrBadRequest
+	}
+	request.TaskId = ctx.Params("taskId")
+	request.Email = auth.Email
+	response, err := c.UseCase.Create(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to create comment : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(model.NewWebResponse(response, "Successfully created comment", fiber.StatusCreated, nil))
+}
+
+func (c *CommentController) List(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.SearchCommentRequest{
+		TaskId: ctx.Params("taskId"),
+		Email:  auth.Email,
+		Page:   ctx.QueryInt("page", 1),
+		Size:   ctx.QueryInt("size", 10),
+		Cursor: ctx.Query("cursor", ""),
+		Total:  ctx.Query("total", ""),
+	}
+	responses, paging, err := c.UseCase.Search(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to list comments : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(responses, "Comments fetched successfully", fiber.StatusOK, paging))
+}
+
+func (c *CommentController) Delete(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.GetCommentRequest{
+		ID:     ctx.Params("commentId"),
+		TaskId: ctx.Params("taskId"),
+		Email:  auth.Email,
+	}
+	if err := c.UseCase.Delete(ctx.UserContext(), request); err != nil {
+		c.Log.Warnf("Failed to delete comment : %+v", err)
+		return err
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
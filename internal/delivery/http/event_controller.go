@@ -0,0 +1,88 @@
+package http
+
+import (
+	"bufio"
+	"fmt"
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/middleware"
+	"github.com/abdisetiakawan/go-clean-arch/internal/realtime"
+	"github.com/abdisetiakawan/go-clean-arch/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// eventHeartbeat is the wait between comments sent to keep an idle stream
+// open through proxies.
+const eventHeartbeat = 15 * time.Second
+
+type EventController struct {
+	UseCase *usecase.EventUseCase
+	Log     *logrus.Logger
+}
+
+func NewEventController(useCase *usecase.EventUseCase, logger *logrus.Logger) *EventController {
+	return &EventController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// Stream sends the changes visible to the user as Server-Sent Events until
+// the client goes away. The Last-Event-ID header, or the last_event_id
+// query parameter, resumes after that event.
+func (c *EventController) Stream(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	lastEventId := ctx.Get("Last-Event-ID", ctx.Query("last_event_id"))
+	stream, err := c.UseCase.Subscribe(ctx.UserContext(), auth.Email, lastEventId)
+	if err != nil {
+		c.Log.Warnf("Failed to subscribe to events : %+v", err)
+		return err
+	}
+
+	ctx.Set(fiber.HeaderContentType, "text/event-stream")
+	ctx.Set(fiber.HeaderCacheControl, "no-cache")
+	ctx.Set(fiber.HeaderConnection, "keep-alive")
+	ctx.Set("X-Accel-Buffering", "no")
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer stream.Close()
+		heartbeat := time.NewTicker(eventHeartbeat)
+		defer heartbeat.Stop()
+
+		fmt.Fprint(w, "retry: 3000\n\n")
+		if stream.Reset {
+			fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+		}
+		// events published while the missed ones were read arrive twice
+		replayed := make(map[uint]bool, len(stream.Missed))
+		for _, event := range stream.Missed {
+			writeEvent(w, event)
+			replayed[event.ID] = true
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+		for {
+			select {
+			case event, ok := <-stream.Events:
+				if !ok {
+					return
+				}
+				if replayed[event.ID] {
+					continue
+				}
+				writeEvent(w, event)
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": ping\n\n")
+			}
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+	return nil
+}
+
+func writeEvent(w *bufio.Writer, event realtime.Event) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+}
@@ -0,0 +1,125 @@
+package http
+
+import (
+	"io"
+	"strings"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/middleware"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type ICalController struct {
+	UseCase *usecase.ICalUseCase
+	Log     *logrus.Logger
+}
+
+func NewICalController(useCase *usecase.ICalUseCase, logger *logrus.Logger) *ICalController {
+	return &ICalController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *ICalController) Feed(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	response, err := c.UseCase.Feed(ctx.UserContext(), auth.Email)
+	if err != nil {
+		c.Log.Warnf("Failed to get calendar feed : %+v", err)
+		return err
+	}
+	response.URL = feedURL(ctx, response.Token)
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully get calendar feed", fiber.StatusOK, nil))
+}
+
+func (c *ICalController) Regenerate(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	response, err := c.UseCase.RegenerateFeed(ctx.UserContext(), auth.Email)
+	if err != nil {
+		c.Log.Warnf("Failed to regenerate calendar feed : %+v", err)
+		return err
+	}
+	response.URL = feedURL(ctx, response.Token)
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully regenerated calendar feed", fiber.StatusOK, nil))
+}
+
+func (c *ICalController) Revoke(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	if err := c.UseCase.RevokeFeed(ctx.UserContext(), auth.Email); err != nil {
+		c.Log.Warnf("Failed to revoke calendar feed : %+v", err)
+		return err
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// Export serves the feed. It is reached without a login, the token in the
+// URL is the credential.
+func (c *ICalController) Export(ctx *fiber.Ctx) error {
+	request := &model.CalendarFeedRequest{
+		Token:     ctx.Params("token"),
+		ProjectId: uint(ctx.QueryInt("project_id", 0)),
+		Tags:      splitQuery(ctx.Query("tag", "")),
+		Statuses:  splitQuery(ctx.Query("status", "")),
+		Component: ctx.Query("type", ""),
+	}
+	data, err := c.UseCase.Export(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to export calendar : %+v", err)
+		return err
+	}
+	ctx.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	return ctx.Status(fiber.StatusOK).Send(data)
+}
+
+// Import accepts the .ics file either as the raw request body or as the
+// "file" field of a multipart form.
+func (c *ICalController) Import(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.ImportCalendarRequest{
+		Email:     auth.Email,
+		ProjectId: uint(ctx.QueryInt("project_id", 0)),
+		Timezone:  auth.Timezone,
+	}
+	data, err := uploadedFile(ctx)
+	if err != nil {
+		c.Log.Warnf("Failed to read uploaded file : %+v", err)
+		return model.ErrBadRequest
+	}
+	request.Data = data
+	response, err := c.UseCase.Import(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to import calendar : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully imported calendar", fiber.StatusOK, nil))
+}
+
+// uploadedFile returns the "file" field of a multipart form, or the raw
+// request body when there is none.
+func uploadedFile(ctx *fiber.Ctx) ([]byte, error) {
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		return ctx.Body(), nil
+	}
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+func feedURL(ctx *fiber.Ctx, token string) string {
+	return ctx.BaseURL() + "/ical/" + token + "/tasks.ics"
+}
+
+// splitQuery splits a comma separated query value such as
+// ?status=pending,in_progress.
+func splitQuery(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
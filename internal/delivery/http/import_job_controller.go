@@ -0,0 +1,74 @@
+package http
+
+import (
+	"fmt"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/middleware"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type ImportJobController struct {
+	UseCase *usecase.ImportJobUseCase
+	Log     *logrus.Logger
+}
+
+func NewImportJobController(useCase *usecase.ImportJobUseCase, logger *logrus.Logger) *ImportJobController {
+	return &ImportJobController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// Start queues the import of a Todoist, Trello or todo.txt export sent as
+// the raw request body or as the "file" field of a multipart form.
+func (c *ImportJobController) Start(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.StartImportRequest{
+		Email:      auth.Email,
+		ProjectId:  uint(ctx.QueryInt("project_id", 0)),
+		Timezone:   ctx.Query("tz", auth.Timezone),
+		Source:     ctx.Params("source"),
+		CreateTags: ctx.QueryBool("create_tags", true),
+	}
+	data, err := uploadedFile(ctx)
+	if err != nil {
+		c.Log.Warnf("Failed to read uploaded file : %+v", err)
+		return model.ErrBadRequest
+	}
+	request.Data = data
+
+	response, err := c.UseCase.Start(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to start import : %+v", err)
+		return err
+	}
+	ctx.Location(fmt.Sprintf("/api/imports/%d", response.ID))
+	return ctx.Status(fiber.StatusAccepted).JSON(model.NewWebResponse(response, "Import started", fiber.StatusAccepted, nil))
+}
+
+func (c *ImportJobController) List(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	responses, err := c.UseCase.List(ctx.UserContext(), auth.Email)
+	if err != nil {
+		c.Log.Warnf("Failed to list imports : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(responses, "Successfully get imports", fiber.StatusOK, nil))
+}
+
+func (c *ImportJobController) Get(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.GetImportJobRequest{
+		ID:    ctx.Params("jobId"),
+		Email: auth.Email,
+	}
+	response, err := c.UseCase.Get(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to get import : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully get import", fiber.StatusOK, nil))
+}
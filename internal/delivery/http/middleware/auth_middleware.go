@@ -49,13 +49,25 @@ func NewAuth(userUserCase *usecase.UserUseCase, viper *viper.Viper, cache *helpe
         }
 
         auth := &model.Auth{
-            Email: email,
+            Email:    email,
+            Timezone: sessionData["timezone"],
         }
         ctx.Locals("auth", auth)
         return ctx.Next()
     }
 }
 
+// QueryToken passes the access_token query parameter on as the
+// Authorization header, for clients such as EventSource that cannot set
+// headers.
+func QueryToken(ctx *fiber.Ctx) error {
+    token := ctx.Query("access_token")
+    if ctx.Get("Authorization") == "" && token != "" {
+        ctx.Request().Header.Set("Authorization", "Bearer "+token)
+    }
+    return ctx.Next()
+}
+
 func GetUser(ctx *fiber.Ctx) *model.Auth {
     return ctx.Locals("auth").(*model.Auth)
 }
\ No newline at end of file
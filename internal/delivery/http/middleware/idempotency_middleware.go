@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// idempotencyTTL is how long a response is replayed for its key.
+	idempotencyTTL = 24 * time.Hour
+	// idempotencyLockTTL bounds how long a request holding a key may run
+	// before a retry is allowed to start over.
+	idempotencyLockTTL = 5 * time.Minute
+	idempotencyKeyMax  = 255
+)
+
+// idempotentResponse is what is stored for an Idempotency-Key. Until the
+// first request finishes only the fingerprint is set.
+type idempotentResponse struct {
+	Fingerprint string `json:"fingerprint"`
+	Done        bool   `json:"done"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Location    string `json:"location,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// NewIdempotency makes POST, PUT, PATCH and DELETE requests carrying an
+// Idempotency-Key header safe to retry. The first response for a key is
+// kept per user, method and path and replayed to retries with the same
+// query string and body. Reusing a key for another request is rejected,
+// and so is a retry while the first request is still running. Server
+// errors are not kept, so those requests may be retried.
+func NewIdempotency(cache *helper.CacheHelper, log *logrus.Logger) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		key := ctx.Get("Idempotency-Key")
+		if key == "" || ctx.Method() == fiber.MethodGet || ctx.Method() == fiber.MethodHead || ctx.Method() == fiber.MethodOptions {
+			return ctx.Next()
+		}
+		if len(key) > idempotencyKeyMax {
+			return model.NewApiError(fiber.StatusBadRequest, "Idempotency-Key is too long")
+		}
+
+		auth := GetUser(ctx)
+		cacheKey := "idempotency:" + auth.Email + ":" + ctx.Method() + ":" + ctx.Path() + ":" + key
+		// imports take their options from the query, so it is part of the request
+		hash := sha256.New()
+		hash.Write(ctx.Request().URI().QueryString())
+		hash.Write([]byte{0})
+		hash.Write(ctx.Body())
+		fingerprint := hex.EncodeToString(hash.Sum(nil))
+
+		pending, _ := json.Marshal(idempotentResponse{Fingerprint: fingerprint})
+		acquired, err := cache.SetNX(ctx.Context(), cacheKey, pending, idempotencyLockTTL)
+		if err != nil {
+			log.WithError(err).Warn("error reserve idempotency key")
+			return ctx.Next()
+		}
+		if !acquired {
+			return replay(ctx, cache, cacheKey, fingerprint)
+		}
+
+		if err := ctx.Next(); err != nil {
+			// run the error handler now so the error response is kept too
+			if err := ctx.App().Config().ErrorHandler(ctx, err); err != nil {
+				cache.Delete(ctx.Context(), cacheKey)
+				return err
+			}
+		}
+		status := ctx.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			cache.Delete(ctx.Context(), cacheKey)
+			return nil
+		}
+		stored, _ := json.Marshal(idempotentResponse{
+			Fingerprint: fingerprint,
+			Done:        true,
+			Status:      status,
+			ContentType: string(ctx.Response().Header.ContentType()),
+			Location:    string(ctx.Response().Header.Peek(fiber.HeaderLocation)),
+			Body:        ctx.Response().Body(),
+		})
+		if err := cache.Set(ctx.Context(), cacheKey, stored, idempotencyTTL); err != nil {
+			log.WithError(err).Warn("error store idempotent response")
+		}
+		return nil
+	}
+}
+
+// replay answers a request whose key is already taken.
+func replay(ctx *fiber.Ctx, cache *helper.CacheHelper, cacheKey string, fingerprint string) error {
+	response := new(idempotentResponse)
+	if err := cache.GetAndUnmarshal(ctx.Context(), cacheKey, response); err != nil {
+		// the first request failed and released the key in the meantime
+		return model.NewApiError(fiber.StatusConflict, "A request with this Idempotency-Key is in progress, retry later")
+	}
+	if response.Fingerprint != fingerprint {
+		return model.NewApiError(fiber.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+	}
+	if !response.Done {
+		return model.NewApiError(fiber.StatusConflict, "A request with this Idempotency-Key is in progress, retry later")
+	}
+
+	ctx.Set("Idempotent-Replayed", "true")
+	if response.ContentType != "" {
+		ctx.Set(fiber.HeaderContentType, response.ContentType)
+	}
+	if response.Location != "" {
+		ctx.Set(fiber.HeaderLocation, response.Location)
+	}
+	return ctx.Status(response.Status).Send(response.Body)
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/middleware"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type NotificationController struct {
+	UseCase *usecase.NotificationUseCase
+	Log     *logrus.Logger
+}
+
+func NewNotificationController(useCase *usecase.NotificationUseCase, logger *logrus.Logger) *NotificationController {
+	return &NotificationController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *NotificationController) List(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.SearchNotificationRequest{
+		Email:  auth.Email,
+		Unread: ctx.QueryBool("unread", false),
+		Type:   ctx.Query("type", ""),
+		Page:   ctx.QueryInt("page", 1),
+		Size:   ctx.QueryInt("size", 20),
+		Cursor: ctx.Query("cursor", ""),
+		Total:  ctx.Query("total", ""),
+	}
+	responses, paging, err := c.UseCase.Search(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to list notifications : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(responses, "Successfully get notifications", fiber.StatusOK, paging))
+}
+
+func (c *NotificationController) Unread(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	response, err := c.UseCase.Unread(ctx.UserContext(), auth.Email)
+	if err != nil {
+		c.Log.Warnf("Failed to count unread notifications : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully get unread notifications", fiber.StatusOK, nil))
+}
+
+func (c *NotificationController) Read(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.ReadNotificationRequest{
+		ID:    ctx.Params("notificationId"),
+		Email: auth.Email,
+	}
+	response, err := c.UseCase.Read(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to read notification : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully read notification", fiber.StatusOK, nil))
+}
+
+func (c *NotificationController) ReadAll(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.ReadAllNotificationsRequest{
+		Email: auth.Email,
+		Type:  ctx.Query("type", ""),
+	}
+	response, err := c.UseCase.ReadAll(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to read notifications : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully read notifications", fiber.StatusOK, nil))
+}
+
+func (c *NotificationController) Settings(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	response, err := c.UseCase.Settings(ctx.UserContext(), auth.Email)
+	if err != nil {
+		c.Log.Warnf("Failed to get notification settings : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully get notification settings", fiber.StatusOK, nil))
+}
+
+func (c *NotificationController) UpdateSettings(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.UpdateNotificationSettingsRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return model.ErrBadRequest
+	}
+	request.Email = auth.Email
+	response, err := c.UseCase.UpdateSettings(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to update notification settings : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully updated notification settings", fiber.StatusOK, nil))
+}
@@ -0,0 +1,196 @@
+package http
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/middleware"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type ProjectController struct {
+	UseCase *usecase.ProjectUseCase
+	Log     *logrus.Logger
+}
+
+func NewProjectController(useCase *usecase.ProjectUseCase, logger *logrus.Logger) *ProjectController {
+	return &ProjectController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *ProjectController) Create(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.CreateProjectRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return model.ErrBadRequest
+	}
+	request.Email = auth.Email
+	response, err := c.UseCase.Create(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to create project : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(model.NewWebResponse(response, "Successfully created project", fiber.StatusCreated, nil))
+}
+
+func (c *ProjectController) List(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.SearchProjectRequest{
+		Email:  auth.Email,
+		Page:   ctx.QueryInt("page", 1),
+		Size:   ctx.QueryInt("size", 10),
+		Cursor: ctx.Query("cursor", ""),
+		Total:  ctx.Query("total", ""),
+	}
+	responses, paging, err := c.UseCase.Search(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to list projects : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(responses, "Projects fetched successfully", fiber.StatusOK, paging))
+}
+
+func (c *ProjectController) Get(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.GetProjectRequest{
+		ID:    ctx.Params("projectId"),
+		Email: auth.Email,
+	}
+	response, err := c.UseCase.Get(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to get project : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully get project", fiber.StatusOK, nil))
+}
+
+func (c *ProjectController) Update(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.UpdateProjectRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return model.ErrBadRequest
+	}
+	request.Email = auth.Email
+	request.ID = ctx.Params("projectId")
+	response, err := c.UseCase.Update(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to update project : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully updated project", fiber.StatusOK, nil))
+}
+
+func (c *ProjectController) Delete(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.GetProjectRequest{
+		ID:    ctx.Params("projectId"),
+		Email: auth.Email,
+	}
+	if err := c.UseCase.Delete(ctx.UserContext(), request); err != nil {
+		c.Log.Warnf("Failed to delete project : %+v", err)
+		return err
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+func (c *ProjectController) ListMembers(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.GetProjectRequest{
+		ID:    ctx.Params("projectId"),
+		Email: auth.Email,
+	}
+	responses, err := c.UseCase.Members(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to list project members : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(responses, "Project members fetched successfully", fiber.StatusOK, nil))
+}
+
+func (c *ProjectController) UpdateMember(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.UpdateProjectMemberRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return model.ErrBadRequest
+	}
+	request.ProjectId = ctx.Params("projectId")
+	request.Email = auth.Email
+	request.MemberEmail = ctx.Params("email")
+	response, err := c.UseCase.UpdateMember(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to update project member : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully updated project member", fiber.StatusOK, nil))
+}
+
+func (c *ProjectController) RemoveMember(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.GetProjectMemberRequest{
+		ProjectId:   ctx.Params("projectId"),
+		Email:       auth.Email,
+		MemberEmail: ctx.Params("email"),
+	}
+	if err := c.UseCase.RemoveMember(ctx.UserContext(), request); err != nil {
+		c.Log.Warnf("Failed to remove project member : %+v", err)
+		return err
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+func (c *ProjectController) Invite(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.CreateInvitationRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return model.ErrBadRequest
+	}
+	request.ProjectId = ctx.Params("projectId")
+	request.Email = auth.Email
+	response, err := c.UseCase.Invite(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to invite project member : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(model.NewWebResponse(response, "Successfully created invitation", fiber.StatusCreated, nil))
+}
+
+func (c *ProjectController) ListInvitations(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.SearchInvitationRequest{Email: auth.Email}
+	responses, err := c.UseCase.Invitations(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to list invitations : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(responses, "Invitations fetched successfully", fiber.StatusOK, nil))
+}
+
+func (c *ProjectController) AcceptInvitation(ctx *fiber.Ctx) error {
+	return c.respondInvitation(ctx, true, "Successfully accepted invitation")
+}
+
+func (c *ProjectController) DeclineInvitation(ctx *fiber.Ctx) error {
+	return c.respondInvitation(ctx, false, "Successfully declined invitation")
+}
+
+func (c *ProjectController) respondInvitation(ctx *fiber.Ctx, accept bool, message string) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.RespondInvitationRequest{
+		ID:     ctx.Params("invitationId"),
+		Email:  auth.Email,
+		Accept: accept,
+	}
+	response, err := c.UseCase.RespondInvitation(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to respond invitation : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, message, fiber.StatusOK, nil))
+}
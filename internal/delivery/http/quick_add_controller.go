@@ -0,0 +1,41 @@
+package http
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/middleware"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type QuickAddController struct {
+	UseCase *usecase.QuickAddUseCase
+	Log     *logrus.Logger
+}
+
+func NewQuickAddController(useCase *usecase.QuickAddUseCase, logger *logrus.Logger) *QuickAddController {
+	return &QuickAddController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *QuickAddController) Create(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.QuickAddTaskRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return model.ErrBadRequest
+	}
+	request.Email = auth.Email
+	request.Timezone = auth.Timezone
+	response, err := c.UseCase.Create(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to quick add task : %+v", err)
+		return err
+	}
+	if request.DryRun {
+		return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully parsed task", fiber.StatusOK, nil))
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(model.NewWebResponse(response, "Successfully created task", fiber.StatusCreated, nil))
+}
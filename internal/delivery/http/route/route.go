@@ -2,6 +2,7 @@ package route
 
 import (
 	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http"
+	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/middleware"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -12,7 +13,25 @@ type RouteConfig struct {
 	TaskController 	*http.TaskController
 	TagsController *http.TagsController
 	TaskTagController *http.TaskTagController
+	ProjectController *http.ProjectController
+	TaskAssigneeController *http.TaskAssigneeController
+	CommentController *http.CommentController
+	SearchController *http.SearchController
+	SavedSearchController *http.SavedSearchController
+	ICalController *http.ICalController
+	TransferController *http.TransferController
+	ImportJobController *http.ImportJobController
+	TaskBatchController *http.TaskBatchController
+	TaskTemplateController *http.TaskTemplateController
+	QuickAddController *http.QuickAddController
+	StatsController *http.StatsController
+	ActivityController *http.ActivityController
+	EventController *http.EventController
+	WebhookController *http.WebhookController
+	NotificationController *http.NotificationController
+	SyncController *http.SyncController
 	AuthMiddleware    fiber.Handler
+	IdempotencyMiddleware fiber.Handler
 }
 
 func (c *RouteConfig) Setup() {
@@ -23,16 +42,31 @@ func (c *RouteConfig) Setup() {
 func (c *RouteConfig) SetupAuthRoute() {
 	c.App.Post("/api/users", c.UserController.Register)
 	c.App.Post("/api/users/_login", c.UserController.Login)
+	c.App.Get("/ical/:token/tasks.ics", c.ICalController.Export)
+	c.App.Get("/api/events", middleware.QueryToken, c.AuthMiddleware, c.EventController.Stream)
 }
 
 func (c *RouteConfig) SetupUserRoute() {
 	c.App.Use(c.AuthMiddleware)
-	c.App.Patch("/api/users/_current", c.UserController.Update)
+	c.App.Use(c.IdempotencyMiddleware)
+	c.App.Put("/api/users/_current", c.UserController.Update)
+	c.App.Patch("/api/users/_current", c.UserController.Patch)
 	c.App.Get("/api/users/_current", c.UserController.Current)
 
 	c.App.Get("/api/tasks", c.TaskController.List)
+	c.App.Get("/api/tasks/_assigned", c.TaskController.Assigned)
+	c.App.Get("/api/tasks/_calendar", c.TaskController.Calendar)
+	c.App.Get("/api/tasks/_search", c.SearchController.Search)
+	c.App.Get("/api/tasks/_export", c.TransferController.Export)
+	c.App.Post("/api/tasks/_import", c.TransferController.Import)
+	c.App.Post("/api/tasks/_import/ical", c.ICalController.Import)
+	c.App.Post("/api/tasks/_import/:source", c.ImportJobController.Start)
 	c.App.Post("/api/tasks", c.TaskController.Create)
+	c.App.Post("/api/tasks/_batch", c.TaskBatchController.Apply)
+	c.App.Post("/api/tasks/_quick", c.QuickAddController.Create)
 	c.App.Put("/api/tasks/:taskId", c.TaskController.Update)
+	c.App.Patch("/api/tasks/:taskId", c.TaskController.Patch)
+	c.App.Post("/api/tasks/:taskId/_move", c.TaskController.Move)
 	c.App.Get("/api/tasks/:taskId", c.TaskController.Get)
 	c.App.Delete("/api/tasks/:taskId", c.TaskController.Delete)
 
@@ -40,10 +74,78 @@ func (c *RouteConfig) SetupUserRoute() {
 	c.App.Get("/api/tags", c.TagsController.List)
 	c.App.Get("/api/tags/:tagId", c.TagsController.Get)
 	c.App.Put("/api/tags/:tagId", c.TagsController.Update)
+	c.App.Patch("/api/tags/:tagId", c.TagsController.Patch)
 	c.App.Delete("/api/tags/:tagId", c.TagsController.Delete)
 
 	c.App.Post("/api/tasks/:taskId/tags", c.TaskTagController.Create)
 	c.App.Get("/api/taskswithtags", c.TaskTagController.List)
 	c.App.Get("/api/tags/:tagId/tasks", c.TaskTagController.ListByTagId)
 	c.App.Delete("/api/tasks/:taskId/tags/:tagId", c.TaskTagController.Delete)
+
+	c.App.Put("/api/tasks/:taskId/assignees", c.TaskAssigneeController.Update)
+	c.App.Post("/api/tasks/:taskId/watchers", c.TaskAssigneeController.Watch)
+	c.App.Delete("/api/tasks/:taskId/watchers/:email", c.TaskAssigneeController.Unwatch)
+
+	c.App.Post("/api/tasks/:taskId/comments", c.CommentController.Create)
+	c.App.Get("/api/tasks/:taskId/comments", c.CommentController.List)
+	c.App.Delete("/api/tasks/:taskId/comments/:commentId", c.CommentController.Delete)
+
+	c.App.Post("/api/projects", c.ProjectController.Create)
+	c.App.Get("/api/projects", c.ProjectController.List)
+	c.App.Get("/api/projects/:projectId", c.ProjectController.Get)
+	c.App.Put("/api/projects/:projectId", c.ProjectController.Update)
+	c.App.Delete("/api/projects/:projectId", c.ProjectController.Delete)
+	c.App.Get("/api/projects/:projectId/members", c.ProjectController.ListMembers)
+	c.App.Put("/api/projects/:projectId/members/:email", c.ProjectController.UpdateMember)
+	c.App.Delete("/api/projects/:projectId/members/:email", c.ProjectController.RemoveMember)
+	c.App.Post("/api/projects/:projectId/invitations", c.ProjectController.Invite)
+	c.App.Get("/api/projects/:projectId/board", c.TaskController.Board)
+
+	c.App.Post("/api/lists", c.SavedSearchController.Create)
+	c.App.Get("/api/lists", c.SavedSearchController.List)
+	c.App.Get("/api/lists/:listId", c.SavedSearchController.Get)
+	c.App.Put("/api/lists/:listId", c.SavedSearchController.Update)
+	c.App.Delete("/api/lists/:listId", c.SavedSearchController.Delete)
+	c.App.Post("/api/lists/:listId/_pin", c.SavedSearchController.Pin)
+	c.App.Post("/api/lists/:listId/_unpin", c.SavedSearchController.Unpin)
+	c.App.Get("/api/lists/:listId/tasks", c.SavedSearchController.Tasks)
+
+	c.App.Post("/api/templates", c.TaskTemplateController.Create)
+	c.App.Get("/api/templates", c.TaskTemplateController.List)
+	c.App.Get("/api/templates/:templateId", c.TaskTemplateController.Get)
+	c.App.Put("/api/templates/:templateId", c.TaskTemplateController.Update)
+	c.App.Delete("/api/templates/:templateId", c.TaskTemplateController.Delete)
+	c.App.Post("/api/templates/:templateId/instantiate", c.TaskTemplateController.Instantiate)
+
+	c.App.Get("/api/stats", c.StatsController.Get)
+	c.App.Get("/api/activities", c.ActivityController.List)
+
+	c.App.Post("/api/webhooks", c.WebhookController.Create)
+	c.App.Get("/api/webhooks", c.WebhookController.List)
+	c.App.Get("/api/webhooks/:webhookId", c.WebhookController.Get)
+	c.App.Put("/api/webhooks/:webhookId", c.WebhookController.Update)
+	c.App.Delete("/api/webhooks/:webhookId", c.WebhookController.Delete)
+	c.App.Get("/api/webhooks/:webhookId/deliveries", c.WebhookController.Deliveries)
+	c.App.Post("/api/webhooks/:webhookId/deliveries/:deliveryId/_redeliver", c.WebhookController.Redeliver)
+
+	c.App.Get("/api/notifications", c.NotificationController.List)
+	c.App.Get("/api/notifications/_unread", c.NotificationController.Unread)
+	c.App.Post("/api/notifications/_read_all", c.NotificationController.ReadAll)
+	c.App.Get("/api/notifications/_settings", c.NotificationController.Settings)
+	c.App.Put("/api/notifications/_settings", c.NotificationController.UpdateSettings)
+	c.App.Post("/api/notifications/:notificationId/_read", c.NotificationController.Read)
+
+	c.App.Get("/api/sync", c.SyncController.Pull)
+	c.App.Post("/api/sync", c.SyncController.Push)
+
+	c.App.Get("/api/imports", c.ImportJobController.List)
+	c.App.Get("/api/imports/:jobId", c.ImportJobController.Get)
+
+	c.App.Get("/api/calendar/feed", c.ICalController.Feed)
+	c.App.Post("/api/calendar/feed/_regenerate", c.ICalController.Regenerate)
+	c.App.Delete("/api/calendar/feed", c.ICalController.Revoke)
+
+	c.App.Get("/api/invitations", c.ProjectController.ListInvitations)
+	c.App.Post("/api/invitations/:invitationId/_accept", c.ProjectController.AcceptInvitation)
+	c.App.Post("/api/invitations/:invitationId/_decline", c.ProjectController.DeclineInvitation)
 }
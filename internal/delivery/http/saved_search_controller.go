@@ -0,0 +1,134 @@
+package http
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/middleware"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type SavedSearchController struct {
+	UseCase *usecase.SavedSearchUseCase
+	Log     *logrus.Logger
+}
+
+func NewSavedSearchController(useCase *usecase.SavedSearchUseCase, logger *logrus.Logger) *SavedSearchController {
+	return &SavedSearchController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *SavedSearchController) Create(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.CreateSavedSearchRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return model.ErrBadRequest
+	}
+	request.Email = auth.Email
+	response, err := c.UseCase.Create(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to create list : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(model.NewWebResponse(response, "Successfully created list", fiber.StatusCreated, nil))
+}
+
+func (c *SavedSearchController) List(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	responses, err := c.UseCase.List(ctx.UserContext(), auth.Email)
+	if err != nil {
+		c.Log.Warnf("Failed to list lists : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(responses, "Lists fetched successfully", fiber.StatusOK, nil))
+}
+
+func (c *SavedSearchController) Get(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.GetSavedSearchRequest{
+		ID:    ctx.Params("listId"),
+		Email: auth.Email,
+	}
+	response, err := c.UseCase.Get(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to get list : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully get list", fiber.StatusOK, nil))
+}
+
+func (c *SavedSearchController) Update(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.UpdateSavedSearchRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return model.ErrBadRequest
+	}
+	request.ID = ctx.Params("listId")
+	request.Email = auth.Email
+	response, err := c.UseCase.Update(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to update list : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully updated list", fiber.StatusOK, nil))
+}
+
+func (c *SavedSearchController) Delete(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.GetSavedSearchRequest{
+		ID:    ctx.Params("listId"),
+		Email: auth.Email,
+	}
+	if err := c.UseCase.Delete(ctx.UserContext(), request); err != nil {
+		c.Log.Warnf("Failed to delete list : %+v", err)
+		return err
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+func (c *SavedSearchController) Pin(ctx *fiber.Ctx) error {
+	return c.pin(ctx, true, "Successfully pinned list")
+}
+
+func (c *SavedSearchController) Unpin(ctx *fiber.Ctx) error {
+	return c.pin(ctx, false, "Successfully unpinned list")
+}
+
+func (c *SavedSearchController) pin(ctx *fiber.Ctx, pinned bool, message string) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.PinSavedSearchRequest{
+		ID:     ctx.Params("listId"),
+		Email:  auth.Email,
+		Pinned: pinned,
+	}
+	response, err := c.UseCase.Pin(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to pin list : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, message, fiber.StatusOK, nil))
+}
+
+func (c *SavedSearchController) Tasks(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.SavedSearchTasksRequest{
+		ID:       ctx.Params("listId"),
+		Email:    auth.Email,
+		Page:     ctx.QueryInt("page", 1),
+		Size:     ctx.QueryInt("size", 10),
+		Cursor:   ctx.Query("cursor", ""),
+		Total:    ctx.Query("total", ""),
+		Timezone: ctx.Query("tz", auth.Timezone),
+	}
+	responses, paging, err := c.UseCase.Tasks(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to list tasks : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(responses, "Tasks fetched successfully", fiber.StatusOK, paging))
+}
@@ -0,0 +1,40 @@
+package http
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/middleware"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type SearchController struct {
+	UseCase *usecase.SearchUseCase
+	Log     *logrus.Logger
+}
+
+func NewSearchController(useCase *usecase.SearchUseCase, logger *logrus.Logger) *SearchController {
+	return &SearchController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *SearchController) Search(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.FullTextSearchRequest{
+		Email:     auth.Email,
+		ProjectId: uint(ctx.QueryInt("project_id", 0)),
+		Query:     ctx.Query("q", ""),
+		Page:      ctx.QueryInt("page", 1),
+		Size:      ctx.QueryInt("size", 10),
+	}
+	responses, total, err := c.UseCase.Search(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to search tasks : %+v", err)
+		return err
+	}
+	paging := model.NewPageMetadata(request.Page, request.Size, total)
+
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(responses, "Tasks fetched successfully", fiber.StatusOK, paging))
+}
@@ -0,0 +1,38 @@
+package http
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/middleware"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type StatsController struct {
+	UseCase *usecase.StatsUseCase
+	Log     *logrus.Logger
+}
+
+func NewStatsController(useCase *usecase.StatsUseCase, logger *logrus.Logger) *StatsController {
+	return &StatsController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *StatsController) Get(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.GetStatsRequest{
+		Email:     auth.Email,
+		ProjectId: uint(ctx.QueryInt("project_id", 0)),
+		From:      ctx.Query("from", ""),
+		To:        ctx.Query("to", ""),
+		Timezone:  ctx.Query("tz", auth.Timezone),
+	}
+	response, err := c.UseCase.Get(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to get stats : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully get stats", fiber.StatusOK, nil))
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/middleware"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type SyncController struct {
+	UseCase *usecase.SyncUseCase
+	Log     *logrus.Logger
+}
+
+func NewSyncController(useCase *usecase.SyncUseCase, logger *logrus.Logger) *SyncController {
+	return &SyncController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *SyncController) Pull(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.SyncPullRequest{
+		Email: auth.Email,
+		Since: ctx.Query("since", ""),
+		Limit: ctx.QueryInt("limit", 500),
+	}
+	response, err := c.UseCase.Pull(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to pull changes : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully get changes", fiber.StatusOK, nil))
+}
+
+func (c *SyncController) Push(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.SyncPushRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return model.ErrBadRequest
+	}
+	request.Email = auth.Email
+	response, err := c.UseCase.Push(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to push changes : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully applied changes", fiber.StatusOK, nil))
+}
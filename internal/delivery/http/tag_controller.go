@@ -1,9 +1,8 @@
 package http
 
 import (
-	"math"
-
 	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/middleware"
+	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
 	"github.com/abdisetiakawan/go-clean-arch/internal/model"
 	"github.com/abdisetiakawan/go-clean-arch/internal/usecase"
 	"github.com/gofiber/fiber/v2"
@@ -44,19 +43,15 @@ func (c *TagsController) List(ctx *fiber.Ctx) error {
 		Name: ctx.Query("name", ""),
 		Page: ctx.QueryInt("page", 1),
 		Size: ctx.QueryInt("size", 10),
+		Cursor: ctx.Query("cursor", ""),
+		Total: ctx.Query("total", ""),
 	}
 
-	responses, total, err := c.UseCase.Search(ctx.UserContext(), request)
+	responses, paging, err := c.UseCase.Search(ctx.UserContext(), request)
 	if err != nil {
 		c.Log.Warnf("failed to list tag: %+v", err)
 		return err
 	}
-	paging := &model.PageMetadata{
-		Page: request.Page,
-		Size: request.Size,
-		TotalItem: total,
-		TotalPage: int64(math.Ceil(float64(total) / float64(request.Size))),
-	}
 
 	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(responses, "Tags fetched successfully", fiber.StatusOK, paging))
 }
@@ -73,6 +68,10 @@ func (c *TagsController) Get(ctx *fiber.Ctx) error {
 		c.Log.Warnf("Failed to get tag : %+v", err)
 		return err
 	}
+	ctx.Set(fiber.HeaderETag, helper.ETag(response.Version))
+	if helper.MatchETag(ctx.Get(fiber.HeaderIfNoneMatch), response.Version, true) {
+		return ctx.SendStatus(fiber.StatusNotModified)
+	}
 	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully get tag", fiber.StatusOK, nil))
 }
 
@@ -85,11 +84,32 @@ func (c *TagsController) Update(ctx *fiber.Ctx) error {
 	}
 	request.Email = auth.Email
 	request.ID = ctx.Params("tagId")
+	request.IfMatch = ctx.Get(fiber.HeaderIfMatch)
 	response, err := c.UseCase.Update(ctx.UserContext(), request)
 	if err != nil {
 		c.Log.Warnf("Failed to update tag : %+v", err)
 		return err
 	}
+	ctx.Set(fiber.HeaderETag, helper.ETag(response.Version))
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully updated tag", fiber.StatusOK, nil))
+}
+
+func (c *TagsController) Patch(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.PatchTagRequest)
+	if err := parseMergePatch(ctx, request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return err
+	}
+	request.Email = auth.Email
+	request.ID = ctx.Params("tagId")
+	request.IfMatch = ctx.Get(fiber.HeaderIfMatch)
+	response, err := c.UseCase.Patch(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to update tag : %+v", err)
+		return err
+	}
+	ctx.Set(fiber.HeaderETag, helper.ETag(response.Version))
 	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully updated tag", fiber.StatusOK, nil))
 }
 
@@ -99,6 +119,7 @@ func (c *TagsController) Delete(ctx *fiber.Ctx) error {
 	request := &model.GetTagRequest{
 		ID: ctx.Params("tagId"),
 		Email: auth.Email,
+		IfMatch: ctx.Get(fiber.HeaderIfMatch),
 	}
 
 	if err := c.UseCase.Delete(ctx.UserContext(), request); err != nil {
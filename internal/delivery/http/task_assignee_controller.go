@@ -0,0 +1,74 @@
+package http
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/middleware"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type TaskAssigneeController struct {
+	UseCase *usecase.TaskAssigneeUseCase
+	Log     *logrus.Logger
+}
+
+func NewTaskAssigneeController(useCase *usecase.TaskAssigneeUseCase, logger *logrus.Logger) *TaskAssigneeController {
+	return &TaskAssigneeController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *TaskAssigneeController) Update(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.UpdateTaskAssigneesRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return model.ErrBadRequest
+	}
+	request.ID = ctx.Params("taskId")
+	request.Email = auth.Email
+	response, err := c.UseCase.Update(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to update task assignees : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully updated task assignees", fiber.StatusOK, nil))
+}
+
+func (c *TaskAssigneeController) Watch(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.TaskWatcherRequest)
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(request); err != nil {
+			c.Log.Warnf("Failed to parse request body : %+v", err)
+			return model.ErrBadRequest
+		}
+	}
+	request.ID = ctx.Params("taskId")
+	request.Email = auth.Email
+	if request.WatcherEmail == "" {
+		request.WatcherEmail = auth.Email
+	}
+	response, err := c.UseCase.Watch(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to watch task : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(model.NewWebResponse(response, "Successfully watched task", fiber.StatusCreated, nil))
+}
+
+func (c *TaskAssigneeController) Unwatch(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.TaskWatcherRequest{
+		ID:           ctx.Params("taskId"),
+		Email:        auth.Email,
+		WatcherEmail: ctx.Params("email"),
+	}
+	if err := c.UseCase.Unwatch(ctx.UserContext(), request); err != nil {
+		c.Log.Warnf("Failed to unwatch task : %+v", err)
+		return err
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
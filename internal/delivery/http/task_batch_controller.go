@@ -0,0 +1,38 @@
+package http
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/middleware"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type TaskBatchController struct {
+	UseCase *usecase.TaskBatchUseCase
+	Log     *logrus.Logger
+}
+
+func NewTaskBatchController(useCase *usecase.TaskBatchUseCase, logger *logrus.Logger) *TaskBatchController {
+	return &TaskBatchController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *TaskBatchController) Apply(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.BatchTaskRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return model.ErrBadRequest
+	}
+	request.Email = auth.Email
+	request.Timezone = ctx.Query("tz", auth.Timezone)
+	response, err := c.UseCase.Apply(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to apply batch : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully applied batch", fiber.StatusOK, nil))
+}
@@ -1,9 +1,11 @@
 package http
 
 import (
-	"math"
+	"strconv"
+	"strings"
 
 	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/middleware"
+	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
 
@@ -27,23 +29,75 @@ func (c *TaskController) List(ctx *fiber.Ctx) error {
 	auth := middleware.GetUser(ctx)
 	request := &model.SearchTaskRequest{
 		Email: auth.Email,
+		ProjectId: uint(ctx.QueryInt("project_id", 0)),
 		Title: ctx.Query("title", ""),
 		Description: ctx.Query("description", ""),
 		Status: ctx.Query("status", ""),
+		Assignee: ctx.Query("assignee", ""),
+		Query: ctx.Query("q", ""),
+		Sort: ctx.Query("sort", ""),
+		DueFrom: ctx.Query("due_from", ""),
+		DueTo: ctx.Query("due_to", ""),
+		Overdue: ctx.QueryBool("overdue", false),
+		CreatedAfter: ctx.Query("created_after", ""),
+		UpdatedAfter: ctx.Query("updated_after", ""),
+		Timezone: ctx.Query("tz", auth.Timezone),
+		Include: ctx.Query("include", ""),
 		Page: ctx.QueryInt("page", 1),
 		Size: ctx.QueryInt("size", 10),
+		Cursor: ctx.Query("cursor", ""),
+		Total: ctx.Query("total", ""),
+	}
+	if request.Assignee == "me" {
+		request.Assignee = auth.Email
+	}
+
+	return c.search(ctx, request)
+}
+
+// Assigned lists every accessible task assigned to the current user.
+func (c *TaskController) Assigned(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.SearchTaskRequest{
+		Email: auth.Email,
+		Status: ctx.Query("status", ""),
+		Assignee: auth.Email,
+		Timezone: auth.Timezone,
+		Include: ctx.Query("include", ""),
+		Page: ctx.QueryInt("page", 1),
+		Size: ctx.QueryInt("size", 10),
+		Cursor: ctx.Query("cursor", ""),
+		Total: ctx.Query("total", ""),
 	}
 
-	responses, total, err := c.UseCase.Search(ctx.UserContext(), request)
+	return c.search(ctx, request)
+}
+
+// Calendar groups the tasks due in a month or week by day.
+func (c *TaskController) Calendar(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.TaskCalendarRequest{
+		Email: auth.Email,
+		ProjectId: uint(ctx.QueryInt("project_id", 0)),
+		Month: ctx.Query("month", ""),
+		Week: ctx.Query("week", ""),
+		Query: ctx.Query("q", ""),
+		Timezone: ctx.Query("tz", auth.Timezone),
+		Limit: ctx.QueryInt("limit", 10),
+	}
+	response, err := c.UseCase.Calendar(ctx.UserContext(), request)
 	if err != nil {
-		c.Log.Warnf("Failed to list tasks : %+v", err)
+		c.Log.Warnf("Failed to get calendar : %+v", err)
 		return err
 	}
-	paging := &model.PageMetadata{
-		Page: request.Page,
-		Size: request.Size,
-		TotalItem: total,
-		TotalPage: int64(math.Ceil(float64(total) / float64(request.Size))),
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Calendar fetched successfully", fiber.StatusOK, nil))
+}
+
+func (c *TaskController) search(ctx *fiber.Ctx, request *model.SearchTaskRequest) error {
+	responses, paging, err := c.UseCase.Search(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to list tasks : %+v", err)
+		return err
 	}
 
 	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(responses, "Tasks fetched successfully", fiber.StatusOK, paging))
@@ -74,19 +128,56 @@ func (c *TaskController) Update(ctx *fiber.Ctx) error {
 	}
 	request.Email = auth.Email
 	request.ID = ctx.Params("taskId")
+	request.IfMatch = ctx.Get(fiber.HeaderIfMatch)
 	response, err := c.UseCase.Update(ctx.UserContext(), request)
 	if err != nil {
 		c.Log.Warnf("Failed to update task : %+v", err)
 		return err
 	}
+	ctx.Set(fiber.HeaderETag, helper.ETag(response.Version))
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully updated task", fiber.StatusOK, nil))
+}
+
+// Patch takes a JSON merge patch, sent as application/merge-patch+json or
+// plain application/json.
+func (c *TaskController) Patch(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.PatchTaskRequest)
+	if err := parseMergePatch(ctx, request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return err
+	}
+	request.Email = auth.Email
+	request.ID = ctx.Params("taskId")
+	request.IfMatch = ctx.Get(fiber.HeaderIfMatch)
+	response, err := c.UseCase.Patch(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to update task : %+v", err)
+		return err
+	}
+	ctx.Set(fiber.HeaderETag, helper.ETag(response.Version))
 	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully updated task", fiber.StatusOK, nil))
 }
 
+// parseMergePatch reads a JSON merge patch. Other bodies cannot tell a
+// missing field from a null one and are refused.
+func parseMergePatch(ctx *fiber.Ctx, out any) error {
+	mediaType, _, _ := strings.Cut(ctx.Get(fiber.HeaderContentType), ";")
+	if !strings.HasSuffix(strings.ToLower(strings.TrimSpace(mediaType)), "json") {
+		return fiber.ErrUnsupportedMediaType
+	}
+	if err := ctx.BodyParser(out); err != nil {
+		return fiber.ErrBadRequest
+	}
+	return nil
+}
+
 func (c *TaskController) Get(ctx *fiber.Ctx) error {
 	auth := middleware.GetUser(ctx)
 	request := &model.GetTaskRequest{
 		ID: ctx.Params("taskId"),
 		Email: auth.Email,
+		Include: ctx.Query("include", ""),
 	}
 
 	response, err := c.UseCase.Get(ctx.UserContext(), request)
@@ -94,6 +185,19 @@ func (c *TaskController) Get(ctx *fiber.Ctx) error {
 		c.Log.Warnf("Failed to get task : %+v", err)
 		return err
 	}
+	// included tags change without the task, so they are part of the tag
+	etag := helper.ETag(response.Version)
+	if request.Include == "tags" {
+		versions := make(map[uint]uint, len(response.Tags))
+		for _, tag := range response.Tags {
+			versions[tag.ID] = tag.Version
+		}
+		etag = helper.CompositeETag(response.Version, versions)
+	}
+	ctx.Set(fiber.HeaderETag, etag)
+	if helper.MatchEntityTag(ctx.Get(fiber.HeaderIfNoneMatch), etag, true) {
+		return ctx.SendStatus(fiber.StatusNotModified)
+	}
 	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully get task", fiber.StatusOK, nil))
 }
 
@@ -102,6 +206,7 @@ func (c *TaskController) Delete(ctx *fiber.Ctx) error {
 	request := &model.GetTaskRequest{
 		ID: ctx.Params("taskId"),
 		Email: auth.Email,
+		IfMatch: ctx.Get(fiber.HeaderIfMatch),
 	}
 
 	if err := c.UseCase.Delete(ctx.UserContext(), request); err != nil {
@@ -110,4 +215,55 @@ func (c *TaskController) Delete(ctx *fiber.Ctx) error {
 	}
 	
 	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+func (c *TaskController) Board(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	projectId, err := strconv.ParseUint(ctx.Params("projectId"), 10, 32)
+	if err != nil {
+		c.Log.Warnf("Invalid project ID : %+v", err)
+		return model.ErrBadRequest
+	}
+	request := &model.GetBoardRequest{
+		ProjectId: uint(projectId),
+		Email: auth.Email,
+		Pages: map[string]int{},
+		Cursors: map[string]string{},
+		Size: ctx.QueryInt("size", 10),
+		Total: ctx.Query("total", ""),
+	}
+	if status := ctx.Query("status", ""); status != "" {
+		request.Statuses = []string{status}
+	}
+	// setiap kolom memiliki halaman dan cursor sendiri, contoh: ?pending_page=2 atau ?pending_cursor=...
+	for _, status := range model.TaskStatuses {
+		request.Pages[status] = ctx.QueryInt(status+"_page", 1)
+		if cursor := ctx.Query(status+"_cursor", ""); cursor != "" {
+			request.Cursors[status] = cursor
+		}
+	}
+
+	responses, err := c.UseCase.Board(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to get board : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(responses, "Board fetched successfully", fiber.StatusOK, nil))
+}
+
+func (c *TaskController) Move(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.MoveTaskRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return model.ErrBadRequest
+	}
+	request.ID = ctx.Params("taskId")
+	request.Email = auth.Email
+	response, err := c.UseCase.Move(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to move task : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully moved task", fiber.StatusOK, nil))
 }
\ No newline at end of file
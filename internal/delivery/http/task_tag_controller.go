@@ -1,7 +1,6 @@
 package http
 
 import (
-	"math"
 	"strconv"
 
 	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/middleware"
@@ -51,18 +50,14 @@ func (c *TaskTagController) List(ctx *fiber.Ctx) error {
 		Email: auth.Email,
 		Page: ctx.QueryInt("page", 1),
 		Size: ctx.QueryInt("size", 10),
+		Cursor: ctx.Query("cursor", ""),
+		Total: ctx.Query("total", ""),
 	}
-	responses, total, err := c.UseCase.Search(ctx.UserContext(), request)
+	responses, paging, err := c.UseCase.Search(ctx.UserContext(), request)
 	if err != nil {
 		c.Log.Warnf("Failed to list task tags : %+v", err)
 		return err
 	}
-	paging := &model.PageMetadata{
-		Page: request.Page,
-		Size: request.Size,
-		TotalItem: total,
-		TotalPage: int64(math.Ceil(float64(total) / float64(request.Size))),
-		}
 
 	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(responses, "Task tags fetched successfully", fiber.StatusOK, paging))
 }
@@ -80,18 +75,14 @@ func (c *TaskTagController) ListByTagId(ctx *fiber.Ctx) error {
 		TagId: uint(tagId),
 		Page: ctx.QueryInt("page", 1),
 		Size: ctx.QueryInt("size", 10),
+		Cursor: ctx.Query("cursor", ""),
+		Total: ctx.Query("total", ""),
 	}
-	responses, total, err := c.UseCase.SearchTaskTagRequestWithTagId(ctx.UserContext(), request)
+	responses, paging, err := c.UseCase.SearchTaskTagRequestWithTagId(ctx.UserContext(), request)
 	if err != nil {
 		c.Log.Warnf("Failed to list task tags : %+v", err)
 		return err
 	}
-	paging := &model.PageMetadata{
-		Page: request.Page,
-		Size: request.Size,
-		TotalItem: total,
-		TotalPage: int64(math.Ceil(float64(total) / float64(request.Size))),
-		}
 
 	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(responses, "Task tags fetched successfully", fiber.StatusOK, paging))
 }
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/middleware"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type TaskTemplateController struct {
+	UseCase *usecase.TaskTemplateUseCase
+	Log     *logrus.Logger
+}
+
+func NewTaskTemplateController(useCase *usecase.TaskTemplateUseCase, logger *logrus.Logger) *TaskTemplateController {
+	return &TaskTemplateController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *TaskTemplateController) Create(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.SaveTaskTemplateRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return model.ErrBadRequest
+	}
+	request.Email = auth.Email
+	response, err := c.UseCase.Create(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to create template : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(model.NewWebResponse(response, "Successfully created template", fiber.StatusCreated, nil))
+}
+
+func (c *TaskTemplateController) List(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	responses, err := c.UseCase.List(ctx.UserContext(), auth.Email)
+	if err != nil {
+		c.Log.Warnf("Failed to list templates : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(responses, "Templates fetched successfully", fiber.StatusOK, nil))
+}
+
+func (c *TaskTemplateController) Get(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.GetTaskTemplateRequest{
+		ID:    ctx.Params("templateId"),
+		Email: auth.Email,
+	}
+	response, err := c.UseCase.Get(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to get template : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully get template", fiber.StatusOK, nil))
+}
+
+func (c *TaskTemplateController) Update(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.SaveTaskTemplateRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return model.ErrBadRequest
+	}
+	request.ID = ctx.Params("templateId")
+	request.Email = auth.Email
+	response, err := c.UseCase.Update(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to update template : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully updated template", fiber.StatusOK, nil))
+}
+
+func (c *TaskTemplateController) Delete(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.GetTaskTemplateRequest{
+		ID:    ctx.Params("templateId"),
+		Email: auth.Email,
+	}
+	if err := c.UseCase.Delete(ctx.UserContext(), request); err != nil {
+		c.Log.Warnf("Failed to delete template : %+v", err)
+		return err
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+func (c *TaskTemplateController) Instantiate(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.InstantiateTaskTemplateRequest)
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(request); err != nil {
+			c.Log.Warnf("Failed to parse request body : %+v", err)
+			return model.ErrBadRequest
+		}
+	}
+	request.ID = ctx.Params("templateId")
+	request.Email = auth.Email
+	request.Timezone = auth.Timezone
+	response, err := c.UseCase.Instantiate(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to instantiate template : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(model.NewWebResponse(response, "Successfully created task from template", fiber.StatusCreated, nil))
+}
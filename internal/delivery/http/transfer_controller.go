@@ -0,0 +1,96 @@
+package http
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/middleware"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// exportContentTypes maps the export formats to their media type.
+var exportContentTypes = map[string]string{
+	"csv":    "text/csv; charset=utf-8",
+	"ndjson": "application/x-ndjson",
+}
+
+type TransferController struct {
+	UseCase *usecase.TransferUseCase
+	Log     *logrus.Logger
+}
+
+func NewTransferController(useCase *usecase.TransferUseCase, logger *logrus.Logger) *TransferController {
+	return &TransferController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *TransferController) Export(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.ExportTasksRequest{
+		Email:     auth.Email,
+		ProjectId: uint(ctx.QueryInt("project_id", 0)),
+		Query:     ctx.Query("q", ""),
+		Timezone:  ctx.Query("tz", auth.Timezone),
+		Format:    ctx.Query("format", "csv"),
+	}
+	var buffer bytes.Buffer
+	if err := c.UseCase.Export(ctx.UserContext(), request, &buffer); err != nil {
+		c.Log.Warnf("Failed to export tasks : %+v", err)
+		return err
+	}
+	ctx.Set(fiber.HeaderContentType, exportContentTypes[request.Format])
+	ctx.Attachment("tasks." + request.Format)
+	return ctx.Status(fiber.StatusOK).Send(buffer.Bytes())
+}
+
+// Import accepts the file either as the raw request body or as the "file"
+// field of a multipart form. Without ?format= the format follows the
+// Content-Type of the body.
+func (c *TransferController) Import(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	format := "csv"
+	if strings.Contains(ctx.Get(fiber.HeaderContentType), "json") {
+		format = "ndjson"
+	}
+	request := &model.ImportTasksRequest{
+		Email:      auth.Email,
+		ProjectId:  uint(ctx.QueryInt("project_id", 0)),
+		Format:     ctx.Query("format", format),
+		Mapping:    map[string]string{},
+		DryRun:     ctx.QueryBool("dry_run", false),
+		Atomic:     ctx.QueryBool("atomic", false),
+		CreateTags: ctx.QueryBool("create_tags", true),
+		Offset:     ctx.QueryInt("offset", 0),
+		ChunkSize:  ctx.QueryInt("chunk_size", 500),
+	}
+	for _, pair := range splitQuery(ctx.Query("mapping", "")) {
+		field, column, _ := strings.Cut(pair, ":")
+		request.Mapping[strings.TrimSpace(field)] = strings.TrimSpace(column)
+	}
+	data, err := uploadedFile(ctx)
+	if err != nil {
+		c.Log.Warnf("Failed to read uploaded file : %+v", err)
+		return model.ErrBadRequest
+	}
+	request.Data = data
+
+	response, err := c.UseCase.Import(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to import tasks : %+v", err)
+		return err
+	}
+	switch {
+	case response.Rejected:
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(model.NewWebResponse(response, "Import rejected, no task was written", fiber.StatusUnprocessableEntity, nil))
+	case response.ResumeFrom != nil:
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.NewWebResponse(response, "Import interrupted, resume from the given offset", fiber.StatusInternalServerError, nil))
+	case response.DryRun:
+		return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully checked tasks", fiber.StatusOK, nil))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully imported tasks", fiber.StatusOK, nil))
+}
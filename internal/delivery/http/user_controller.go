@@ -74,7 +74,24 @@ func (c *UserController) Update(ctx *fiber.Ctx) error {
 	response, err := c.UseCase.Update(ctx.UserContext(), request)
 	if err != nil {
 		c.Log.Warnf("Failed to update user : %+v", err)
-		return model.ErrInternalServer
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully updated user", fiber.StatusOK, nil))
+}
+
+func (c *UserController) Patch(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := new(model.PatchUserRequest)
+	if err := parseMergePatch(ctx, request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return err
+	}
+	request.Email = auth.Email
+	response, err := c.UseCase.Patch(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to update user : %+v", err)
+		return err
 	}
 	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully updated user", fiber.StatusOK, nil))
-}
\ No newline at end of file
+}
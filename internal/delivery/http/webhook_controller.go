@@ -0,0 +1,125 @@
+package http
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/delivery/http/middleware"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type WebhookController struct {
+	UseCase *usecase.WebhookUseCase
+	Log     *logrus.Logger
+}
+
+func NewWebhookController(useCase *usecase.WebhookUseCase, logger *logrus.Logger) *WebhookController {
+	return &WebhookController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *WebhookController) Create(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.SaveWebhookRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return model.ErrBadRequest
+	}
+	request.Email = auth.Email
+	response, err := c.UseCase.Create(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to create webhook : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(model.NewWebResponse(response, "Successfully created webhook", fiber.StatusCreated, nil))
+}
+
+func (c *WebhookController) List(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	responses, err := c.UseCase.List(ctx.UserContext(), auth.Email)
+	if err != nil {
+		c.Log.Warnf("Failed to list webhooks : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(responses, "Successfully get webhooks", fiber.StatusOK, nil))
+}
+
+func (c *WebhookController) Get(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.GetWebhookRequest{
+		ID:    ctx.Params("webhookId"),
+		Email: auth.Email,
+	}
+	response, err := c.UseCase.Get(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to get webhook : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully get webhook", fiber.StatusOK, nil))
+}
+
+func (c *WebhookController) Update(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.SaveWebhookRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return model.ErrBadRequest
+	}
+	request.ID = ctx.Params("webhookId")
+	request.Email = auth.Email
+	response, err := c.UseCase.Update(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to update webhook : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(response, "Successfully updated webhook", fiber.StatusOK, nil))
+}
+
+func (c *WebhookController) Delete(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.GetWebhookRequest{
+		ID:    ctx.Params("webhookId"),
+		Email: auth.Email,
+	}
+	if err := c.UseCase.Delete(ctx.UserContext(), request); err != nil {
+		c.Log.Warnf("Failed to delete webhook : %+v", err)
+		return err
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+func (c *WebhookController) Deliveries(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.SearchWebhookDeliveryRequest{
+		WebhookId: ctx.Params("webhookId"),
+		Email:     auth.Email,
+		Status:    ctx.Query("status", ""),
+		Page:      ctx.QueryInt("page", 1),
+		Size:      ctx.QueryInt("size", 20),
+		Cursor:    ctx.Query("cursor", ""),
+		Total:     ctx.Query("total", ""),
+	}
+	responses, paging, err := c.UseCase.Deliveries(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to list webhook deliveries : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(model.NewWebResponse(responses, "Successfully get webhook deliveries", fiber.StatusOK, paging))
+}
+
+func (c *WebhookController) Redeliver(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.RedeliverWebhookRequest{
+		ID:        ctx.Params("deliveryId"),
+		WebhookId: ctx.Params("webhookId"),
+		Email:     auth.Email,
+	}
+	response, err := c.UseCase.Redeliver(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to redeliver webhook delivery : %+v", err)
+		return err
+	}
+	return ctx.Status(fiber.StatusAccepted).JSON(model.NewWebResponse(response, "Successfully queued webhook delivery", fiber.StatusAccepted, nil))
+}
@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// Activity is something that happened to a task, tag or comment of a
+// project. Data holds details of the change as JSON.
+type Activity struct {
+	ID         uint      `gorm:"column:id;primaryKey;autoIncrement"`
+	ProjectId  uint      `gorm:"column:project_id;not null"`
+	Actor      string    `gorm:"column:actor;type:varchar(100);not null"`
+	Type       string    `gorm:"column:type;type:varchar(50);not null"`
+	EntityType string    `gorm:"column:entity_type;type:varchar(20);not null"`
+	EntityId   uint      `gorm:"column:entity_id;not null"`
+	Summary    string    `gorm:"column:summary;type:varchar(255);not null"`
+	Data       string    `gorm:"column:data;type:text"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (Activity) TableName() string {
+	return "activities"
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// CalendarFeed holds the secret token of a user's iCalendar feed URL.
+type CalendarFeed struct {
+	Email     string    `gorm:"column:email;primaryKey;type:varchar(150)"`
+	Token     string    `gorm:"column:token;type:varchar(64);not null;uniqueIndex"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+}
+
+func (CalendarFeed) TableName() string {
+	return "calendar_feeds"
+}
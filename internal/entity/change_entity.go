@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// Change records that a task, a tag or the link between them was written
+// in a project. The row itself is not kept: its seq only orders the change
+// for delta sync, which reads the current state of the entity. Seq is
+// given after the transaction of the change commits, so it follows commit
+// order where the id follows write order. TaskId is set for tasks and
+// links, TagId for tags and links.
+type Change struct {
+	ID         uint      `gorm:"column:id;primaryKey;autoIncrement"`
+	Seq        *uint     `gorm:"column:seq"`
+	ProjectId  uint      `gorm:"column:project_id;not null"`
+	EntityType string    `gorm:"column:entity_type;type:enum('task','tag','task_tag');not null"`
+	TaskId     *uint     `gorm:"column:task_id"`
+	TagId      *uint     `gorm:"column:tag_id"`
+	CreatedAt  time.Time `gorm:"column:created_at;->"`
+}
+
+func (Change) TableName() string {
+	return "changes"
+}
+
+// ChangeSequence is the single row holding the last seq given to a change
+// and the last seq of the changes pruned from the log.
+type ChangeSequence struct {
+	ID     uint `gorm:"column:id;primaryKey"`
+	Value  uint `gorm:"column:value"`
+	Pruned uint `gorm:"column:pruned"`
+}
+
+func (ChangeSequence) TableName() string {
+	return "change_sequence"
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+type Comment struct {
+	ID        uint      `gorm:"column:id;primaryKey;autoIncrement"`
+	TaskId    uint      `gorm:"column:task_id;not null;index"`
+	Email     string    `gorm:"column:email;type:varchar(150);not null"`
+	Body      string    `gorm:"column:body;type:text;not null"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+	Task      Task      `gorm:"foreignKey:task_id;references:id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (Comment) TableName() string {
+	return "task_comments"
+}
@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+// ImportJob tracks an import running in the background. Skipped holds the
+// skipped items as JSON and Payload the rows still to import, as JSON, until
+// the job finishes. LeaseUntil is when a worker may take over the job from
+// one that stopped, and Runs counts the times that happened.
+type ImportJob struct {
+	ID         uint       `gorm:"column:id;primaryKey;autoIncrement"`
+	Email      string     `gorm:"column:email;type:varchar(150);not null"`
+	ProjectId  uint       `gorm:"column:project_id;not null"`
+	Source     string     `gorm:"column:source;type:enum('todoist','trello','todotxt');not null"`
+	Status     string     `gorm:"column:status;type:enum('queued','running','completed','failed');default:queued"`
+	Total      int        `gorm:"column:total;not null"`
+	Processed  int        `gorm:"column:processed;not null"`
+	Created    int        `gorm:"column:created;not null"`
+	Updated    int        `gorm:"column:updated;not null"`
+	Skipped    string     `gorm:"column:skipped;type:mediumtext"`
+	Error      string     `gorm:"column:error;type:varchar(500);not null"`
+	Payload    string     `gorm:"column:payload;type:longtext"`
+	CreateTags bool       `gorm:"column:create_tags;not null"`
+	LeaseUntil *time.Time `gorm:"column:lease_until"`
+	Runs       int        `gorm:"column:runs;not null"`
+	CreatedAt  time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+	FinishedAt *time.Time `gorm:"column:finished_at"`
+}
+
+func (ImportJob) TableName() string {
+	return "import_jobs"
+}
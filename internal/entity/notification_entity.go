@@ -0,0 +1,62 @@
+package entity
+
+import "time"
+
+// Notification is a message to a user. InApp notifications are listed in
+// the notification center; the email and webhook statuses track the other
+// channels, which are sent from DeliverAfter on and tried again after a
+// failure until their attempts run out. A notification with a DedupeKey is
+// stored only once per user.
+type Notification struct {
+	ID              uint       `gorm:"column:id;primaryKey;autoIncrement"`
+	Email           string     `gorm:"column:email;type:varchar(150);not null"`
+	Type            string     `gorm:"column:type;type:varchar(50);not null"`
+	Title           string     `gorm:"column:title;type:varchar(255);not null"`
+	Body            string     `gorm:"column:body;type:text"`
+	Data            string     `gorm:"column:data;type:text"`
+	DedupeKey       *string    `gorm:"column:dedupe_key;type:varchar(150)"`
+	InApp           bool       `gorm:"column:in_app;not null"`
+	ReadAt          *time.Time `gorm:"column:read_at"`
+	EmailStatus     string     `gorm:"column:email_status;type:enum('none','pending','digest','sent','failed');default:none"`
+	WebhookStatus   string     `gorm:"column:webhook_status;type:enum('none','pending','sent','failed');default:none"`
+	EmailAttempts   int        `gorm:"column:email_attempts"`
+	WebhookAttempts int        `gorm:"column:webhook_attempts"`
+	DeliverAfter    *time.Time `gorm:"column:deliver_after"`
+	CreatedAt       time.Time  `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// NotificationPreference picks the channels of one notification type.
+type NotificationPreference struct {
+	Email     string `gorm:"column:email;type:varchar(150);primaryKey"`
+	Type      string `gorm:"column:type;type:varchar(50);primaryKey"`
+	InApp     bool   `gorm:"column:in_app;not null"`
+	ByEmail   bool   `gorm:"column:by_email;not null"`
+	ByWebhook bool   `gorm:"column:by_webhook;not null"`
+}
+
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+// NotificationSetting holds how a user receives notifications. Times are
+// wall clock times in the time zone of the user.
+type NotificationSetting struct {
+	Email         string     `gorm:"column:email;type:varchar(150);primaryKey"`
+	Digest        bool       `gorm:"column:digest;not null"`
+	DigestTime    string     `gorm:"column:digest_time;type:varchar(5);not null"`
+	LastDigestAt  *time.Time `gorm:"column:last_digest_at"`
+	QuietStart    *string    `gorm:"column:quiet_start;type:varchar(5)"`
+	QuietEnd      *string    `gorm:"column:quiet_end;type:varchar(5)"`
+	WebhookURL    *string    `gorm:"column:webhook_url;type:varchar(2048)"`
+	WebhookSecret *string    `gorm:"column:webhook_secret;type:varchar(100)"`
+	CreatedAt     time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+}
+
+func (NotificationSetting) TableName() string {
+	return "notification_settings"
+}
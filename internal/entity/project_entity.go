@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+type Project struct {
+	ID         uint            `gorm:"column:id;primaryKey;autoIncrement"`
+	OwnerEmail string          `gorm:"column:owner_email;type:varchar(150);not null;index"`
+	Name       string          `gorm:"column:name;type:varchar(100);not null"`
+	IsPersonal bool            `gorm:"column:is_personal;not null;default:false"`
+	CreatedAt  time.Time       `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt  time.Time       `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+	Members    []ProjectMember `gorm:"foreignKey:project_id;references:id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (Project) TableName() string {
+	return "projects"
+}
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+type ProjectInvitation struct {
+	ID        uint      `gorm:"column:id;primaryKey;autoIncrement"`
+	ProjectId uint      `gorm:"column:project_id;not null;index"`
+	Email     string    `gorm:"column:email;type:varchar(150);not null;index"`
+	InvitedBy string    `gorm:"column:invited_by;type:varchar(150);not null"`
+	Role      string    `gorm:"column:role;type:enum('editor','viewer');default:viewer"`
+	Status    string    `gorm:"column:status;type:enum('pending','accepted','declined');default:pending"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+	Project   Project   `gorm:"foreignKey:project_id;references:id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (ProjectInvitation) TableName() string {
+	return "project_invitations"
+}
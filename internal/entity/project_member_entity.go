@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+type ProjectMember struct {
+	ID        uint      `gorm:"column:id;primaryKey;autoIncrement"`
+	ProjectId uint      `gorm:"column:project_id;not null;index"`
+	Email     string    `gorm:"column:email;type:varchar(150);not null;index"`
+	Role      string    `gorm:"column:role;type:enum('owner','editor','viewer');default:viewer"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+	Project   Project   `gorm:"foreignKey:project_id;references:id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (ProjectMember) TableName() string {
+	return "project_members"
+}
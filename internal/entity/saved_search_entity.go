@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+type SavedSearch struct {
+	ID        uint      `gorm:"column:id;primaryKey;autoIncrement"`
+	Email     string    `gorm:"column:email;type:varchar(150);not null"`
+	Name      string    `gorm:"column:name;type:varchar(100);not null"`
+	Query     string    `gorm:"column:query;type:varchar(500);not null"`
+	Sort      string    `gorm:"column:sort;type:varchar(20);not null"`
+	Pinned    bool      `gorm:"column:pinned;not null;default:false"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+}
+
+func (SavedSearch) TableName() string {
+	return "saved_searches"
+}
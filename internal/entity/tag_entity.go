@@ -5,11 +5,14 @@ import "time"
 type Tag struct {
     ID        uint      `gorm:"column:id;primaryKey;autoIncrement"`
     Email     string    `gorm:"column:email;type:varchar(150);index"`
+    ProjectId uint      `gorm:"column:project_id;not null;index"`
     Name      string    `gorm:"column:name;type:varchar(50);not null"`
+    Version   uint      `gorm:"column:version;not null;default:1"`
     CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
     UpdatedAt time.Time `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
     Tasks     []Task    `gorm:"many2many:task_tags"`
     User      User      `gorm:"foreignKey:email;references:email"`
+    Project   Project   `gorm:"foreignKey:project_id;references:id"`
 }
 
 func (Tag) TableName() string {
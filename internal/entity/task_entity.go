@@ -5,14 +5,25 @@ import "time"
 type Task struct {
     ID          uint      `gorm:"column:id;primaryKey;autoIncrement"`
     Email       string    `gorm:"column:email;type:varchar(100);not null;index"`
+    ProjectId   uint      `gorm:"column:project_id;not null;index"`
+    ParentId    *uint     `gorm:"column:parent_id"`
+    Uid         *string   `gorm:"column:uid;type:varchar(255)"`
     Title       string    `gorm:"column:title;type:varchar(150);not null"`
     Description string    `gorm:"column:description;type:text"`
     Status      string    `gorm:"column:status;type:enum('pending','in_progress','completed');default:pending"`
-    DueDate     time.Time `gorm:"column:due_date;type:date"`
+    Priority    string    `gorm:"column:priority;type:enum('low','medium','high','urgent');default:medium"`
+    Position    string    `gorm:"column:position;type:varchar(64);not null"`
+    DueDate     *time.Time `gorm:"column:due_date;type:date"`
+    DueTime     *string   `gorm:"column:due_time;type:time"`
+    StartDate   *time.Time `gorm:"column:start_date;type:date"`
+    Recurrence  *string   `gorm:"column:recurrence;type:varchar(500)"`
+    Version     uint      `gorm:"column:version;not null;default:1"`
+    CompletedAt *time.Time `gorm:"column:completed_at"`
     CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
     UpdatedAt   time.Time `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
     Tags        []Tag     `gorm:"many2many:task_tags"`
     User        User      `gorm:"foreignKey:email;references:email"`
+    Project     Project   `gorm:"foreignKey:project_id;references:id"`
 }
 
 func (Task) TableName() string {
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// TaskTemplate is a named blueprint for a task. Tags holds the tag names
+// and Subtasks the subtasks, both as JSON.
+type TaskTemplate struct {
+	ID          uint      `gorm:"column:id;primaryKey;autoIncrement"`
+	Email       string    `gorm:"column:email;type:varchar(150);not null"`
+	Name        string    `gorm:"column:name;type:varchar(100);not null"`
+	Title       string    `gorm:"column:title;type:varchar(150);not null"`
+	Description string    `gorm:"column:description;type:text"`
+	Status      string    `gorm:"column:status;type:enum('pending','in_progress','completed');default:pending"`
+	Priority    string    `gorm:"column:priority;type:enum('low','medium','high','urgent');default:medium"`
+	DueIn       string    `gorm:"column:due_in;type:varchar(20);not null"`
+	Tags        string    `gorm:"column:tags;type:text"`
+	Subtasks    string    `gorm:"column:subtasks;type:mediumtext"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+}
+
+func (TaskTemplate) TableName() string {
+	return "task_templates"
+}
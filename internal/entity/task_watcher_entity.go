@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+type TaskWatcher struct {
+	ID        uint      `gorm:"column:id;primaryKey;autoIncrement"`
+	TaskId    uint      `gorm:"column:task_id;not null;index"`
+	Email     string    `gorm:"column:email;type:varchar(150);not null;index"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	Task      Task      `gorm:"foreignKey:task_id;references:id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (TaskWatcher) TableName() string {
+	return "task_watchers"
+}
@@ -6,6 +6,8 @@ type User struct {
     Email       string    `gorm:"column:email;primaryKey;type:varchar(150);uniqueIndex"`
     Name        string    `gorm:"column:name;type:varchar(100);not null"`
     Password    string    `gorm:"column:password;type:varchar(255);not null"`
+    Timezone    string    `gorm:"column:timezone;type:varchar(64);not null;default:UTC"`
+    Locale      string    `gorm:"column:locale;type:varchar(35);not null;default:en"`
     AccessToken string    `gorm:"-"`
     CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
     UpdatedAt   time.Time `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+// Webhook sends the activities of the projects of its owner, or of one of
+// them, to a URL. Events holds the subscribed activity types as JSON.
+// FailureCount counts the failed tries since the last success.
+type Webhook struct {
+	ID           uint       `gorm:"column:id;primaryKey;autoIncrement"`
+	Email        string     `gorm:"column:email;type:varchar(150);not null"`
+	ProjectId    *uint      `gorm:"column:project_id"`
+	URL          string     `gorm:"column:url;type:varchar(2048);not null"`
+	Secret       string     `gorm:"column:secret;type:varchar(100);not null"`
+	Events       string     `gorm:"column:events;type:text;not null"`
+	Active       bool       `gorm:"column:active;not null"`
+	FailureCount int        `gorm:"column:failure_count;not null"`
+	DisabledAt   *time.Time `gorm:"column:disabled_at"`
+	CreatedAt    time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt    time.Time  `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+}
+
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// WebhookDelivery sends one activity to one webhook. Pending deliveries are
+// tried again at NextAttemptAt.
+type WebhookDelivery struct {
+	ID            uint       `gorm:"column:id;primaryKey;autoIncrement"`
+	WebhookId     uint       `gorm:"column:webhook_id;not null"`
+	ActivityId    uint       `gorm:"column:activity_id;not null"`
+	Event         string     `gorm:"column:event;type:varchar(50);not null"`
+	Status        string     `gorm:"column:status;type:enum('pending','succeeded','failed');default:pending"`
+	Attempts      int        `gorm:"column:attempts;not null"`
+	NextAttemptAt *time.Time `gorm:"column:next_attempt_at"`
+	ResponseCode  *int       `gorm:"column:response_code"`
+	Error         string     `gorm:"column:error;type:varchar(255);not null"`
+	DeliveredAt   *time.Time `gorm:"column:delivered_at"`
+	CreatedAt     time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+	Webhook       Webhook    `gorm:"foreignKey:webhook_id;references:id"`
+	Activity      Activity   `gorm:"foreignKey:activity_id;references:id"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
@@ -0,0 +1,295 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Error reports a problem with a filter expression. Pos is the 1-based
+// character position the problem starts at.
+type Error struct {
+	Pos     int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid filter at position %d: %s", e.Pos, e.Message)
+}
+
+// Expr is a node of a parsed filter expression.
+type Expr interface {
+	Pos() int
+}
+
+// And matches tasks matching every expression. Terms separated by spaces
+// are joined with And.
+type And struct {
+	Exprs []Expr
+	At    int
+}
+
+// Or matches tasks matching any expression, e.g. `tag:work OR tag:home`.
+type Or struct {
+	Exprs []Expr
+	At    int
+}
+
+// Not excludes tasks matching the expression, e.g. `-tag:someday`.
+type Not struct {
+	Expr Expr
+	At   int
+}
+
+// Condition compares a task field with a value, e.g. `due<2026-11-01`.
+type Condition struct {
+	Field   string
+	Op      string
+	Value   string
+	At      int
+	OpAt    int
+	ValueAt int
+}
+
+// Text is a bare word or quoted phrase matched against title and description.
+type Text struct {
+	Value string
+	At    int
+}
+
+func (e *And) Pos() int       { return e.At }
+func (e *Or) Pos() int        { return e.At }
+func (e *Not) Pos() int       { return e.At }
+func (e *Condition) Pos() int { return e.At }
+func (e *Text) Pos() int      { return e.At }
+
+// Values splits a comma separated value such as `status:pending,in_progress`.
+func (e *Condition) Values() []string {
+	return strings.Split(e.Value, ",")
+}
+
+// Parse parses and validates a filter expression such as
+// `status:in_progress tag:work due<2026-11-01 priority>=high -tag:someday`.
+// An empty expression returns a nil Expr.
+func Parse(text string) (Expr, error) {
+	p := &parser{input: []rune(text)}
+	p.skipSpace()
+	if p.eof() {
+		return nil, nil
+	}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if !p.eof() {
+		return nil, p.errorf(p.pos, "unexpected %q", p.input[p.pos])
+	}
+	if err := Validate(expr); err != nil {
+		return nil, err
+	}
+	return expr, nil
+}
+
+type parser struct {
+	input []rune
+	pos   int
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	start := p.pos
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	exprs := []Expr{first}
+	for {
+		p.skipSpace()
+		if !p.keyword("OR") {
+			break
+		}
+		at := p.pos
+		p.pos += 2
+		p.skipSpace()
+		if p.eof() || p.peek() == ')' || p.keyword("OR") {
+			return nil, p.errorf(at, "expected a filter after OR")
+		}
+		next, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, next)
+	}
+	if len(exprs) == 1 {
+		return first, nil
+	}
+	return &Or{Exprs: exprs, At: start + 1}, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	p.skipSpace()
+	start := p.pos
+	var exprs []Expr
+	for {
+		p.skipSpace()
+		if p.eof() || p.peek() == ')' || p.keyword("OR") {
+			break
+		}
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	switch len(exprs) {
+	case 0:
+		if p.eof() {
+			return nil, p.errorf(p.pos, "expected a filter")
+		}
+		return nil, p.errorf(p.pos, "unexpected %q", p.input[p.pos])
+	case 1:
+		return exprs[0], nil
+	}
+	return &And{Exprs: exprs, At: start + 1}, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.peek() != '-' {
+		return p.parsePrimary()
+	}
+	at := p.pos
+	p.pos++
+	if p.eof() || unicode.IsSpace(p.peek()) || p.peek() == ')' {
+		return nil, p.errorf(at, "expected a filter after '-'")
+	}
+	expr, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return &Not{Expr: expr, At: at + 1}, nil
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	switch p.peek() {
+	case '(':
+		at := p.pos
+		p.pos++
+		p.skipSpace()
+		if p.peek() == ')' {
+			return nil, p.errorf(at, "empty group")
+		}
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.peek() != ')' {
+			return nil, p.errorf(at, "unclosed '('")
+		}
+		p.pos++
+		return expr, nil
+	case '"':
+		at := p.pos
+		value, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		return &Text{Value: value, At: at + 1}, nil
+	}
+
+	at := p.pos
+	for !p.eof() && (unicode.IsLetter(p.peek()) || p.peek() == '_') {
+		p.pos++
+	}
+	if p.pos > at && !p.eof() && strings.ContainsRune(":<>=", p.peek()) {
+		return p.condition(strings.ToLower(string(p.input[at:p.pos])), at)
+	}
+	for !p.eof() && !p.boundary(p.peek()) {
+		p.pos++
+	}
+	return &Text{Value: string(p.input[at:p.pos]), At: at + 1}, nil
+}
+
+func (p *parser) condition(field string, at int) (Expr, error) {
+	opAt := p.pos
+	op := string(p.peek())
+	p.pos++
+	if (op == "<" || op == ">") && p.peek() == '=' {
+		op += "="
+		p.pos++
+	}
+	if op == "=" {
+		op = ":"
+	}
+	valueAt := p.pos
+	var value string
+	if p.peek() == '"' {
+		quoted, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		value = quoted
+	} else {
+		for !p.eof() && !p.boundary(p.peek()) {
+			p.pos++
+		}
+		value = string(p.input[valueAt:p.pos])
+	}
+	if value == "" {
+		return nil, p.errorf(valueAt, "missing value for %s", field)
+	}
+	return &Condition{Field: field, Op: op, Value: value, At: at + 1, OpAt: opAt + 1, ValueAt: valueAt + 1}, nil
+}
+
+func (p *parser) quoted() (string, error) {
+	at := p.pos
+	p.pos++
+	for !p.eof() && p.peek() != '"' {
+		p.pos++
+	}
+	if p.eof() {
+		return "", p.errorf(at, "unterminated quote")
+	}
+	p.pos++
+	value := strings.TrimSpace(string(p.input[at+1 : p.pos-1]))
+	if value == "" {
+		return "", p.errorf(at, "empty quotes")
+	}
+	return value, nil
+}
+
+// keyword reports whether the upper case word is at the current position
+// as a whole word.
+func (p *parser) keyword(word string) bool {
+	end := p.pos + len(word)
+	if end > len(p.input) || string(p.input[p.pos:end]) != word {
+		return false
+	}
+	return end == len(p.input) || p.boundary(p.input[end])
+}
+
+func (p *parser) boundary(r rune) bool {
+	return unicode.IsSpace(r) || r == '(' || r == ')'
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+func (p *parser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+// errorf builds an Error for the 0-based offset.
+func (p *parser) errorf(offset int, format string, args ...any) error {
+	return &Error{Pos: offset + 1, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,216 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// render writes an expression in prefix notation so trees compare as
+// strings.
+func render(expr Expr) string {
+	switch e := expr.(type) {
+	case *And:
+		return "(and " + renderAll(e.Exprs) + ")"
+	case *Or:
+		return "(or " + renderAll(e.Exprs) + ")"
+	case *Not:
+		return "(not " + render(e.Expr) + ")"
+	case *Condition:
+		return fmt.Sprintf("%s%s%q", e.Field, e.Op, e.Value)
+	case *Text:
+		return fmt.Sprintf("%q", e.Value)
+	case nil:
+		return "nil"
+	}
+	return fmt.Sprintf("%T", expr)
+}
+
+func renderAll(exprs []Expr) string {
+	parts := make([]string, len(exprs))
+	for i, expr := range exprs {
+		parts[i] = render(expr)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "", want: "nil"},
+		{text: "   ", want: "nil"},
+		{text: "invoice", want: `"invoice"`},
+		{text: "status:pending", want: `status:"pending"`},
+		{text: "STATUS=pending", want: `status:"pending"`},
+		{text: "status:pending,in_progress", want: `status:"pending,in_progress"`},
+		{text: "priority>=high due<2026-11-01", want: `(and priority>="high" due<"2026-11-01")`},
+		{text: "due:none -tag:someday", want: `(and due:"none" (not tag:"someday"))`},
+		// spaces bind tighter than OR
+		{text: "tag:work status:pending OR tag:home", want: `(or (and tag:"work" status:"pending") tag:"home")`},
+		{text: "tag:work OR tag:home OR tag:errand", want: `(or tag:"work" tag:"home" tag:"errand")`},
+		{text: "tag:work (status:pending OR priority:high)", want: `(and tag:"work" (or status:"pending" priority:"high"))`},
+		{text: "(tag:work OR tag:home) -(status:completed OR due:none)", want: `(and (or tag:"work" tag:"home") (not (or status:"completed" due:"none")))`},
+		{text: "-tag:work OR x", want: `(or (not tag:"work") "x")`},
+		{text: "--tag:work", want: `(not (not tag:"work"))`},
+		{text: "((invoice))", want: `"invoice"`},
+		// OR is only a keyword in upper case and as a whole word
+		{text: "cats or dogs", want: `(and "cats" "or" "dogs")`},
+		{text: "ORACLE", want: `"ORACLE"`},
+		// quoting
+		{text: `"release notes"`, want: `"release notes"`},
+		{text: `"  padded  "`, want: `"padded"`},
+		{text: `title:"Pay invoice" OR "OR"`, want: `(or title:"Pay invoice" "OR")`},
+		{text: `tag:"home office"`, want: `tag:"home office"`},
+		{text: "e-mail 5:30", want: `(and "e-mail" "5:30")`},
+		{text: "assignee:me project:3", want: `(and assignee:"me" project:"3")`},
+		{text: "created>=-2w updated<today", want: `(and created>="-2w" updated<"today")`},
+	}
+	for _, test := range tests {
+		expr, err := Parse(test.text)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", test.text, err)
+			continue
+		}
+		if got := render(expr); got != test.want {
+			t.Errorf("Parse(%q) = %s, want %s", test.text, got, test.want)
+		}
+	}
+}
+
+func TestParsePositions(t *testing.T) {
+	expr, err := Parse(`tag:work  -priority>="high"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	and := expr.(*And)
+	if and.At != 1 {
+		t.Errorf("and at %d, want 1", and.At)
+	}
+	not := and.Exprs[1].(*Not)
+	condition := not.Expr.(*Condition)
+	if not.At != 11 || condition.At != 12 || condition.OpAt != 20 || condition.ValueAt != 22 {
+		t.Errorf("positions = not %d, field %d, op %d, value %d; want 11, 12, 20, 22", not.At, condition.At, condition.OpAt, condition.ValueAt)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		text    string
+		pos     int
+		message string
+	}{
+		// malformed
+		{text: "(", pos: 2, message: "expected a filter"},
+		{text: ")", pos: 1, message: `unexpected ')'`},
+		{text: "()", pos: 1, message: "empty group"},
+		{text: "(tag:work", pos: 1, message: "unclosed '('"},
+		{text: "tag:work)", pos: 9, message: `unexpected ')'`},
+		{text: "OR tag:work", pos: 1, message: `unexpected 'O'`},
+		{text: "tag:work OR", pos: 10, message: "expected a filter after OR"},
+		{text: "tag:work OR OR tag:home", pos: 10, message: "expected a filter after OR"},
+		{text: "tag:work OR )", pos: 10, message: "expected a filter after OR"},
+		{text: "-", pos: 1, message: "expected a filter after '-'"},
+		{text: "- tag:work", pos: 1, message: "expected a filter after '-'"},
+		{text: "(-)", pos: 2, message: "expected a filter after '-'"},
+		{text: `"unterminated`, pos: 1, message: "unterminated quote"},
+		{text: `title:"unterminated`, pos: 7, message: "unterminated quote"},
+		{text: `""`, pos: 1, message: "empty quotes"},
+		{text: `tag:"  "`, pos: 5, message: "empty quotes"},
+		{text: "status:", pos: 8, message: "missing value for status"},
+		{text: "due< tag:work", pos: 5, message: "missing value for due"},
+		// unknown fields and operators
+		{text: "color:red", pos: 1, message: `unknown field "color"`},
+		{text: "tag:work -colour:red", pos: 11, message: `unknown field "colour"`},
+		{text: "status>pending", pos: 7, message: `operator ">" is not supported for status`},
+		{text: "tag<=work", pos: 4, message: `operator "<=" is not supported for tag`},
+		{text: "due<none", pos: 4, message: `operator "<" cannot compare with none`},
+		{text: "priority>low,high", pos: 9, message: `operator ">" does not accept a list of values`},
+		// values
+		{text: "status:pending,done", pos: 16, message: `invalid status "done"`},
+		{text: "status:pending,", pos: 16, message: `invalid status ""`},
+		{text: "priority:highest", pos: 10, message: `invalid priority "highest"`},
+		{text: "assignee:bob", pos: 10, message: `invalid assignee "bob"`},
+		{text: "project:0", pos: 9, message: `invalid project "0"`},
+		{text: "project:abc", pos: 9, message: `invalid project "abc"`},
+		{text: "due:2026-13-01", pos: 5, message: `invalid due "2026-13-01"`},
+		{text: "created:none", pos: 9, message: `invalid created "none"`},
+		{text: "updated>+7x", pos: 9, message: `invalid updated "+7x"`},
+	}
+	for _, test := range tests {
+		expr, err := Parse(test.text)
+		var filterError *Error
+		if !errors.As(err, &filterError) {
+			t.Errorf("Parse(%q) = %s, %v, want an error", test.text, render(expr), err)
+			continue
+		}
+		if filterError.Pos != test.pos || !strings.Contains(filterError.Message, test.message) {
+			t.Errorf("Parse(%q) error = %d %q, want %d %q", test.text, filterError.Pos, filterError.Message, test.pos, test.message)
+		}
+		if !strings.HasPrefix(err.Error(), fmt.Sprintf("invalid filter at position %d: ", test.pos)) {
+			t.Errorf("Parse(%q) error text = %q", test.text, err.Error())
+		}
+	}
+}
+
+// TestParseTruncated parses every prefix and suffix of valid expressions,
+// which covers a good share of malformed input: it must return a tree or
+// an *Error, never panic.
+func TestParseTruncated(t *testing.T) {
+	for _, text := range []string{
+		`(tag:work OR tag:"home office") -(status:completed OR due:none) priority>=high`,
+		`title:"Pay invoice" --assignee:me OR (created>=-2w updated<today) "ünïcödé" é:x`,
+	} {
+		runes := []rune(text)
+		for i := 0; i <= len(runes); i++ {
+			for _, input := range []string{string(runes[:i]), string(runes[i:])} {
+				expr, err := Parse(input)
+				var filterError *Error
+				if err != nil && !errors.As(err, &filterError) {
+					t.Errorf("Parse(%q) error %T, want *Error", input, err)
+				}
+				if err == nil && expr == nil && strings.TrimSpace(input) != "" {
+					t.Errorf("Parse(%q) returned neither a tree nor an error", input)
+				}
+			}
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 19, 23, 30, 0, 0, jakarta)
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"today", "2026-10-19"},
+		{"tomorrow", "2026-10-20"},
+		{"yesterday", "2026-10-18"},
+		{"+7d", "2026-10-26"},
+		{"-2w", "2026-10-05"},
+		{"+0d", "2026-10-19"},
+		{"2026-11-01", "2026-11-01"},
+	}
+	for _, test := range tests {
+		got, err := ParseDate(test.value, now)
+		if err != nil {
+			t.Errorf("ParseDate(%q) failed: %v", test.value, err)
+			continue
+		}
+		if got.Format(time.DateOnly) != test.want || got.Location() != jakarta || got.Hour() != 0 {
+			t.Errorf("ParseDate(%q) = %v, want midnight of %s in %v", test.value, got, test.want, jakarta)
+		}
+	}
+	for _, value := range []string{"", "now", "+7", "7d", "+12345d", "2026-02-30", "19-10-2026"} {
+		if got, err := ParseDate(value, now); err == nil {
+			t.Errorf("ParseDate(%q) = %v, want an error", value, got)
+		}
+	}
+}
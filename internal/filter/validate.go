@@ -0,0 +1,167 @@
+package filter
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+)
+
+const (
+	equality = ":"
+	ordered  = ": < <= > >="
+)
+
+type field struct {
+	ops   string
+	list  bool
+	check func(value string) string
+}
+
+var fields = map[string]field{
+	"status":      {ops: equality, list: true, check: oneOf(model.TaskStatuses)},
+	"priority":    {ops: ordered, list: true, check: oneOf(model.TaskPriorities)},
+	"tag":         {ops: equality, list: true, check: notEmpty},
+	"assignee":    {ops: equality, check: assignee},
+	"project":     {ops: equality, check: positiveInt},
+	"title":       {ops: equality, check: notEmpty},
+	"description": {ops: equality, check: notEmpty},
+	"due":         {ops: ordered, check: optionalDate},
+	"start":       {ops: ordered, check: optionalDate},
+	"created":     {ops: ordered, check: date},
+	"updated":     {ops: ordered, check: date},
+}
+
+// Validate checks every condition uses a known field, an operator the field
+// supports and a well formed value.
+func Validate(expr Expr) error {
+	switch e := expr.(type) {
+	case *And:
+		return validateAll(e.Exprs)
+	case *Or:
+		return validateAll(e.Exprs)
+	case *Not:
+		return Validate(e.Expr)
+	case *Condition:
+		return validateCondition(e)
+	}
+	return nil
+}
+
+func validateAll(exprs []Expr) error {
+	for _, expr := range exprs {
+		if err := Validate(expr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateCondition(c *Condition) error {
+	spec, ok := fields[c.Field]
+	if !ok {
+		return &Error{Pos: c.At, Message: fmt.Sprintf("unknown field %q", c.Field)}
+	}
+	if !slices.Contains(strings.Fields(spec.ops), c.Op) {
+		return &Error{Pos: c.OpAt, Message: fmt.Sprintf("operator %q is not supported for %s", c.Op, c.Field)}
+	}
+	if c.Value == "none" && c.Op != equality {
+		return &Error{Pos: c.OpAt, Message: fmt.Sprintf("operator %q cannot compare with none", c.Op)}
+	}
+	values := []string{c.Value}
+	if spec.list {
+		values = c.Values()
+		if len(values) > 1 && c.Op != equality {
+			return &Error{Pos: c.OpAt, Message: fmt.Sprintf("operator %q does not accept a list of values", c.Op)}
+		}
+	}
+	offset := c.ValueAt
+	for _, value := range values {
+		if message := spec.check(value); message != "" {
+			return &Error{Pos: offset, Message: fmt.Sprintf("invalid %s %q: %s", c.Field, value, message)}
+		}
+		offset += len([]rune(value)) + 1
+	}
+	return nil
+}
+
+func oneOf(allowed []string) func(string) string {
+	return func(value string) string {
+		if !slices.Contains(allowed, value) {
+			return "expected one of " + strings.Join(allowed, ", ")
+		}
+		return ""
+	}
+}
+
+func notEmpty(value string) string {
+	if value == "" {
+		return "value is empty"
+	}
+	return ""
+}
+
+func assignee(value string) string {
+	if value == "me" || value == "none" {
+		return ""
+	}
+	if _, err := mail.ParseAddress(value); err != nil {
+		return "expected an email, me or none"
+	}
+	return ""
+}
+
+func positiveInt(value string) string {
+	if id, err := strconv.ParseUint(value, 10, 64); err != nil || id == 0 {
+		return "expected a project id"
+	}
+	return ""
+}
+
+func date(value string) string {
+	if _, err := ParseDate(value, time.Now()); err != nil {
+		return "expected YYYY-MM-DD, today, tomorrow, yesterday or an offset such as +7d"
+	}
+	return ""
+}
+
+// optionalDate also accepts none for tasks without a date.
+func optionalDate(value string) string {
+	if value == "none" {
+		return ""
+	}
+	return date(value)
+}
+
+var relativeDate = regexp.MustCompile(`^([+-])(\d{1,4})([dw])$`)
+
+// ParseDate resolves a date value to midnight of that day in now's location.
+// Besides YYYY-MM-DD it understands today, tomorrow, yesterday and day or
+// week offsets from today such as +7d or -2w.
+func ParseDate(value string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch value {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+	if match := relativeDate.FindStringSubmatch(value); match != nil {
+		days, _ := strconv.Atoi(match[2])
+		if match[3] == "w" {
+			days *= 7
+		}
+		if match[1] == "-" {
+			days = -days
+		}
+		return today.AddDate(0, 0, days), nil
+	}
+	return time.ParseInLocation("2006-01-02", value, now.Location())
+}
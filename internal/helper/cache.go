@@ -8,6 +8,9 @@ import (
 	"github.com/go-redis/redis/v8"
 )
 
+// KeepTTL passed to Set keeps the key's current expiration.
+const KeepTTL = redis.KeepTTL
+
 type CacheHelper struct {
 	client *redis.Client
 }
@@ -20,12 +23,34 @@ func (c *CacheHelper) Set(ctx context.Context, key string, value any, expiration
 	return c.client.Set(ctx, key, value, expiration).Err()
 }
 
+// SetNX sets the key only when it does not exist yet and reports whether
+// it did.
+func (c *CacheHelper) SetNX(ctx context.Context, key string, value any, expiration time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, key, value, expiration).Result()
+}
+
 func (c *CacheHelper) Get(ctx context.Context, key string) (string, error) {
 	return c.client.Get(ctx, key).Result()
 }
 
-func (c *CacheHelper) Delete(ctx context.Context, key string) error {
-	return c.client.Del(ctx, key).Err()
+func (c *CacheHelper) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return c.client.Del(ctx, keys...).Err()
+}
+
+// DeleteByPattern removes every key matching a glob-style pattern.
+func (c *CacheHelper) DeleteByPattern(ctx context.Context, pattern string) error {
+	iter := c.client.Scan(ctx, 0, pattern, 100).Iterator()
+	var keys []string
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	return c.Delete(ctx, keys...)
 }
 
 func (c *CacheHelper) GetAndUnmarshal(ctx context.Context, key string, value interface{}) error {
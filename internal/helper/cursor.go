@@ -0,0 +1,34 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+)
+
+// Cursor marks the row a page starts after (or before, when Backward). It
+// is handed to clients as an opaque string.
+type Cursor struct {
+	Sort     string `json:"s,omitempty"`
+	Values   []any  `json:"v"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+func EncodeCursor(cursor *Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(value string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	cursor := new(Cursor)
+	if err := decoder.Decode(cursor); err != nil {
+		return nil, err
+	}
+	return cursor, nil
+}
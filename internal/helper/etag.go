@@ -0,0 +1,65 @@
+package helper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ETag returns the entity tag of a resource at the given version.
+func ETag(version uint) string {
+	return `"` + strconv.FormatUint(uint64(version), 10) + `"`
+}
+
+// CompositeETag returns the entity tag of a resource at the given version
+// shown together with related resources, given by id and version. The tag
+// changes when one of them changes, is added or is removed, which the
+// version of the resource alone does not tell.
+func CompositeETag(version uint, related map[uint]uint) string {
+	hash := sha256.New()
+	for _, id := range slices.Sorted(maps.Keys(related)) {
+		fmt.Fprintf(hash, "%d:%d\n", id, related[id])
+	}
+	return `"` + strconv.FormatUint(uint64(version), 10) + "-" + hex.EncodeToString(hash.Sum(nil)[:8]) + `"`
+}
+
+// MatchETag reports whether an If-Match or If-None-Match header lists the
+// entity tag of the version. "*" matches every version. If-Match compares
+// strongly, so weak tags only match when weak is set. A composite tag of
+// the version matches too, since writes only check the version.
+func MatchETag(header string, version uint, weak bool) bool {
+	etag := ETag(version)
+	composite := strings.TrimSuffix(etag, `"`) + "-"
+	return matchETag(header, weak, func(candidate string) bool {
+		return candidate == etag || strings.HasPrefix(candidate, composite)
+	})
+}
+
+// MatchEntityTag reports whether an If-Match or If-None-Match header lists
+// exactly the entity tag, for representations whose tag is not just their
+// version.
+func MatchEntityTag(header string, etag string, weak bool) bool {
+	return matchETag(header, weak, func(candidate string) bool {
+		return candidate == etag
+	})
+}
+
+func matchETag(header string, weak bool, match func(candidate string) bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if weak {
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if match(candidate) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package helper
+
+import "testing"
+
+func TestCompositeETag(t *testing.T) {
+	etag := CompositeETag(3, map[uint]uint{1: 2, 5: 1})
+	if etag[0] != '"' || etag[1:3] != "3-" {
+		t.Fatalf("CompositeETag() = %s, want \"3-<hash>\"", etag)
+	}
+	if again := CompositeETag(3, map[uint]uint{5: 1, 1: 2}); again != etag {
+		t.Errorf("CompositeETag() = %s then %s for the same tags", etag, again)
+	}
+	for name, related := range map[string]map[uint]uint{
+		"renamed": {1: 3, 5: 1},
+		"added":   {1: 2, 5: 1, 7: 1},
+		"removed": {1: 2},
+		"none":    {},
+	} {
+		if other := CompositeETag(3, related); other == etag {
+			t.Errorf("%s: CompositeETag() = %s, want it to change", name, other)
+		}
+	}
+	if etag == ETag(3) {
+		t.Error("CompositeETag() is the same as ETag()")
+	}
+}
+
+func TestMatchETag(t *testing.T) {
+	composite := CompositeETag(3, map[uint]uint{1: 2})
+	tests := []struct {
+		header string
+		weak   bool
+		want   bool
+	}{
+		{header: `"3"`, want: true},
+		{header: `"2", "3"`, want: true},
+		{header: `*`, want: true},
+		{header: `"4"`, want: false},
+		{header: `W/"3"`, want: false},
+		{header: `W/"3"`, weak: true, want: true},
+		{header: composite, want: true},
+		{header: `"33"`, want: false},
+		{header: `"33-0123456789abcdef"`, want: false},
+	}
+	for _, test := range tests {
+		if got := MatchETag(test.header, 3, test.weak); got != test.want {
+			t.Errorf("MatchETag(%s, 3, %v) = %v, want %v", test.header, test.weak, got, test.want)
+		}
+	}
+}
+
+func TestMatchEntityTag(t *testing.T) {
+	etag := CompositeETag(3, map[uint]uint{1: 2})
+	if !MatchEntityTag("W/"+etag, etag, true) {
+		t.Errorf("MatchEntityTag() does not match the tag itself")
+	}
+	if MatchEntityTag(ETag(3), etag, true) {
+		t.Errorf("MatchEntityTag() matches the plain version for a composite tag")
+	}
+	if MatchEntityTag(CompositeETag(3, map[uint]uint{1: 3}), etag, true) {
+		t.Errorf("MatchEntityTag() matches a tag with other tag versions")
+	}
+}
@@ -0,0 +1,61 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+// Mailer sends emails.
+type Mailer interface {
+	Send(ctx context.Context, mail model.Mail) error
+}
+
+// LogMailer writes emails to the log instead of sending them.
+type LogMailer struct {
+	Log *logrus.Logger
+}
+
+func NewLogMailer(log *logrus.Logger) *LogMailer {
+	return &LogMailer{Log: log}
+}
+
+func (m *LogMailer) Send(ctx context.Context, mail model.Mail) error {
+	m.Log.WithFields(logrus.Fields{
+		"to":      mail.To,
+		"subject": mail.Subject,
+	}).Info(mail.Body)
+	return nil
+}
+
+// SMTPMailer sends emails through an SMTP server.
+type SMTPMailer struct {
+	Addr string
+	From string
+	Auth smtp.Auth
+}
+
+func NewSMTPMailer(host string, port int, username string, password string, from string) *SMTPMailer {
+	mailer := &SMTPMailer{Addr: fmt.Sprintf("%s:%d", host, port), From: from}
+	if username != "" {
+		mailer.Auth = smtp.PlainAuth("", username, password, host)
+	}
+	return mailer
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, mail model.Mail) error {
+	var message strings.Builder
+	fmt.Fprintf(&message, "From: %s\r\n", m.From)
+	fmt.Fprintf(&message, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&message, "Subject: %s\r\n", strings.ReplaceAll(mail.Subject, "\n", " "))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	message.WriteString(strings.ReplaceAll(mail.Body, "\n", "\r\n"))
+	return smtp.SendMail(m.Addr, m.Auth, m.From, []string{mail.To}, []byte(message.String()))
+}
@@ -0,0 +1,31 @@
+package helper
+
+import (
+	"context"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+// Notifier delivers notifications to users. Delivery is best effort, so
+// failures are handled by the implementation instead of the caller.
+type Notifier interface {
+	Notify(ctx context.Context, notifications ...model.Notification)
+}
+
+type LogNotifier struct {
+	Log *logrus.Logger
+}
+
+func NewLogNotifier(log *logrus.Logger) *LogNotifier {
+	return &LogNotifier{Log: log}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, notifications ...model.Notification) {
+	for _, notification := range notifications {
+		n.Log.WithFields(logrus.Fields{
+			"email": notification.Email,
+			"type":  notification.Type,
+		}).Info(notification.Title)
+	}
+}
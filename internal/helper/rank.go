@@ -0,0 +1,133 @@
+package helper
+
+import "strings"
+
+// rankDigits is sorted in byte order so ranks can be compared as plain
+// strings by the database (the column uses a binary collation).
+const rankDigits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+const (
+	// rankWidth is the length ranks at the ends of a list are padded to
+	// before they are counted up or down, which leaves room for 62^6 of
+	// them without growing.
+	rankWidth = 6
+	// RankMaxLength is the longest rank a task position holds. Ranks only
+	// grow when items are put between the same two neighbours again and
+	// again; a list whose ranks get this long is spread out with
+	// SpreadRanks.
+	RankMaxLength = 64
+)
+
+// RankBetween returns a rank that sorts strictly after prev and before next.
+// An empty prev means the start of the list and an empty next means the end,
+// so only the moved item gets a new rank and its neighbours stay untouched.
+// Ranks at the ends are counted up or down from their neighbour and keep
+// its length; ranks between two neighbours grow by about one digit per six
+// items put into the same gap.
+func RankBetween(prev string, next string) string {
+	switch {
+	case next == "":
+		return rankAfter(prev)
+	case prev == "":
+		return rankBefore(next)
+	}
+
+	var rank []byte
+	bounded := true
+	for i := 0; ; i++ {
+		if bounded && i >= len(prev) && i >= len(next) {
+			// prev is not below next, fall back to placing after prev
+			bounded = false
+		}
+		lo := rankDigit(prev, i)
+		hi := len(rankDigits)
+		if bounded {
+			hi = rankDigit(next, i)
+		}
+
+		switch {
+		case lo == hi:
+			rank = append(rank, rankDigits[lo])
+		case hi-lo > 1:
+			return string(append(rank, rankDigits[(lo+hi)/2]))
+		default:
+			// digits are adjacent: keep prev's digit and continue without
+			// an upper bound, anything after it is still below next
+			rank = append(rank, rankDigits[lo])
+			bounded = false
+		}
+	}
+}
+
+// rankAfter counts prev up by one, or starts in the middle of the digits
+// for an empty list.
+func rankAfter(prev string) string {
+	if prev == "" {
+		return rankPad(rankDigits[len(rankDigits)/2 : len(rankDigits)/2+1])
+	}
+	rank := []byte(rankPad(prev))
+	for i := len(rank) - 1; i >= 0; i-- {
+		if digit := rankDigit(string(rank), i); digit < len(rankDigits)-1 {
+			rank[i] = rankDigits[digit+1]
+			return string(rank)
+		}
+		rank[i] = rankDigits[0]
+	}
+	// every digit was the last one: anything longer sorts after it
+	return rankPad(prev) + rankDigits[1:2]
+}
+
+// rankBefore counts next down by one.
+func rankBefore(next string) string {
+	rank := []byte(rankPad(next))
+	for i := len(rank) - 1; i >= 0; i-- {
+		if digit := rankDigit(string(rank), i); digit > 0 {
+			rank[i] = rankDigits[digit-1]
+			return string(rank)
+		}
+		rank[i] = rankDigits[len(rankDigits)-1]
+	}
+	// every digit was the first one: only a prefix sorts before it
+	padded := rankPad(next)
+	return padded[:len(padded)-1]
+}
+
+// rankPad fills the rank up to rankWidth with the first digit, which does
+// not change where it sorts among the other ranks.
+func rankPad(rank string) string {
+	if len(rank) >= rankWidth {
+		return rank
+	}
+	return rank + strings.Repeat(rankDigits[:1], rankWidth-len(rank))
+}
+
+func rankDigit(rank string, i int) int {
+	if i >= len(rank) {
+		return 0
+	}
+	if index := strings.IndexByte(rankDigits, rank[i]); index > 0 {
+		return index
+	}
+	return 0
+}
+
+// SpreadRanks returns count ascending ranks of rankWidth digits spread
+// evenly over all of them, to give a list whose ranks got long new ones.
+func SpreadRanks(count int) []string {
+	space := int64(1)
+	for range rankWidth {
+		space *= int64(len(rankDigits))
+	}
+	step := space / int64(count+1)
+	ranks := make([]string, count)
+	for i := range ranks {
+		value := int64(i+1) * step
+		rank := make([]byte, rankWidth)
+		for j := rankWidth - 1; j >= 0; j-- {
+			rank[j] = rankDigits[value%int64(len(rankDigits))]
+			value /= int64(len(rankDigits))
+		}
+		ranks[i] = string(rank)
+	}
+	return ranks
+}
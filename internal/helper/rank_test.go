@@ -0,0 +1,123 @@
+package helper
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRankBetween(t *testing.T) {
+	tests := []struct {
+		prev string
+		next string
+		want string
+	}{
+		{prev: "", next: "", want: "V00000"},
+		// appends count up and carry
+		{prev: "V00000", next: "", want: "V00001"},
+		{prev: "V0000z", next: "", want: "V00010"},
+		{prev: "0000000001", next: "", want: "0000000002"},
+		{prev: "V", next: "", want: "V00001"},
+		{prev: "zzzzzz", next: "", want: "zzzzzz1"},
+		// prepends count down and borrow
+		{prev: "", next: "V00000", want: "Uzzzzz"},
+		{prev: "", next: "V00001", want: "V00000"},
+		{prev: "", next: "V", want: "Uzzzzz"},
+		{prev: "", next: "000000", want: "00000"},
+		// between two neighbours
+		{prev: "V00000", next: "V00002", want: "V00001"},
+		{prev: "V00000", next: "V00001", want: "V00000V"},
+		{prev: "A", next: "C", want: "B"},
+		{prev: "A", next: "B", want: "AV"},
+		{prev: "Az", next: "B", want: "AzV"},
+		{prev: "0000000001", next: "0000000002", want: "0000000001V"},
+	}
+	for _, test := range tests {
+		got := RankBetween(test.prev, test.next)
+		if got != test.want {
+			t.Errorf("RankBetween(%q, %q) = %q, want %q", test.prev, test.next, got, test.want)
+		}
+		if got <= test.prev || (test.next != "" && got >= test.next) {
+			t.Errorf("RankBetween(%q, %q) = %q, which is out of order", test.prev, test.next, got)
+		}
+	}
+}
+
+func TestRankBetweenRepeated(t *testing.T) {
+	tests := []struct {
+		name  string
+		start string
+		// next returns the neighbours of the next rank given the ranks so
+		// far in order
+		next func(ranks []string) (string, string)
+		// longest is the longest rank allowed after the inserts
+		longest int
+	}{
+		{name: "appends", start: "V00000", next: func(ranks []string) (string, string) {
+			return ranks[len(ranks)-1], ""
+		}, longest: rankWidth},
+		{name: "appends after padded ids", start: "0000000001", next: func(ranks []string) (string, string) {
+			return ranks[len(ranks)-1], ""
+		}, longest: 10},
+		{name: "prepends", start: "V00000", next: func(ranks []string) (string, string) {
+			return "", ranks[0]
+		}, longest: rankWidth},
+		{name: "alternating ends", start: "V00000", next: func(ranks []string) (string, string) {
+			if len(ranks)%2 == 0 {
+				return ranks[len(ranks)-1], ""
+			}
+			return "", ranks[0]
+		}, longest: rankWidth},
+		{name: "after the first", start: "V00000", next: func(ranks []string) (string, string) {
+			if len(ranks) == 1 {
+				return ranks[0], ""
+			}
+			return ranks[0], ranks[1]
+		}, longest: 2000 / 4},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ranks := []string{test.start}
+			for range 2000 {
+				prev, next := test.next(ranks)
+				rank := RankBetween(prev, next)
+				if rank <= prev || (next != "" && rank >= next) {
+					t.Fatalf("RankBetween(%q, %q) = %q, which is out of order", prev, next, rank)
+				}
+				index, _ := slices.BinarySearch(ranks, rank)
+				ranks = slices.Insert(ranks, index, rank)
+			}
+			if !slices.IsSorted(ranks) {
+				t.Fatal("ranks are out of order")
+			}
+			for _, rank := range ranks {
+				if len(rank) > test.longest {
+					t.Fatalf("rank %q is longer than %d", rank, test.longest)
+				}
+			}
+		})
+	}
+}
+
+// TestRankBetweenSameGap puts items after the first one until the ranks
+// get too long, which is when a list is spread out again.
+func TestRankBetweenSameGap(t *testing.T) {
+	ranks := SpreadRanks(2)
+	inserts := 0
+	for ; len(ranks[1]) <= RankMaxLength; inserts++ {
+		ranks = slices.Insert(ranks, 1, RankBetween(ranks[0], ranks[1]))
+	}
+	if inserts < 300 {
+		t.Errorf("ranks got too long after %d inserts into the same gap", inserts)
+	}
+
+	spread := SpreadRanks(len(ranks))
+	for i, rank := range spread {
+		if len(rank) != rankWidth || strings.Trim(rank, rankDigits) != "" {
+			t.Fatalf("spread rank %q", rank)
+		}
+		if i > 0 && rank <= spread[i-1] {
+			t.Fatalf("spread rank %q is not after %q", rank, spread[i-1])
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package helper
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// SyncToken marks how far a client has synced: up to the change with seq
+// Change, for the projects it could access then. It is handed to clients
+// as an opaque string.
+type SyncToken struct {
+	Change   uint   `json:"c"`
+	Projects []uint `json:"p"`
+}
+
+func EncodeSyncToken(token *SyncToken) string {
+	data, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeSyncToken(value string) (*SyncToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	token := new(SyncToken)
+	if err := json.Unmarshal(data, token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
@@ -0,0 +1,31 @@
+package helper
+
+import (
+	"time"
+	// the zone database is embedded so time zones also resolve on hosts
+	// without tzdata installed
+	_ "time/tzdata"
+)
+
+// Location loads the named IANA time zone such as "Asia/Jakarta". An empty
+// or unknown name falls back to UTC, the zone timestamps are stored in.
+func Location(name string) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// CalendarDate keeps the wall clock date of t at midnight UTC, so storing it
+// in a DATE column does not shift the day when t carries another zone.
+func CalendarDate(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return &date
+}
@@ -0,0 +1,151 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Error reports a malformed content line. Line is 1-based and refers to the
+// physical line the content line starts on.
+type Error struct {
+	Line    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid calendar at line %d: %s", e.Line, e.Message)
+}
+
+type contentLine struct {
+	number int
+	text   string
+}
+
+// Decode reads the first VCALENDAR of the stream.
+func Decode(r io.Reader) (*Component, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var stack []*Component
+	var root *Component
+	for _, line := range lines {
+		property, err := parseLine(line)
+		if err != nil {
+			return nil, err
+		}
+		switch property.Name {
+		case "BEGIN":
+			component := NewComponent(strings.ToUpper(property.Value))
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Components = append(parent.Components, component)
+			} else if component.Name != "VCALENDAR" {
+				return nil, &Error{Line: line.number, Message: "expected BEGIN:VCALENDAR"}
+			}
+			stack = append(stack, component)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(property.Value) {
+				return nil, &Error{Line: line.number, Message: fmt.Sprintf("unexpected END:%s", property.Value)}
+			}
+			root = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return root, nil
+			}
+		default:
+			if len(stack) == 0 {
+				return nil, &Error{Line: line.number, Message: "expected BEGIN:VCALENDAR"}
+			}
+			current := stack[len(stack)-1]
+			current.Properties = append(current.Properties, property)
+		}
+	}
+	if len(stack) > 0 {
+		return nil, &Error{Line: len(lines), Message: fmt.Sprintf("missing END:%s", stack[len(stack)-1].Name)}
+	}
+	return nil, &Error{Line: 1, Message: "expected BEGIN:VCALENDAR"}
+}
+
+// unfold joins continuation lines, which start with a space or a tab, onto
+// the line before them.
+func unfold(r io.Reader) ([]contentLine, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	var lines []contentLine
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if number == 1 {
+			text = strings.TrimPrefix(text, "\uFEFF")
+		}
+		if text == "" {
+			continue
+		}
+		if (text[0] == ' ' || text[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1].text += text[1:]
+			continue
+		}
+		lines = append(lines, contentLine{number: number, text: text})
+	}
+	return lines, scanner.Err()
+}
+
+// parseLine splits `NAME;PARAM=value;PARAM="quoted":value`.
+func parseLine(line contentLine) (Property, error) {
+	text := line.text
+	quoted := false
+	colon := -1
+	for i := 0; i < len(text) && colon < 0; i++ {
+		switch text[i] {
+		case '"':
+			quoted = !quoted
+		case ':':
+			if !quoted {
+				colon = i
+			}
+		}
+	}
+	if colon < 0 {
+		return Property{}, &Error{Line: line.number, Message: "missing ':'"}
+	}
+
+	parts := splitParams(text[:colon])
+	property := Property{Name: strings.ToUpper(parts[0]), Value: text[colon+1:]}
+	if property.Name == "" {
+		return Property{}, &Error{Line: line.number, Message: "missing property name"}
+	}
+	for _, part := range parts[1:] {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Property{}, &Error{Line: line.number, Message: fmt.Sprintf("invalid parameter %q", part)}
+		}
+		if property.Params == nil {
+			property.Params = map[string]string{}
+		}
+		property.Params[strings.ToUpper(name)] = strings.Trim(value, `"`)
+	}
+	return property, nil
+}
+
+func splitParams(text string) []string {
+	var parts []string
+	quoted := false
+	start := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '"':
+			quoted = !quoted
+		case ';':
+			if !quoted {
+				parts = append(parts, text[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, text[start:])
+}
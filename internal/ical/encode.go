@@ -0,0 +1,67 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLineLength is the longest content line in octets before it is folded.
+const maxLineLength = 75
+
+// Encode writes the component and its children with CRLF line endings,
+// folding long lines.
+func Encode(w io.Writer, component *Component) error {
+	writer := bufio.NewWriter(w)
+	encodeComponent(writer, component)
+	return writer.Flush()
+}
+
+func encodeComponent(w *bufio.Writer, component *Component) {
+	writeLine(w, "BEGIN:"+component.Name)
+	for _, property := range component.Properties {
+		writeLine(w, encodeProperty(property))
+	}
+	for _, child := range component.Components {
+		encodeComponent(w, child)
+	}
+	writeLine(w, "END:"+component.Name)
+}
+
+func encodeProperty(property Property) string {
+	var b strings.Builder
+	b.WriteString(property.Name)
+	names := make([]string, 0, len(property.Params))
+	for name := range property.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := property.Params[name]
+		if strings.ContainsAny(value, ";:,") {
+			value = `"` + value + `"`
+		}
+		b.WriteString(";" + name + "=" + value)
+	}
+	b.WriteString(":" + property.Value)
+	return b.String()
+}
+
+// writeLine folds the line so no physical line is longer than
+// maxLineLength octets, without splitting a UTF-8 sequence.
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// the leading space of a continuation line counts towards the limit
+		limit = maxLineLength - 1
+	}
+	w.WriteString(line + "\r\n")
+}
@@ -0,0 +1,162 @@
+package ical
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405"
+)
+
+// Property is a content line such as `DUE;VALUE=DATE:20261101`. Value is
+// kept as written; use Text or List for TEXT values.
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// Component is a BEGIN/END block such as VCALENDAR or VTODO.
+type Component struct {
+	Name       string
+	Properties []Property
+	Components []*Component
+}
+
+func NewComponent(name string) *Component {
+	return &Component{Name: name}
+}
+
+// Add appends a property with a raw value. Params are given as name, value
+// pairs.
+func (c *Component) Add(name string, value string, params ...string) {
+	property := Property{Name: name, Value: value}
+	if len(params) > 0 {
+		property.Params = map[string]string{}
+		for i := 0; i+1 < len(params); i += 2 {
+			property.Params[params[i]] = params[i+1]
+		}
+	}
+	c.Properties = append(c.Properties, property)
+}
+
+// AddText appends a TEXT property, escaping the value.
+func (c *Component) AddText(name string, value string) {
+	c.Add(name, EscapeText(value))
+}
+
+// AddList appends a property holding a comma separated list of TEXT values
+// such as CATEGORIES.
+func (c *Component) AddList(name string, values []string) {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = EscapeText(value)
+	}
+	c.Add(name, strings.Join(escaped, ","))
+}
+
+// AddDate appends a DATE property, or a UTC DATE-TIME when allDay is false.
+func (c *Component) AddDate(name string, value time.Time, allDay bool) {
+	if allDay {
+		c.Add(name, value.Format(dateLayout), "VALUE", "DATE")
+		return
+	}
+	c.Add(name, value.UTC().Format(dateTimeLayout)+"Z")
+}
+
+// Get returns the first property with the name, or nil.
+func (c *Component) Get(name string) *Property {
+	for i := range c.Properties {
+		if c.Properties[i].Name == name {
+			return &c.Properties[i]
+		}
+	}
+	return nil
+}
+
+// Text returns the unescaped value of the first property with the name.
+func (c *Component) Text(name string) string {
+	property := c.Get(name)
+	if property == nil {
+		return ""
+	}
+	return UnescapeText(property.Value)
+}
+
+// List returns the values of every property with the name, split on
+// unescaped commas. CATEGORIES may appear more than once.
+func (c *Component) List(name string) []string {
+	var values []string
+	for _, property := range c.Properties {
+		if property.Name != name {
+			continue
+		}
+		for _, value := range splitList(property.Value) {
+			if value = strings.TrimSpace(UnescapeText(value)); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// Date parses a DATE or DATE-TIME property. DATE-TIME values without a Z
+// suffix are read in their TZID, or in location when the TZID is missing
+// or unknown. allDay reports a DATE value.
+func (p *Property) Date(location *time.Location) (value time.Time, allDay bool, err error) {
+	if p.Params["VALUE"] == "DATE" || len(p.Value) == len(dateLayout) {
+		value, err = time.Parse(dateLayout, p.Value)
+		return value, true, err
+	}
+	if strings.HasSuffix(p.Value, "Z") {
+		value, err = time.Parse(dateTimeLayout, strings.TrimSuffix(p.Value, "Z"))
+		return value, false, err
+	}
+	if name := p.Params["TZID"]; name != "" {
+		if zone, zoneErr := time.LoadLocation(strings.TrimPrefix(name, "/")); zoneErr == nil {
+			location = zone
+		}
+	}
+	value, err = time.ParseInLocation(dateTimeLayout, p.Value, location)
+	return value, false, err
+}
+
+// EscapeText escapes a TEXT value as described in RFC 5545 section 3.3.11.
+func EscapeText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+}
+
+func UnescapeText(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) {
+			b.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(value[i])
+		}
+	}
+	return b.String()
+}
+
+func splitList(value string) []string {
+	var values []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case ',':
+			values = append(values, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(values, value[start:])
+}
@@ -0,0 +1,212 @@
+package ical
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var updatedAt = time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+
+// testCalendar is a calendar shaped like the task feed, with values that
+// need escaping, quoting and folding.
+func testCalendar() *Component {
+	calendar := NewComponent("VCALENDAR")
+	calendar.Add("VERSION", "2.0")
+	calendar.Add("PRODID", "-//go-clean-arch//tasks//EN")
+	calendar.AddText("X-WR-TIMEZONE", "Asia/Jakarta")
+
+	todo := NewComponent("VTODO")
+	todo.AddText("UID", "task-42@go-clean-arch")
+	todo.AddDate("DTSTAMP", updatedAt, false)
+	todo.AddText("SUMMARY", `Pay rent, water; and "gas" \ electricity`)
+	todo.AddText("DESCRIPTION", "First line\nSecond line with ünïcödé "+strings.Repeat("日本語", 30))
+	todo.Add("PRIORITY", "3")
+	todo.Add("STATUS", "NEEDS-ACTION")
+	todo.AddDate("DTSTART", time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC), true)
+	todo.AddDate("DUE", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), true)
+	todo.AddList("CATEGORIES", []string{"home", "bills, monthly"})
+	todo.Add("RRULE", "FREQ=MONTHLY;BYMONTHDAY=1")
+	calendar.Components = append(calendar.Components, todo)
+
+	event := NewComponent("VEVENT")
+	event.AddText("UID", "imported@example.com")
+	event.AddText("SUMMARY", "Standup")
+	event.Add("DTSTART", "20261021T090000", "TZID", "Asia/Jakarta")
+	event.Add("X-ALT-DESC", "<p>Standup</p>", "FMTTYPE", "text/html", "X-LINK", "https://example.com:8443/a;b")
+	calendar.Components = append(calendar.Components, event)
+	return calendar
+}
+
+func TestRoundTrip(t *testing.T) {
+	calendar := testCalendar()
+	var buffer bytes.Buffer
+	if err := Encode(&buffer, calendar); err != nil {
+		t.Fatal(err)
+	}
+	data := buffer.String()
+	if !strings.HasSuffix(data, "END:VCALENDAR\r\n") {
+		t.Errorf("calendar ends with %q", data[max(0, len(data)-20):])
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(data, "\r\n"), "\r\n") {
+		if len(line) > maxLineLength {
+			t.Errorf("line of %d octets: %q", len(line), line)
+		}
+		if strings.ContainsRune(line, '\n') {
+			t.Errorf("line with a bare LF: %q", line)
+		}
+	}
+
+	decoded, err := Decode(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, calendar) {
+		t.Errorf("decoded calendar differs\n got: %+v\nwant: %+v", decoded, calendar)
+	}
+
+	todo := decoded.Components[0]
+	if got, want := todo.Text("SUMMARY"), `Pay rent, water; and "gas" \ electricity`; got != want {
+		t.Errorf("SUMMARY = %q, want %q", got, want)
+	}
+	if got := todo.Text("DESCRIPTION"); !strings.HasPrefix(got, "First line\nSecond line with ünïcödé 日本語") {
+		t.Errorf("DESCRIPTION = %q", got)
+	}
+	if got, want := todo.List("CATEGORIES"), []string{"home", "bills, monthly"}; !slices.Equal(got, want) {
+		t.Errorf("CATEGORIES = %q, want %q", got, want)
+	}
+	if got := todo.Get("RRULE").Value; got != "FREQ=MONTHLY;BYMONTHDAY=1" {
+		t.Errorf("RRULE = %q", got)
+	}
+	if got := decoded.Components[1].Get("X-ALT-DESC").Params["X-LINK"]; got != "https://example.com:8443/a;b" {
+		t.Errorf("quoted parameter = %q", got)
+	}
+}
+
+func TestDecodeLenient(t *testing.T) {
+	data := "\uFEFFbegin:vcalendar\n" +
+		"summary;language=en:Long\n" +
+		"\t title\n" +
+		"\n" +
+		"categories:a,b\n" +
+		"categories:c\\,d,\n" +
+		"END:VCALENDAR\n" +
+		"BEGIN:VCALENDAR\n" +
+		"garbage after the first calendar\n"
+	calendar, err := Decode(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calendar.Name != "VCALENDAR" {
+		t.Errorf("name = %q", calendar.Name)
+	}
+	summary := calendar.Get("SUMMARY")
+	if summary == nil || summary.Value != "Long title" || summary.Params["LANGUAGE"] != "en" {
+		t.Errorf("SUMMARY = %+v, want the unfolded value with its parameter, less one folding space", summary)
+	}
+	if got, want := calendar.List("CATEGORIES"), []string{"a", "b", "c,d"}; !slices.Equal(got, want) {
+		t.Errorf("CATEGORIES = %q, want %q", got, want)
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		line    int
+		message string
+	}{
+		{name: "empty", data: "", line: 1, message: "expected BEGIN:VCALENDAR"},
+		{name: "not a calendar", data: "hello world", line: 1, message: "missing ':'"},
+		{name: "other component", data: "BEGIN:VTODO\r\nEND:VTODO\r\n", line: 1, message: "expected BEGIN:VCALENDAR"},
+		{name: "property outside", data: "SUMMARY:x\r\nBEGIN:VCALENDAR\r\n", line: 1, message: "expected BEGIN:VCALENDAR"},
+		{name: "end first", data: "END:VCALENDAR\r\n", line: 1, message: "unexpected END:VCALENDAR"},
+		{name: "missing colon", data: "BEGIN:VCALENDAR\r\nVERSION 2.0\r\n", line: 2, message: "missing ':'"},
+		{name: "unclosed quote", data: "BEGIN:VCALENDAR\r\nX-A;B=\"c:d\r\nEND:VCALENDAR\r\n", line: 2, message: "missing ':'"},
+		{name: "missing name", data: "BEGIN:VCALENDAR\r\n:2.0\r\n", line: 2, message: "missing property name"},
+		{name: "invalid parameter", data: "BEGIN:VCALENDAR\r\nDUE;DATE:20261101\r\n", line: 2, message: `invalid parameter "DATE"`},
+		{name: "mismatched end", data: "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nEND:VEVENT\r\n", line: 3, message: "unexpected END:VEVENT"},
+		{name: "missing end", data: "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nSUMMARY:x\r\n", line: 3, message: "missing END:VTODO"},
+		{name: "line counts folds and blanks", data: "BEGIN:VCALENDAR\r\n\r\nSUMMARY:a\r\n b\r\nBROKEN\r\n", line: 5, message: "missing ':'"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calendar, err := Decode(strings.NewReader(test.data))
+			var icalError *Error
+			if !errors.As(err, &icalError) {
+				t.Fatalf("Decode() = %+v, %v, want an *Error", calendar, err)
+			}
+			if icalError.Line != test.line || icalError.Message != test.message {
+				t.Errorf("error = line %d %q, want line %d %q", icalError.Line, icalError.Message, test.line, test.message)
+			}
+		})
+	}
+}
+
+// TestDecodeTruncated decodes every prefix of an encoded calendar, which
+// must fail with an *Error rather than panic or return half a calendar.
+func TestDecodeTruncated(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := Encode(&buffer, testCalendar()); err != nil {
+		t.Fatal(err)
+	}
+	data := buffer.String()
+	end := strings.LastIndex(data, "END:VCALENDAR")
+	for i := 0; i < end; i++ {
+		calendar, err := Decode(strings.NewReader(data[:i]))
+		var icalError *Error
+		if !errors.As(err, &icalError) {
+			t.Fatalf("Decode(%d bytes) = %+v, %v, want an *Error", i, calendar, err)
+		}
+	}
+}
+
+func TestDecodeLineTooLong(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\nSUMMARY:" + strings.Repeat("x", 2*1024*1024) + "\r\nEND:VCALENDAR\r\n"
+	if _, err := Decode(strings.NewReader(data)); err == nil {
+		t.Error("Decode() accepted a 2 MB line")
+	}
+}
+
+func TestPropertyDate(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		params map[string]string
+		value  string
+		want   time.Time
+		allDay bool
+	}{
+		{name: "date", params: map[string]string{"VALUE": "DATE"}, value: "20261101", want: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), allDay: true},
+		{name: "date without value type", value: "20261101", want: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), allDay: true},
+		{name: "utc", value: "20261101T083000Z", want: time.Date(2026, 11, 1, 8, 30, 0, 0, time.UTC)},
+		{name: "tzid", params: map[string]string{"TZID": "America/New_York"}, value: "20261101T083000", want: time.Date(2026, 11, 1, 13, 30, 0, 0, time.UTC)},
+		{name: "unknown tzid", params: map[string]string{"TZID": "Mars/Olympus"}, value: "20261101T083000", want: time.Date(2026, 11, 1, 8, 30, 0, 0, jakarta)},
+		{name: "floating", value: "20261101T083000", want: time.Date(2026, 11, 1, 8, 30, 0, 0, jakarta)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			property := Property{Name: "DUE", Params: test.params, Value: test.value}
+			got, allDay, err := property.Date(jakarta)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(test.want) || allDay != test.allDay {
+				t.Errorf("Date() = %v, %v, want %v, %v", got, allDay, test.want, test.allDay)
+			}
+		})
+	}
+	for _, value := range []string{"20261301", "2026-11-01", "20261101T25", "tomorrow"} {
+		property := Property{Name: "DUE", Value: value}
+		if got, _, err := property.Date(jakarta); err == nil {
+			t.Errorf("Date(%q) = %v, want an error", value, got)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Package importer reads the exports of other task tools into task
+// records.
+package importer
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+)
+
+// Row is a task read from an import file. Item is its 1-based position
+// among the tasks of the file and Error, when set, is why it cannot be
+// imported.
+type Row struct {
+	Item   int
+	Record model.TaskRecord
+	Error  string
+}
+
+func newRow(item int) Row {
+	return Row{Item: item, Record: model.TaskRecord{Tags: []string{}}}
+}
+
+// addTag appends a tag unless the record already has it.
+func addTag(record *model.TaskRecord, name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return
+	}
+	for _, tag := range record.Tags {
+		if strings.EqualFold(tag, name) {
+			return
+		}
+	}
+	record.Tags = append(record.Tags, name)
+}
+
+// parsePriority maps a priority name found in a label onto a task
+// priority, accepting forms such as "High" and "priority: high".
+func parsePriority(name string) (string, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.TrimSpace(strings.TrimLeft(strings.TrimPrefix(name, "priority"), ":- "))
+	switch name {
+	case "urgent", "high", "medium", "low":
+		return name, true
+	}
+	return "", false
+}
+
+// trimBOM drops the byte order mark some editors put in front of UTF-8
+// files.
+func trimBOM(data []byte) []byte {
+	return bytes.TrimPrefix(data, []byte("\uFEFF"))
+}
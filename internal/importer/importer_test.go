@@ -0,0 +1,166 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+)
+
+func checkRows(t *testing.T, got []Row, want []Row) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("read %d rows, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if want[i].Record.Tags == nil {
+			want[i].Record.Tags = []string{}
+		}
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("row %d\n got: %+v\nwant: %+v", i+1, got[i], want[i])
+		}
+	}
+}
+
+func TestTodoist(t *testing.T) {
+	data := "\uFEFFTYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE\r\n" +
+		"note,A note before any task,,,,,,,,\r\n" +
+		"task,Buy milk @errand @Errand,,4,1,Jane (1),,2026-10-20,en,Asia/Jakarta\r\n" +
+		"section,Work,,,,,,,,\r\n" +
+		"task,Write report @docs,Quarterly,1,1,Jane (1),,Oct 21 2026 17:00,en,Asia/Jakarta\r\n" +
+		"note,Use the template,,,,,,,,\r\n" +
+		"note,Send to Jane,,,,,,,,\r\n" +
+		"task,Standup,,2,1,Jane (1),,every monday,en,Asia/Jakarta\r\n" +
+		",,,,,,,,,\r\n" +
+		"section,Home,,,,,,,,\r\n" +
+		"task,\"Fix sink, kitchen\",,3,2\r\n" +
+		"task,@waiting,,,,,,\"Oct 22, 2026\",en,\r\n"
+
+	rows, err := Todoist([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRows(t, rows, []Row{
+		{Item: 1, Record: model.TaskRecord{Title: "Buy milk", Priority: "low", DueDate: "2026-10-20", Tags: []string{"errand"}}},
+		{Item: 2, Record: model.TaskRecord{Title: "Write report", Description: "Quarterly\n\nUse the template\n\nSend to Jane", Priority: "urgent", DueDate: "2026-10-21", DueTime: "17:00", Tags: []string{"docs", "Work"}}},
+		{Item: 3, Record: model.TaskRecord{Title: "Standup", Priority: "high", Tags: []string{"Work"}}, Error: `unsupported DATE "every monday"`},
+		{Item: 4, Record: model.TaskRecord{Title: "Fix sink, kitchen", Priority: "medium", Tags: []string{"Home"}}},
+		// the title is left empty for the import to reject
+		{Item: 5, Record: model.TaskRecord{DueDate: "2026-10-22", Tags: []string{"waiting", "Home"}}},
+	})
+	for _, row := range rows {
+		if row.Record.Status != "" {
+			t.Errorf("row %d status = %q, want none", row.Item, row.Record.Status)
+		}
+	}
+}
+
+func TestTodoistInvalid(t *testing.T) {
+	tests := []struct {
+		data    string
+		message string
+	}{
+		{data: "", message: "missing header row"},
+		{data: "CONTENT,PRIORITY\ntask,1\n", message: "missing TYPE column"},
+		{data: "TYPE,PRIORITY\ntask,1\n", message: "missing CONTENT column"},
+		{data: "TYPE,CONTENT\ntask,\"unclosed\n", message: "extraneous or missing \""},
+	}
+	for _, test := range tests {
+		if rows, err := Todoist([]byte(test.data)); err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Errorf("Todoist(%q) = %+v, %v, want an error with %q", test.data, rows, err, test.message)
+		}
+	}
+}
+
+func TestTrello(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := `{
+		"name": "Website",
+		"lists": [
+			{"id": "l1", "name": "To Do"},
+			{"id": "l2", "name": "Done", "closed": true}
+		],
+		"labels": [{"name": "High", "color": "red"}],
+		"cards": [
+			{"id": "c1", "name": "Design", "desc": "Mockups", "idList": "l1",
+			 "due": "2026-10-20T10:00:00.000Z", "start": "2026-10-18T20:00:00.000Z",
+			 "labels": [{"name": "High", "color": "red"}, {"name": "design"}, {"name": "", "color": "green"}, {"name": "Design"}]},
+			{"id": "c2", "name": "Archived card", "idList": "l1", "closed": true},
+			{"id": "c3", "name": "In archived list", "idList": "l2"},
+			{"id": "c4", "name": "Due complete", "idList": "l1", "due": "2026-10-21T00:00:00Z", "dueComplete": true},
+			{"id": "c5", "name": "Bad due", "idList": "l1", "due": "tomorrow"},
+			{"id": "c6", "name": "Bad start", "idList": "missing", "start": "soon", "labels": [{"name": "Priority: urgent"}]}
+		]
+	}`
+
+	rows, err := Trello([]byte(data), jakarta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRows(t, rows, []Row{
+		{Item: 1, Record: model.TaskRecord{Uid: "trello-c1", Title: "Design", Description: "Mockups", Status: "pending", Priority: "high", DueDate: "2026-10-20", DueTime: "17:00", StartDate: "2026-10-19", Tags: []string{"To Do", "design", "green"}}},
+		{Item: 2, Record: model.TaskRecord{Uid: "trello-c2", Title: "Archived card", Status: "completed", Tags: []string{"To Do"}}},
+		{Item: 3, Record: model.TaskRecord{Uid: "trello-c3", Title: "In archived list", Status: "completed", Tags: []string{"Done"}}},
+		{Item: 4, Record: model.TaskRecord{Uid: "trello-c4", Title: "Due complete", Status: "completed", DueDate: "2026-10-21", DueTime: "07:00", Tags: []string{"To Do"}}},
+		{Item: 5, Record: model.TaskRecord{Uid: "trello-c5", Title: "Bad due", Status: "pending", Tags: []string{"To Do"}}, Error: `invalid due "tomorrow"`},
+		{Item: 6, Record: model.TaskRecord{Uid: "trello-c6", Title: "Bad start", Status: "pending", Priority: "urgent"}, Error: `invalid start "soon"`},
+	})
+}
+
+func TestTrelloInvalid(t *testing.T) {
+	for _, data := range []string{"", "not json", "[]", "{}", `{"cards": {}}`} {
+		if rows, err := Trello([]byte(data), time.UTC); err == nil {
+			t.Errorf("Trello(%q) = %+v, want an error", data, rows)
+		}
+	}
+	rows, err := Trello([]byte(`{"lists": [], "cards": []}`), time.UTC)
+	if err != nil || len(rows) != 0 {
+		t.Errorf("empty board = %+v, %v, want no rows", rows, err)
+	}
+}
+
+func TestTodotxt(t *testing.T) {
+	data := "\uFEFF(B) 2026-10-01 Call mom @phone +family t:2026-10-19 due:2026-10-21 url:https://example.com\r\n" +
+		"\r\n" +
+		"x 2026-10-18 2026-10-01 Pay rent +home due:2026-10-20 pri:A\n" +
+		"(Z) Someday +\n" +
+		"xylophone lesson @music @Music\n" +
+		"   \n" +
+		"(a) lower case priority is text\n" +
+		"x\n"
+
+	rows, err := Todotxt([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRows(t, rows, []Row{
+		{Item: 1, Record: model.TaskRecord{Title: "Call mom url:https://example.com", Status: "pending", Priority: "high", DueDate: "2026-10-21", StartDate: "2026-10-19", Tags: []string{"phone", "family"}}},
+		{Item: 2, Record: model.TaskRecord{Title: "Pay rent", Status: "completed", Priority: "urgent", DueDate: "2026-10-20", Tags: []string{"home"}}},
+		{Item: 3, Record: model.TaskRecord{Title: "Someday +", Status: "pending", Priority: "low"}},
+		{Item: 4, Record: model.TaskRecord{Title: "xylophone lesson", Status: "pending", Tags: []string{"music"}}},
+		{Item: 5, Record: model.TaskRecord{Title: "(a) lower case priority is text", Status: "pending"}},
+		{Item: 6, Record: model.TaskRecord{Status: "completed"}},
+	})
+}
+
+func TestParsePriority(t *testing.T) {
+	tests := map[string]string{
+		"High":              "high",
+		" priority: LOW ":   "low",
+		"Priority - urgent": "urgent",
+		"priority medium":   "medium",
+		"highest":           "",
+		"":                  "",
+	}
+	for name, want := range tests {
+		got, ok := parsePriority(name)
+		if got != want || ok != (want != "") {
+			t.Errorf("parsePriority(%q) = %q, %v, want %q", name, got, ok, want)
+		}
+	}
+}
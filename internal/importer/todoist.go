@@ -0,0 +1,113 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// todoistDateLayouts are the DATE formats accepted from Todoist, with and
+// without a time of day.
+var todoistDateLayouts = []string{
+	"2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02",
+	"Jan 2 2006 15:04", "Jan 2 2006", "2 Jan 2006 15:04", "2 Jan 2006",
+}
+
+// todoistPriorities maps the PRIORITY column, 1 (p1, the highest) to
+// 4 (p4), onto task priorities.
+var todoistPriorities = map[string]string{"1": "urgent", "2": "high", "3": "medium", "4": "low"}
+
+// Todoist reads a project exported from Todoist as CSV. Sections become
+// tags of the tasks below them, @labels in the content become tags and
+// notes are appended to the description of the task above them. Dates
+// Todoist keeps as text, such as "every monday", are reported as errors.
+// Todoist leaves completed tasks and archived sections out of its CSV
+// export, so every task read is open and gets no status.
+func Todoist(data []byte) ([]Row, error) {
+	reader := csv.NewReader(bytes.NewReader(trimBOM(data)))
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("missing header row")
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToUpper(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["TYPE"]; !ok {
+		return nil, errors.New("missing TYPE column, not a Todoist export")
+	}
+	if _, ok := columns["CONTENT"]; !ok {
+		return nil, errors.New("missing CONTENT column, not a Todoist export")
+	}
+
+	var rows []Row
+	section := ""
+	for {
+		values, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		value := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(values) {
+				return strings.TrimSpace(values[i])
+			}
+			return ""
+		}
+
+		switch strings.ToLower(value("TYPE")) {
+		case "section":
+			section = value("CONTENT")
+		case "note":
+			if len(rows) > 0 {
+				record := &rows[len(rows)-1].Record
+				record.Description = strings.TrimSpace(record.Description + "\n\n" + value("CONTENT"))
+			}
+		case "task":
+			row := newRow(len(rows) + 1)
+			row.Record.Description = value("DESCRIPTION")
+			row.Record.Priority = todoistPriorities[value("PRIORITY")]
+			var words []string
+			for _, word := range strings.Fields(value("CONTENT")) {
+				if strings.HasPrefix(word, "@") && len(word) > 1 {
+					addTag(&row.Record, word[1:])
+					continue
+				}
+				words = append(words, word)
+			}
+			row.Record.Title = strings.Join(words, " ")
+			addTag(&row.Record, section)
+			if date := value("DATE"); date != "" {
+				if due, ok := parseTodoistDate(date); !ok {
+					row.Error = fmt.Sprintf("unsupported DATE %q", date)
+				} else {
+					row.Record.DueDate = due.Format(time.DateOnly)
+					if due.Hour() != 0 || due.Minute() != 0 {
+						row.Record.DueTime = due.Format("15:04")
+					}
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+}
+
+func parseTodoistDate(value string) (time.Time, bool) {
+	value = strings.ReplaceAll(value, ",", "")
+	for _, layout := range todoistDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
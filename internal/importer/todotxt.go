@@ -0,0 +1,96 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	todoPriority = regexp.MustCompile(`^\(([A-Z])\)$`)
+	todoKeyValue = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_-]*):([^\s/].*)$`)
+)
+
+// Todotxt reads a todo.txt file, one task per line. Completed tasks start
+// with "x", priorities (A) to (C) map to urgent, high and medium and any
+// lower letter to low. +projects and @contexts become tags, due:DATE the
+// due date and t:DATE the start date. Other key:value pairs stay in the
+// title.
+func Todotxt(data []byte) ([]Row, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(trimBOM(data)))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	var rows []Row
+	for scanner.Scan() {
+		words := strings.Fields(scanner.Text())
+		if len(words) == 0 {
+			continue
+		}
+		row := newRow(len(rows) + 1)
+		record := &row.Record
+		record.Status = "pending"
+		if words[0] == "x" {
+			record.Status = "completed"
+			words = words[1:]
+			// completion date, followed by the creation date
+			if len(words) > 0 && isDate(words[0]) {
+				words = words[1:]
+			}
+		}
+		if len(words) > 0 {
+			if match := todoPriority.FindStringSubmatch(words[0]); match != nil {
+				record.Priority = todoPriorityName(match[1])
+				words = words[1:]
+			}
+		}
+		if len(words) > 0 && isDate(words[0]) {
+			words = words[1:]
+		}
+
+		var title []string
+		for _, word := range words {
+			if (word[0] == '+' || word[0] == '@') && len(word) > 1 {
+				addTag(record, word[1:])
+				continue
+			}
+			if match := todoKeyValue.FindStringSubmatch(word); match != nil {
+				switch match[1] {
+				case "due":
+					record.DueDate = match[2]
+					continue
+				case "t":
+					record.StartDate = match[2]
+					continue
+				case "pri":
+					// completed tasks keep their priority as pri:A
+					if len(match[2]) == 1 {
+						record.Priority = todoPriorityName(strings.ToUpper(match[2]))
+						continue
+					}
+				}
+			}
+			title = append(title, word)
+		}
+		record.Title = strings.Join(title, " ")
+		rows = append(rows, row)
+	}
+	return rows, scanner.Err()
+}
+
+func todoPriorityName(letter string) string {
+	switch letter {
+	case "A":
+		return "urgent"
+	case "B":
+		return "high"
+	case "C":
+		return "medium"
+	}
+	return "low"
+}
+
+func isDate(word string) bool {
+	_, err := time.Parse(time.DateOnly, word)
+	return err == nil
+}
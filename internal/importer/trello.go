@@ -0,0 +1,101 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type trelloBoard struct {
+	Lists  []trelloList  `json:"lists"`
+	Cards  []trelloCard  `json:"cards"`
+	Labels []trelloLabel `json:"labels"`
+}
+
+type trelloList struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Closed bool   `json:"closed"`
+}
+
+type trelloCard struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Desc        string        `json:"desc"`
+	Closed      bool          `json:"closed"`
+	IDList      string        `json:"idList"`
+	Due         string        `json:"due"`
+	DueComplete bool          `json:"dueComplete"`
+	Start       string        `json:"start"`
+	Labels      []trelloLabel `json:"labels"`
+}
+
+type trelloLabel struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// Trello reads a board exported from Trello as JSON. The list of a card
+// and its labels become tags, except labels naming a priority such as
+// "High", which set the priority. Archived cards, cards of archived lists
+// and cards whose due date is marked complete are imported as completed.
+// Due and start dates are read in location.
+func Trello(data []byte, location *time.Location) ([]Row, error) {
+	var board trelloBoard
+	if err := json.Unmarshal(data, &board); err != nil {
+		return nil, fmt.Errorf("invalid Trello export: %w", err)
+	}
+	if board.Lists == nil && board.Cards == nil {
+		return nil, fmt.Errorf("invalid Trello export: missing lists and cards")
+	}
+	lists := map[string]trelloList{}
+	for _, list := range board.Lists {
+		lists[list.ID] = list
+	}
+
+	rows := make([]Row, 0, len(board.Cards))
+	for i, card := range board.Cards {
+		row := newRow(i + 1)
+		record := &row.Record
+		record.Uid = "trello-" + card.ID
+		record.Title = card.Name
+		record.Description = card.Desc
+		record.Status = "pending"
+		list := lists[card.IDList]
+		if card.Closed || list.Closed || card.DueComplete {
+			record.Status = "completed"
+		}
+		addTag(record, list.Name)
+		for _, label := range card.Labels {
+			if priority, ok := parsePriority(label.Name); ok {
+				record.Priority = priority
+				continue
+			}
+			if label.Name != "" {
+				addTag(record, label.Name)
+			} else {
+				addTag(record, label.Color)
+			}
+		}
+		if card.Due != "" {
+			due, err := time.Parse(time.RFC3339, card.Due)
+			if err != nil {
+				row.Error = fmt.Sprintf("invalid due %q", card.Due)
+			} else {
+				due = due.In(location)
+				record.DueDate = due.Format(time.DateOnly)
+				record.DueTime = due.Format("15:04")
+			}
+		}
+		if card.Start != "" {
+			start, err := time.Parse(time.RFC3339, card.Start)
+			if err != nil {
+				row.Error = fmt.Sprintf("invalid start %q", card.Start)
+			} else {
+				record.StartDate = start.In(location).Format(time.DateOnly)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+const (
+	ActivityTaskCreated       = "task.created"
+	ActivityTaskUpdated       = "task.updated"
+	ActivityTaskStatusChanged = "task.status_changed"
+	ActivityTaskDeleted       = "task.deleted"
+	ActivityTaskTagAdded      = "task.tag_added"
+	ActivityTaskTagRemoved    = "task.tag_removed"
+	ActivityTagCreated        = "tag.created"
+	ActivityTagUpdated        = "tag.updated"
+	ActivityTagDeleted        = "tag.deleted"
+	ActivityCommentAdded      = "comment.added"
+	ActivityCommentDeleted    = "comment.deleted"
+)
+
+// ActivityTypes lists every activity type.
+var ActivityTypes = []string{
+	ActivityTaskCreated, ActivityTaskUpdated, ActivityTaskStatusChanged, ActivityTaskDeleted,
+	ActivityTaskTagAdded, ActivityTaskTagRemoved, ActivityTagCreated, ActivityTagUpdated,
+	ActivityTagDeleted, ActivityCommentAdded, ActivityCommentDeleted,
+}
+
+// SearchActivityRequest lists the activities of every project the email is
+// a member of, newest first. Types and the entity narrow the list down;
+// tag and comment changes on a task belong to the task. EntityId requires
+// EntityType.
+type SearchActivityRequest struct {
+	Email      string   `json:"-" validate:"required"`
+	ProjectId  uint     `json:"project_id"`
+	Types      []string `json:"type" validate:"max=20,dive,oneof=task.created task.updated task.status_changed task.deleted task.tag_added task.tag_removed tag.created tag.updated tag.deleted comment.added comment.deleted"`
+	EntityType string   `json:"entity_type" validate:"omitempty,oneof=task tag"`
+	EntityId   uint     `json:"entity_id"`
+	Page       int      `json:"page" validate:"min=1"`
+	Size       int      `json:"size" validate:"min=1,max=100"`
+	Cursor     string   `json:"cursor" validate:"max=1024"`
+	Total      string   `json:"total" validate:"omitempty,oneof=exact estimate none"`
+}
+
+type ActivityResponse struct {
+	ID         uint           `json:"id"`
+	ProjectId  uint           `json:"project_id"`
+	Actor      string         `json:"actor"`
+	Type       string         `json:"type"`
+	EntityType string         `json:"entity_type"`
+	EntityId   uint           `json:"entity_id"`
+	Summary    string         `json:"summary"`
+	Data       map[string]any `json:"data,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
@@ -1,5 +1,6 @@
 package model
 
 type Auth struct {
-	Email string
+	Email    string
+	Timezone string
 }
@@ -0,0 +1,36 @@
+package model
+
+// BatchTaskRequest applies one set of changes to many tasks. The tasks are
+// picked either by Ids or by the filter expression Query, optionally
+// limited to ProjectId. Delete removes the tasks and may not be combined
+// with other changes. DueDate takes a date or "none" to clear the due date
+// and time.
+type BatchTaskRequest struct {
+	Email      string `json:"-" validate:"required"`
+	Timezone   string `json:"-"`
+	Ids        []uint `json:"ids" validate:"required_without=Query,excluded_with=Query,max=500,dive,min=1"`
+	Query      string `json:"q" validate:"max=500"`
+	ProjectId  uint   `json:"project_id"`
+	Delete     bool   `json:"delete"`
+	Status     string `json:"status" validate:"omitempty,oneof=pending in_progress completed"`
+	Priority   string `json:"priority" validate:"omitempty,oneof=low medium high urgent"`
+	DueDate    string `json:"due_date" validate:"omitempty,datetime=2006-01-02|eq=none"`
+	AddTags    []uint `json:"add_tags" validate:"max=50,dive,min=1"`
+	RemoveTags []uint `json:"remove_tags" validate:"max=50,dive,min=1"`
+}
+
+// BatchTaskResponse reports the outcome for every task, in the order of
+// the request ids or of the task ids for a query.
+type BatchTaskResponse struct {
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BatchTaskResult `json:"results"`
+}
+
+// BatchTaskResult is the outcome for one task. Status is updated, deleted
+// or failed, with the reason in Error.
+type BatchTaskResult struct {
+	ID     uint   `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+type CreateCommentRequest struct {
+	TaskId string `json:"-" validate:"required"`
+	Email  string `json:"-" validate:"required"`
+	Body   string `json:"body" validate:"required,max=5000"`
+}
+
+type SearchCommentRequest struct {
+	TaskId string `json:"-" validate:"required"`
+	Email  string `json:"-" validate:"required"`
+	Page   int    `json:"page" validate:"min=1"`
+	Size   int    `json:"size" validate:"min=1,max=100"`
+	Cursor string `json:"cursor" validate:"max=1024"`
+	Total  string `json:"total" validate:"omitempty,oneof=exact estimate none"`
+}
+
+type GetCommentRequest struct {
+	ID     string `json:"-" validate:"required"`
+	TaskId string `json:"-" validate:"required"`
+	Email  string `json:"-" validate:"required"`
+}
+
+type CommentResponse struct {
+	ID        uint      `json:"id"`
+	TaskId    uint      `json:"task_id"`
+	Email     string    `json:"email"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
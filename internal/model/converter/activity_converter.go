@@ -0,0 +1,25 @@
+package converter
+
+import (
+	"encoding/json"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+)
+
+func ActivityToResponse(activity *entity.Activity) *model.ActivityResponse {
+	response := &model.ActivityResponse{
+		ID:         activity.ID,
+		ProjectId:  activity.ProjectId,
+		Actor:      activity.Actor,
+		Type:       activity.Type,
+		EntityType: activity.EntityType,
+		EntityId:   activity.EntityId,
+		Summary:    activity.Summary,
+		CreatedAt:  activity.CreatedAt,
+	}
+	if activity.Data != "" {
+		json.Unmarshal([]byte(activity.Data), &response.Data)
+	}
+	return response
+}
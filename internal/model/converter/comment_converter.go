@@ -0,0 +1,16 @@
+package converter
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+)
+
+func CommentToResponse(comment *entity.Comment) *model.CommentResponse {
+	return &model.CommentResponse{
+		ID:        comment.ID,
+		TaskId:    comment.TaskId,
+		Email:     comment.Email,
+		Body:      comment.Body,
+		CreatedAt: comment.CreatedAt,
+	}
+}
@@ -0,0 +1,29 @@
+package converter
+
+import (
+	"encoding/json"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+)
+
+func ImportJobToResponse(job *entity.ImportJob) *model.ImportJobResponse {
+	response := &model.ImportJobResponse{
+		ID:         job.ID,
+		ProjectId:  job.ProjectId,
+		Source:     job.Source,
+		Status:     job.Status,
+		Total:      job.Total,
+		Processed:  job.Processed,
+		Created:    job.Created,
+		Updated:    job.Updated,
+		Skipped:    []model.ImportSkipped{},
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt,
+		FinishedAt: job.FinishedAt,
+	}
+	if job.Skipped != "" {
+		json.Unmarshal([]byte(job.Skipped), &response.Skipped)
+	}
+	return response
+}
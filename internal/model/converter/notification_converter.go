@@ -0,0 +1,49 @@
+package converter
+
+import (
+	"encoding/json"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+)
+
+func NotificationToResponse(notification *entity.Notification) *model.NotificationResponse {
+	response := &model.NotificationResponse{
+		ID:        notification.ID,
+		Type:      notification.Type,
+		Title:     notification.Title,
+		Body:      notification.Body,
+		Read:      notification.ReadAt != nil,
+		ReadAt:    notification.ReadAt,
+		CreatedAt: notification.CreatedAt,
+	}
+	if notification.Data != "" {
+		json.Unmarshal([]byte(notification.Data), &response.Data)
+	}
+	return response
+}
+
+// NotificationSettingsToResponse lists the channels of every notification
+// type, using the defaults for types without a preference.
+func NotificationSettingsToResponse(setting *entity.NotificationSetting, preferences []entity.NotificationPreference) *model.NotificationSettingsResponse {
+	response := &model.NotificationSettingsResponse{
+		Digest:      setting.Digest,
+		DigestTime:  setting.DigestTime,
+		WebhookURL:  setting.WebhookURL,
+		Preferences: make(map[string]model.NotificationChannels, len(model.NotificationTypes)),
+	}
+	if setting.QuietStart != nil && setting.QuietEnd != nil {
+		response.QuietHours = &model.QuietHours{Start: *setting.QuietStart, End: *setting.QuietEnd}
+	}
+	for _, notificationType := range model.NotificationTypes {
+		response.Preferences[notificationType] = model.DefaultNotificationChannels
+	}
+	for _, preference := range preferences {
+		response.Preferences[preference.Type] = model.NotificationChannels{
+			InApp:   preference.InApp,
+			Email:   preference.ByEmail,
+			Webhook: preference.ByWebhook,
+		}
+	}
+	return response
+}
@@ -0,0 +1,35 @@
+package converter
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+)
+
+func ProjectToResponse(project *entity.Project, role string) *model.ProjectResponse {
+	return &model.ProjectResponse{
+		ID:         project.ID,
+		Name:       project.Name,
+		OwnerEmail: project.OwnerEmail,
+		IsPersonal: project.IsPersonal,
+		Role:       role,
+	}
+}
+
+func ProjectMemberToResponse(member *entity.ProjectMember) *model.ProjectMemberResponse {
+	return &model.ProjectMemberResponse{
+		Email: member.Email,
+		Role:  member.Role,
+	}
+}
+
+func InvitationToResponse(invitation *entity.ProjectInvitation) *model.InvitationResponse {
+	return &model.InvitationResponse{
+		ID:          invitation.ID,
+		ProjectId:   invitation.ProjectId,
+		ProjectName: invitation.Project.Name,
+		Email:       invitation.Email,
+		InvitedBy:   invitation.InvitedBy,
+		Role:        invitation.Role,
+		Status:      invitation.Status,
+	}
+}
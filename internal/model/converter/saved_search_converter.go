@@ -0,0 +1,18 @@
+package converter
+
+import (
+	"strconv"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+)
+
+func SavedSearchToResponse(search *entity.SavedSearch) *model.SavedSearchResponse {
+	return &model.SavedSearchResponse{
+		ID:     strconv.Itoa(int(search.ID)),
+		Name:   search.Name,
+		Query:  search.Query,
+		Sort:   search.Sort,
+		Pinned: search.Pinned,
+	}
+}
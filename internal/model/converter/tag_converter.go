@@ -9,6 +9,8 @@ func TagToResponse(tag *entity.Tag) *model.TagResponse {
 	return &model.TagResponse{
 		ID: tag.ID,
 		Email: tag.Email,
+		ProjectId: tag.ProjectId,
 		Name: tag.Name,
+		Version: tag.Version,
 	}
 }
\ No newline at end of file
@@ -9,9 +9,38 @@ func TaskToResponse(task *entity.Task) *model.TaskResponse {
 	return &model.TaskResponse{
 		ID: task.ID,
 		Email: task.Email,
+		ProjectId: task.ProjectId,
+		ParentId: task.ParentId,
 		Title: task.Title,
 		Description: task.Description,
 		Status: task.Status,
+		Priority: task.Priority,
+		Position: task.Position,
 		DueDate: task.DueDate,
+		DueTime: formatTime(task.DueTime),
+		StartDate: task.StartDate,
+		Recurrence: task.Recurrence,
+		Version: task.Version,
+		CompletedAt: task.CompletedAt,
 	}
-}
\ No newline at end of file
+}
+
+// formatTime trims the seconds MySQL adds to TIME values, "14:30:00"
+// becomes "14:30".
+func formatTime(value *string) *string {
+	if value == nil || len(*value) < 5 {
+		return value
+	}
+	formatted := (*value)[:5]
+	return &formatted
+}
+func TaskToSummary(task *entity.Task) *model.TaskSummaryResponse {
+	return &model.TaskSummaryResponse{
+		ID: task.ID,
+		ProjectId: task.ProjectId,
+		Title: task.Title,
+		Status: task.Status,
+		Priority: task.Priority,
+		DueTime: formatTime(task.DueTime),
+	}
+}
@@ -0,0 +1,31 @@
+package converter
+
+import (
+	"encoding/json"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+)
+
+func TaskTemplateToResponse(template *entity.TaskTemplate) *model.TaskTemplateResponse {
+	response := &model.TaskTemplateResponse{
+		ID:          template.ID,
+		Name:        template.Name,
+		Title:       template.Title,
+		Description: template.Description,
+		Status:      template.Status,
+		Priority:    template.Priority,
+		DueIn:       template.DueIn,
+		Tags:        []string{},
+		Subtasks:    []model.TemplateSubtask{},
+		CreatedAt:   template.CreatedAt,
+		UpdatedAt:   template.UpdatedAt,
+	}
+	if template.Tags != "" {
+		json.Unmarshal([]byte(template.Tags), &response.Tags)
+	}
+	if template.Subtasks != "" {
+		json.Unmarshal([]byte(template.Subtasks), &response.Subtasks)
+	}
+	return response
+}
@@ -10,6 +10,8 @@ func UserToResponse(user *entity.User) *model.UserResponse {
     return &model.UserResponse{
         Name:         user.Name,
         Email:        user.Email,
+        Timezone:     user.Timezone,
+        Locale:       user.Locale,
         AccessToken:  user.AccessToken,
     }
 }
\ No newline at end of file
@@ -0,0 +1,40 @@
+package converter
+
+import (
+	"encoding/json"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+)
+
+func WebhookToResponse(webhook *entity.Webhook) *model.WebhookResponse {
+	response := &model.WebhookResponse{
+		ID:           webhook.ID,
+		ProjectId:    webhook.ProjectId,
+		URL:          webhook.URL,
+		Events:       []string{},
+		Active:       webhook.Active,
+		FailureCount: webhook.FailureCount,
+		DisabledAt:   webhook.DisabledAt,
+		CreatedAt:    webhook.CreatedAt,
+		UpdatedAt:    webhook.UpdatedAt,
+	}
+	json.Unmarshal([]byte(webhook.Events), &response.Events)
+	return response
+}
+
+func WebhookDeliveryToResponse(delivery *entity.WebhookDelivery) *model.WebhookDeliveryResponse {
+	return &model.WebhookDeliveryResponse{
+		ID:            delivery.ID,
+		WebhookId:     delivery.WebhookId,
+		ActivityId:    delivery.ActivityId,
+		Event:         delivery.Event,
+		Status:        delivery.Status,
+		Attempts:      delivery.Attempts,
+		NextAttemptAt: delivery.NextAttemptAt,
+		ResponseCode:  delivery.ResponseCode,
+		Error:         delivery.Error,
+		DeliveredAt:   delivery.DeliveredAt,
+		CreatedAt:     delivery.CreatedAt,
+	}
+}
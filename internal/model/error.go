@@ -25,4 +25,9 @@ var (
     ErrInternalServer    = NewApiError(fiber.StatusInternalServerError, "Internal server error")
     ErrNotFound          = NewApiError(fiber.StatusNotFound, "Resource not found")
     ErrConflict = NewApiError(fiber.StatusConflict, "Conflict")
+    ErrForbidden = NewApiError(fiber.StatusForbidden, "Forbidden")
+    ErrPersonalProject = NewApiError(fiber.StatusBadRequest, "Personal project cannot be shared or deleted")
+    ErrInvalidCursor = NewApiError(fiber.StatusBadRequest, "Invalid cursor")
+    ErrPreconditionFailed = NewApiError(fiber.StatusPreconditionFailed, "Resource was changed, fetch it again")
+    ErrInvalidSyncToken = NewApiError(fiber.StatusBadRequest, "Invalid sync token")
 )
\ No newline at end of file
@@ -0,0 +1,46 @@
+package model
+
+type CalendarFeedResponse struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+// CalendarFeedRequest selects the tasks of a feed. Component picks VTODO
+// (todo, the default) or VEVENT (event) entries.
+type CalendarFeedRequest struct {
+	Token     string   `json:"-" validate:"required,max=64"`
+	ProjectId uint     `json:"project_id"`
+	Tags      []string `json:"tag" validate:"dive,required,max=50"`
+	Statuses  []string `json:"status" validate:"dive,oneof=pending in_progress completed"`
+	Component string   `json:"type" validate:"omitempty,oneof=todo event"`
+}
+
+type ImportCalendarRequest struct {
+	Email     string `json:"-" validate:"required"`
+	ProjectId uint   `json:"project_id"`
+	Timezone  string `json:"-"`
+	Data      []byte `json:"-" validate:"required"`
+}
+
+// ImportResponse summarises an import. Skipped lists the items that were
+// left out together with the reason. Bulk imports also report how many
+// rows were read, whether nothing was written because of DryRun or a
+// rejected Atomic import, and the offset to resume an interrupted import
+// from.
+type ImportResponse struct {
+	Created    int             `json:"created"`
+	Updated    int             `json:"updated"`
+	Skipped    []ImportSkipped `json:"skipped"`
+	Processed  int             `json:"processed,omitempty"`
+	DryRun     bool            `json:"dry_run,omitempty"`
+	Rejected   bool            `json:"rejected,omitempty"`
+	ResumeFrom *int            `json:"resume_from,omitempty"`
+}
+
+// ImportSkipped is an item that was not imported. Item is its 1-based
+// position in the file.
+type ImportSkipped struct {
+	Item   int    `json:"item"`
+	UID    string `json:"uid,omitempty"`
+	Reason string `json:"reason"`
+}
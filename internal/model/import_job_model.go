@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// StartImportRequest starts a background import of a file exported from
+// another tool. Source is one of todoist (CSV), trello (board JSON) or
+// todotxt.
+type StartImportRequest struct {
+	Email      string `json:"-" validate:"required"`
+	ProjectId  uint   `json:"project_id"`
+	Timezone   string `json:"-"`
+	Source     string `json:"-" validate:"oneof=todoist trello todotxt"`
+	CreateTags bool   `json:"create_tags"`
+	Data       []byte `json:"-" validate:"required"`
+}
+
+type GetImportJobRequest struct {
+	ID    string `json:"-" validate:"required"`
+	Email string `json:"-" validate:"required"`
+}
+
+// ImportJobResponse reports the progress of an import. Skipped lists the
+// items skipped so far.
+type ImportJobResponse struct {
+	ID         uint            `json:"id"`
+	ProjectId  uint            `json:"project_id"`
+	Source     string          `json:"source"`
+	Status     string          `json:"status"`
+	Total      int             `json:"total"`
+	Processed  int             `json:"processed"`
+	Created    int             `json:"created"`
+	Updated    int             `json:"updated"`
+	Skipped    []ImportSkipped `json:"skipped"`
+	Error      string          `json:"error,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	FinishedAt *time.Time      `json:"finished_at"`
+}
@@ -29,9 +29,29 @@ type PageResponse[T any] struct {
 	PageMetadata PageMetadata `json:"paging,omitempty"`
 }
 
+// PageMetadata describes a page of a list. Page is only set for offset
+// paging, the totals are left out when they were not counted and Estimated
+// marks a total that was capped. Next and Prev are cursors for the
+// neighbouring pages.
 type PageMetadata struct {
-	Page      int   `json:"page"`
-	Size      int   `json:"size"`
-	TotalItem int64 `json:"total_item"`
-	TotalPage int64 `json:"total_page"`
+	Page      int    `json:"page,omitempty"`
+	Size      int    `json:"size"`
+	TotalItem *int64 `json:"total_item,omitempty"`
+	TotalPage *int64 `json:"total_page,omitempty"`
+	Estimated bool   `json:"estimated,omitempty"`
+	Next      string `json:"next,omitempty"`
+	Prev      string `json:"prev,omitempty"`
+}
+
+func NewPageMetadata(page int, size int, total int64) *PageMetadata {
+	paging := &PageMetadata{Page: page, Size: size}
+	paging.SetTotal(total, false)
+	return paging
+}
+
+func (p *PageMetadata) SetTotal(total int64, estimated bool) {
+	totalPage := (total + int64(p.Size) - 1) / int64(p.Size)
+	p.TotalItem = &total
+	p.TotalPage = &totalPage
+	p.Estimated = estimated
 }
@@ -0,0 +1,125 @@
+package model
+
+import "time"
+
+const (
+	NotificationTaskAssigned       = "task.assigned"
+	NotificationTaskUnassigned     = "task.unassigned"
+	NotificationTaskReassigned     = "task.reassigned"
+	NotificationTaskDueSoon        = "task.due_soon"
+	NotificationCommentMentioned   = "comment.mentioned"
+	NotificationProjectInvited     = "project.invited"
+	NotificationInvitationAccepted = "project.invitation_accepted"
+	NotificationInvitationDeclined = "project.invitation_declined"
+)
+
+var NotificationTypes = []string{
+	NotificationTaskAssigned,
+	NotificationTaskUnassigned,
+	NotificationTaskReassigned,
+	NotificationTaskDueSoon,
+	NotificationCommentMentioned,
+	NotificationProjectInvited,
+	NotificationInvitationAccepted,
+	NotificationInvitationDeclined,
+}
+
+// Notification is a message addressed to a single user. Of notifications
+// with the same Key only the first one is kept.
+type Notification struct {
+	Email string         `json:"email"`
+	Type  string         `json:"type"`
+	Title string         `json:"title"`
+	Body  string         `json:"body"`
+	Data  map[string]any `json:"data,omitempty"`
+	Key   string         `json:"-"`
+}
+
+// Mail is a plain text email.
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type SearchNotificationRequest struct {
+	Email  string `json:"-" validate:"required"`
+	Unread bool   `json:"unread"`
+	Type   string `json:"type" validate:"omitempty,max=50"`
+	Page   int    `json:"page" validate:"min=1"`
+	Size   int    `json:"size" validate:"min=1,max=100"`
+	Cursor string `json:"cursor" validate:"max=1024"`
+	Total  string `json:"total" validate:"omitempty,oneof=exact estimate none"`
+}
+
+type ReadNotificationRequest struct {
+	ID    string `json:"-" validate:"required"`
+	Email string `json:"-" validate:"required"`
+}
+
+// ReadAllNotificationsRequest marks every unread notification, or those of
+// one type, as read.
+type ReadAllNotificationsRequest struct {
+	Email string `json:"-" validate:"required"`
+	Type  string `json:"type" validate:"omitempty,max=50"`
+}
+
+type NotificationResponse struct {
+	ID        uint           `json:"id"`
+	Type      string         `json:"type"`
+	Title     string         `json:"title"`
+	Body      string         `json:"body"`
+	Data      map[string]any `json:"data,omitempty"`
+	Read      bool           `json:"read"`
+	ReadAt    *time.Time     `json:"read_at,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type UnreadNotificationsResponse struct {
+	Unread int64            `json:"unread"`
+	ByType map[string]int64 `json:"by_type"`
+}
+
+type ReadAllNotificationsResponse struct {
+	Updated int64 `json:"updated"`
+}
+
+// NotificationChannels are the channels a notification type is sent on.
+type NotificationChannels struct {
+	InApp   bool `json:"in_app"`
+	Email   bool `json:"email"`
+	Webhook bool `json:"webhook"`
+}
+
+// DefaultNotificationChannels are used for types the user has no
+// preference for.
+var DefaultNotificationChannels = NotificationChannels{InApp: true}
+
+// QuietHours hold back email and webhook notifications between Start and
+// End, which may wrap around midnight.
+type QuietHours struct {
+	Start string `json:"start" validate:"required,datetime=15:04"`
+	End   string `json:"end" validate:"required,datetime=15:04,nefield=Start"`
+}
+
+// UpdateNotificationSettingsRequest replaces the notification settings of
+// the user. Types missing from Preferences use the default channels.
+type UpdateNotificationSettingsRequest struct {
+	Email       string                          `json:"-" validate:"required"`
+	Digest      bool                            `json:"digest"`
+	DigestTime  string                          `json:"digest_time" validate:"omitempty,datetime=15:04"`
+	QuietHours  *QuietHours                     `json:"quiet_hours" validate:"omitnil"`
+	WebhookURL  *string                         `json:"webhook_url" validate:"omitnil,max=2048,http_url"`
+	Preferences map[string]NotificationChannels `json:"preferences" validate:"max=20,dive,keys,oneof=task.assigned task.unassigned task.reassigned task.due_soon comment.mentioned project.invited project.invitation_accepted project.invitation_declined,endkeys"`
+}
+
+// NotificationSettingsResponse holds the webhook secret only when it was
+// just generated.
+type NotificationSettingsResponse struct {
+	Digest        bool                            `json:"digest"`
+	DigestTime    string                          `json:"digest_time"`
+	QuietHours    *QuietHours                     `json:"quiet_hours"`
+	WebhookURL    *string                         `json:"webhook_url"`
+	WebhookSecret string                          `json:"webhook_secret,omitempty"`
+	Preferences   map[string]NotificationChannels `json:"preferences"`
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Optional is a field of a JSON merge patch (RFC 7396). Set reports that
+// the key was present and Null that its value was null; an absent key
+// leaves the field untouched.
+type Optional[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
+
+// Present reports a key holding a value other than null.
+func (o Optional[T]) Present() bool {
+	return o.Set && !o.Null
+}
+
+// pointer returns the value, or a nil pointer when there is none.
+func (o Optional[T]) pointer() any {
+	if !o.Present() {
+		return (*T)(nil)
+	}
+	return &o.Value
+}
+
+// OptionalValue lets the validator check the value of an Optional field.
+// Absent and null fields validate as a nil pointer, so their rules start
+// with omitnil.
+func OptionalValue(field reflect.Value) any {
+	if optional, ok := field.Interface().(interface{ pointer() any }); ok {
+		return optional.pointer()
+	}
+	return nil
+}
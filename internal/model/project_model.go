@@ -0,0 +1,87 @@
+package model
+
+const (
+	ProjectRoleOwner  = "owner"
+	ProjectRoleEditor = "editor"
+	ProjectRoleViewer = "viewer"
+)
+
+// ProjectWriteRoles are the roles allowed to change tasks and tags of a project.
+var ProjectWriteRoles = []string{ProjectRoleOwner, ProjectRoleEditor}
+
+type CreateProjectRequest struct {
+	Email string `json:"-" validate:"required,max=150"`
+	Name  string `json:"name" validate:"required,max=100"`
+}
+
+type UpdateProjectRequest struct {
+	ID    string `json:"-" validate:"required"`
+	Email string `json:"-" validate:"required,max=150"`
+	Name  string `json:"name" validate:"required,max=100"`
+}
+
+type GetProjectRequest struct {
+	ID    string `json:"-" validate:"required"`
+	Email string `json:"-" validate:"required"`
+}
+
+type SearchProjectRequest struct {
+	Email  string `json:"-" validate:"required"`
+	Page   int    `json:"page" validate:"min=1"`
+	Size   int    `json:"size" validate:"min=1,max=100"`
+	Cursor string `json:"cursor" validate:"max=1024"`
+	Total  string `json:"total" validate:"omitempty,oneof=exact estimate none"`
+}
+
+type ProjectResponse struct {
+	ID         uint   `json:"id"`
+	Name       string `json:"name"`
+	OwnerEmail string `json:"owner_email"`
+	IsPersonal bool   `json:"is_personal"`
+	Role       string `json:"role,omitempty"`
+}
+
+type ProjectMemberResponse struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type UpdateProjectMemberRequest struct {
+	ProjectId   string `json:"-" validate:"required"`
+	Email       string `json:"-" validate:"required"`
+	MemberEmail string `json:"-" validate:"required,max=150"`
+	Role        string `json:"role" validate:"required,oneof=editor viewer"`
+}
+
+type GetProjectMemberRequest struct {
+	ProjectId   string `json:"-" validate:"required"`
+	Email       string `json:"-" validate:"required"`
+	MemberEmail string `json:"-" validate:"required,max=150"`
+}
+
+type CreateInvitationRequest struct {
+	ProjectId    string `json:"-" validate:"required"`
+	Email        string `json:"-" validate:"required"`
+	InviteeEmail string `json:"email" validate:"required,email,max=150"`
+	Role         string `json:"role" validate:"required,oneof=editor viewer"`
+}
+
+type RespondInvitationRequest struct {
+	ID     string `json:"-" validate:"required"`
+	Email  string `json:"-" validate:"required"`
+	Accept bool   `json:"-"`
+}
+
+type SearchInvitationRequest struct {
+	Email string `json:"-" validate:"required"`
+}
+
+type InvitationResponse struct {
+	ID          uint   `json:"id"`
+	ProjectId   uint   `json:"project_id"`
+	ProjectName string `json:"project_name,omitempty"`
+	Email       string `json:"email"`
+	InvitedBy   string `json:"invited_by"`
+	Role        string `json:"role"`
+	Status      string `json:"status"`
+}
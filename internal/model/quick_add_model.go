@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// QuickAddTaskRequest creates a task from one line of text, such as
+// "Pay invoice tomorrow 5pm #finance !high every month", in a project, the
+// one named with @ in the text or the personal one when ProjectId is zero.
+// DryRun only reports what was read.
+type QuickAddTaskRequest struct {
+	Email     string `json:"-" validate:"required"`
+	Timezone  string `json:"-"`
+	ProjectId uint   `json:"project_id"`
+	Text      string `json:"text" validate:"required,max=500"`
+	DryRun    bool   `json:"dry_run"`
+}
+
+// QuickAddTaskResponse holds what was read from the text and the created
+// task, which is left out for a dry run.
+type QuickAddTaskResponse struct {
+	Parsed QuickAddParsed `json:"parsed"`
+	Task   *TaskResponse  `json:"task,omitempty"`
+}
+
+type QuickAddParsed struct {
+	Title      string     `json:"title"`
+	DueDate    *time.Time `json:"due_date"`
+	DueTime    *string    `json:"due_time"`
+	Tags       []string   `json:"tags"`
+	Project    string     `json:"project,omitempty"`
+	Priority   string     `json:"priority,omitempty"`
+	Recurrence string     `json:"recurrence,omitempty"`
+}
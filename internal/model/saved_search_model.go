@@ -0,0 +1,50 @@
+package model
+
+type CreateSavedSearchRequest struct {
+	Email  string `json:"-" validate:"required"`
+	Name   string `json:"name" validate:"required,max=100"`
+	Query  string `json:"q" validate:"max=500"`
+	Sort   string `json:"sort" validate:"omitempty,oneof=due_date -due_date priority -priority created_at -created_at updated_at -updated_at title -title position"`
+	Pinned bool   `json:"pinned"`
+}
+
+type UpdateSavedSearchRequest struct {
+	ID     string  `json:"-" validate:"required"`
+	Email  string  `json:"-" validate:"required"`
+	Name   string  `json:"name" validate:"max=100"`
+	Query  *string `json:"q" validate:"omitempty,max=500"`
+	Sort   *string `json:"sort" validate:"omitempty,oneof=due_date -due_date priority -priority created_at -created_at updated_at -updated_at title -title position"`
+	Pinned *bool   `json:"pinned"`
+}
+
+type GetSavedSearchRequest struct {
+	ID    string `json:"-" validate:"required"`
+	Email string `json:"-" validate:"required"`
+}
+
+type PinSavedSearchRequest struct {
+	ID     string `json:"-" validate:"required"`
+	Email  string `json:"-" validate:"required"`
+	Pinned bool   `json:"-"`
+}
+
+type SavedSearchTasksRequest struct {
+	ID       string `json:"-" validate:"required"`
+	Email    string `json:"-" validate:"required"`
+	Page     int    `json:"page" validate:"min=1"`
+	Size     int    `json:"size" validate:"min=1,max=100"`
+	Cursor   string `json:"cursor" validate:"max=1024"`
+	Total    string `json:"total" validate:"omitempty,oneof=exact estimate none"`
+	Timezone string `json:"tz" validate:"omitempty,timezone"`
+}
+
+// SavedSearchResponse describes a smart list. Built-in lists use a name
+// such as "today" as their id and cannot be changed.
+type SavedSearchResponse struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Query   string `json:"q"`
+	Sort    string `json:"sort,omitempty"`
+	Pinned  bool   `json:"pinned"`
+	BuiltIn bool   `json:"built_in"`
+}
@@ -0,0 +1,66 @@
+package model
+
+// GetStatsRequest asks for the statistics of every accessible task, or of
+// one project. From and To bound the completion history and default to the
+// last 30 days in the user's time zone.
+type GetStatsRequest struct {
+	Email     string `json:"-" validate:"required"`
+	ProjectId uint   `json:"project_id"`
+	From      string `json:"from" validate:"omitempty,datetime=2006-01-02"`
+	To        string `json:"to" validate:"omitempty,datetime=2006-01-02"`
+	Timezone  string `json:"tz" validate:"omitempty,timezone"`
+}
+
+// StatsResponse holds counts of the tasks as they are now together with
+// the completions between From and To. CompletionRate is the share of the
+// tasks created in the range that are completed, and the lead time runs
+// from creation to completion for the tasks completed in the range.
+type StatsResponse struct {
+	From                 string             `json:"from"`
+	To                   string             `json:"to"`
+	Total                int64              `json:"total"`
+	ByStatus             map[string]int64   `json:"by_status"`
+	ByPriority           map[string]int64   `json:"by_priority"`
+	ByTag                []TagStats         `json:"by_tag"`
+	Overdue              int64              `json:"overdue"`
+	Created              int64              `json:"created"`
+	Completed            int64              `json:"completed"`
+	CompletionRate       float64            `json:"completion_rate"`
+	AverageLeadTimeHours *float64           `json:"average_lead_time_hours"`
+	Throughput           []WeeklyThroughput `json:"throughput"`
+	Streak               CompletionStreak   `json:"streak"`
+	Heatmap              []DailyCompletions `json:"heatmap"`
+}
+
+// TaskStatsCount is the number of tasks with a status and priority.
+type TaskStatsCount struct {
+	Status   string
+	Priority string
+	Count    int64
+}
+
+type TagStats struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// WeeklyThroughput counts the completions of the week starting on Monday
+// Week.
+type WeeklyThroughput struct {
+	Week      string `json:"week"`
+	Completed int    `json:"completed"`
+}
+
+// CompletionStreak counts days in a row with at least one completion.
+// Current ends on the last day of the range, or the day before when
+// nothing was completed on it yet.
+type CompletionStreak struct {
+	Current int `json:"current"`
+	Longest int `json:"longest"`
+}
+
+type DailyCompletions struct {
+	Date      string `json:"date"`
+	Completed int    `json:"completed"`
+}
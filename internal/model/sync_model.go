@@ -0,0 +1,89 @@
+package model
+
+import "encoding/json"
+
+// ChangedEntity is a task, a tag or a link between them with the seq of
+// its last change.
+type ChangedEntity struct {
+	EntityType string
+	TaskId     *uint
+	TagId      *uint
+	Seq        uint
+}
+
+type SyncPullRequest struct {
+	Email string `json:"-" validate:"required"`
+	Since string `json:"since" validate:"max=4096"`
+	Limit int    `json:"limit" validate:"min=1,max=1000"`
+}
+
+// TaskTagLink links a task to a tag.
+type TaskTagLink struct {
+	TaskId uint `json:"task_id"`
+	TagId  uint `json:"tag_id"`
+}
+
+type SyncDeleted struct {
+	Tasks    []uint        `json:"tasks"`
+	Tags     []uint        `json:"tags"`
+	TaskTags []TaskTagLink `json:"task_tags"`
+}
+
+// SyncPullResponse holds the current state of everything that changed
+// since the token of the request. With Reset it holds everything, and the
+// client replaces what it has.
+type SyncPullResponse struct {
+	Token    string          `json:"token"`
+	HasMore  bool            `json:"has_more"`
+	Reset    bool            `json:"reset"`
+	Projects []uint          `json:"projects"`
+	Tasks    []TaskResponse  `json:"tasks"`
+	Tags     []TagResponse   `json:"tags"`
+	TaskTags []TaskTagLink   `json:"task_tags"`
+	Deleted  SyncDeleted     `json:"deleted"`
+}
+
+// SyncChange is a change made by the client. Entities created earlier in
+// the same push are referred to by their Ref instead of their id.
+type SyncChange struct {
+	Entity  string          `json:"entity" validate:"required,oneof=task tag task_tag"`
+	Op      string          `json:"op" validate:"required,oneof=create update delete add remove"`
+	Ref     string          `json:"ref" validate:"max=64"`
+	ID      uint            `json:"id"`
+	Version *uint           `json:"version"`
+	TaskId  uint            `json:"task_id"`
+	TaskRef string          `json:"task_ref" validate:"max=64"`
+	TagId   uint            `json:"tag_id"`
+	TagRef  string          `json:"tag_ref" validate:"max=64"`
+	Data    json.RawMessage `json:"data"`
+}
+
+type SyncPushRequest struct {
+	Email   string       `json:"-" validate:"required"`
+	Changes []SyncChange `json:"changes" validate:"required,min=1,max=500,dive"`
+}
+
+const (
+	SyncApplied   = "applied"
+	SyncConflict  = "conflict"
+	SyncNotFound  = "not_found"
+	SyncInvalid   = "invalid"
+	SyncForbidden = "forbidden"
+	SyncFailed    = "error"
+)
+
+// SyncResult is the outcome of one change of a push. Data holds the entity
+// as stored, or as it is on the server when the change conflicted.
+type SyncResult struct {
+	Index   int    `json:"index"`
+	Ref     string `json:"ref,omitempty"`
+	Status  string `json:"status"`
+	ID      uint   `json:"id,omitempty"`
+	Version uint   `json:"version,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Data    any    `json:"data,omitempty"`
+}
+
+type SyncPushResponse struct {
+	Results []SyncResult `json:"results"`
+}
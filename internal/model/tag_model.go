@@ -1,30 +1,48 @@
 package model
 
 type CreateTagRequest struct {
-	Email string `json:"email" validate:"required,max=150"`
-	Name  string `json:"name" validate:"required,max=50"`
+	Email     string `json:"email" validate:"required,max=150"`
+	ProjectId uint   `json:"project_id"`
+	Name      string `json:"name" validate:"required,max=50"`
 }
 
 type TagResponse struct {
-	ID    uint   `json:"id"`
-	Email string `json:"email,omitempty"`
-	Name  string `json:"name"`
+	ID        uint   `json:"id"`
+	Email     string `json:"email,omitempty"`
+	ProjectId uint   `json:"project_id"`
+	Name      string `json:"name"`
+	Version   uint   `json:"version"`
 }
 
 type SearchTagRequest struct {
-	Email string `json:"-"`
-	Name  string `json:"name"`
-	Page  int    `json:"page" validate:"min=1"`
-	Size  int    `json:"size" validate:"min=1,max=100"`
+	Email     string `json:"-"`
+	ProjectId uint   `json:"project_id"`
+	Name      string `json:"name"`
+	Page      int    `json:"page" validate:"min=1"`
+	Size      int    `json:"size" validate:"min=1,max=100"`
+	Cursor    string `json:"cursor" validate:"max=1024"`
+	Total     string `json:"total" validate:"omitempty,oneof=exact estimate none"`
 }
 
 type GetTagRequest struct {
 	ID    string `json:"-" validate:"required"`
 	Email string `json:"-" validate:"required"`
+	// IfMatch holds the If-Match header of a delete.
+	IfMatch string `json:"-"`
 }
 
+// UpdateTagRequest replaces a tag.
 type UpdateTagRequest struct {
 	ID			string `json:"-"`
+	IfMatch     string `json:"-"`
 	Email       string `json:"-" validate:"max=100"`
-	Name       string `json:"name" validate:"max=150"`
-}
\ No newline at end of file
+	Name       string `json:"name" validate:"required,max=50"`
+}
+
+// PatchTagRequest is a JSON merge patch of a tag. The name cannot be null.
+type PatchTagRequest struct {
+	ID      string           `json:"-"`
+	IfMatch string           `json:"-"`
+	Email   string           `json:"-" validate:"max=100"`
+	Name    Optional[string] `json:"name" validate:"omitnil,min=1,max=50"`
+}
@@ -4,42 +4,189 @@ import (
 	"time"
 )
 
+// TaskStatuses lists the task statuses in board column order.
+var TaskStatuses = []string{"pending", "in_progress", "completed"}
+
+// TaskPriorities lists the task priorities from lowest to highest.
+var TaskPriorities = []string{"low", "medium", "high", "urgent"}
+
 type CreateTaskRequest struct {
 	Email       string `json:"-" validate:"required,max=100"`
+	ProjectId   uint   `json:"project_id"`
 	Title       string `json:"title" validate:"required,max=150"`
 	Description string `json:"description" validate:"required"`
 	Status      string `json:"status" validate:"oneof=pending in_progress completed"`
-	DueDate     time.Time	`json:"due_date" validate:"required"`
+	Priority    string `json:"priority" validate:"omitempty,oneof=low medium high urgent"`
+	DueDate     *time.Time	`json:"due_date"`
+	DueTime     *string    `json:"due_time" validate:"omitempty,excluded_without=DueDate,datetime=15:04"`
+	StartDate   *time.Time `json:"start_date"`
+	Assignees   []string `json:"assignees" validate:"omitempty,dive,email,max=150"`
 }
 
+// UpdateTaskRequest replaces a task. Fields left out are cleared, or reset
+// to their default for status (pending) and priority (medium).
 type UpdateTaskRequest struct {
 	ID			string `json:"-"`
+	IfMatch     string `json:"-"`
 	Email       string `json:"-" validate:"max=100"`
-	Title       string `json:"title" validate:"max=150"`
+	Title       string `json:"title" validate:"required,max=150"`
 	Description string `json:"description"`
-	Status      string `json:"status" validate:"oneof=pending in_progress completed"`
-	DueDate     time.Time	`json:"due_date"`
+	Status      string `json:"status" validate:"omitempty,oneof=pending in_progress completed"`
+	Priority    string `json:"priority" validate:"omitempty,oneof=low medium high urgent"`
+	DueDate     *time.Time	`json:"due_date"`
+	DueTime     *string    `json:"due_time" validate:"omitempty,excluded_without=DueDate,datetime=15:04"`
+	StartDate   *time.Time `json:"start_date"`
+}
+
+// PatchTaskRequest is a JSON merge patch of a task. Absent keys are left
+// alone and null clears a field; title, status and priority cannot be
+// null.
+type PatchTaskRequest struct {
+	ID          string              `json:"-"`
+	IfMatch     string              `json:"-"`
+	Email       string              `json:"-" validate:"max=100"`
+	Title       Optional[string]    `json:"title" validate:"omitnil,min=1,max=150"`
+	Description Optional[string]    `json:"description"`
+	Status      Optional[string]    `json:"status" validate:"omitnil,oneof=pending in_progress completed"`
+	Priority    Optional[string]    `json:"priority" validate:"omitnil,oneof=low medium high urgent"`
+	DueDate     Optional[time.Time] `json:"due_date"`
+	DueTime     Optional[string]    `json:"due_time" validate:"omitnil,datetime=15:04"`
+	StartDate   Optional[time.Time] `json:"start_date"`
 }
 
 type TaskResponse struct {
 	ID 			uint    `json:"id"`
 	Email 		string `json:"email,omitempty"`
+	ProjectId	uint   `json:"project_id"`
+	ParentId	*uint  `json:"parent_id,omitempty"`
 	Title 		string `json:"title"`
 	Description string `json:"description"`
 	Status		string `json:"status"`
-	DueDate		time.Time `json:"due_date"`
+	Priority	string `json:"priority"`
+	Position	string `json:"position"`
+	DueDate		*time.Time `json:"due_date"`
+	DueTime		*string    `json:"due_time"`
+	StartDate	*time.Time `json:"start_date"`
+	Recurrence	*string    `json:"recurrence,omitempty"`
+	Version		uint       `json:"version"`
+	CompletedAt	*time.Time `json:"completed_at,omitempty"`
+	Assignees	[]string  `json:"assignees,omitempty"`
+	Watchers	[]string  `json:"watchers,omitempty"`
+	Subtasks	[]TaskResponse `json:"subtasks,omitempty"`
+	Tags		[]TaskTagSummary `json:"tags,omitempty"`
 }
 
 type SearchTaskRequest struct {
 	Email string `json:"-"`
+	ProjectId uint `json:"project_id"`
 	Title string `json:"title"`
 	Description string `json:"description"`
 	Status		string `json:"status"`
+	Assignee	string `json:"assignee"`
+	Query		string `json:"q" validate:"max=500"`
+	Sort		string `json:"sort" validate:"omitempty,oneof=due_date -due_date priority -priority created_at -created_at updated_at -updated_at title -title position"`
+	DueFrom		string `json:"due_from" validate:"omitempty,datetime=2006-01-02"`
+	DueTo		string `json:"due_to" validate:"omitempty,datetime=2006-01-02"`
+	Overdue		bool   `json:"overdue"`
+	CreatedAfter string `json:"created_after" validate:"omitempty,datetime=2006-01-02"`
+	UpdatedAfter string `json:"updated_after" validate:"omitempty,datetime=2006-01-02"`
+	Timezone	string `json:"tz" validate:"omitempty,timezone"`
+	Include		string `json:"include" validate:"omitempty,oneof=tags"`
 	Page   int    `json:"page" validate:"min=1"`
 	Size   int    `json:"size" validate:"min=1,max=100"`
+	Cursor string `json:"cursor" validate:"max=1024"`
+	Total  string `json:"total" validate:"omitempty,oneof=exact estimate none"`
 }
 
 type GetTaskRequest struct {
 	ID 	  string   	`json:"-" validate:"required"`
 	Email string 	`json:"-" validate:"required"`
-}
\ No newline at end of file
+	// IfMatch holds the If-Match header of a delete.
+	IfMatch string  `json:"-"`
+	Include string  `json:"include" validate:"omitempty,oneof=tags"`
+}
+
+type UpdateTaskAssigneesRequest struct {
+	ID        string   `json:"-" validate:"required"`
+	Email     string   `json:"-" validate:"required"`
+	Assignees []string `json:"assignees" validate:"dive,email,max=150"`
+}
+
+type TaskWatcherRequest struct {
+	ID           string `json:"-" validate:"required"`
+	Email        string `json:"-" validate:"required"`
+	WatcherEmail string `json:"email" validate:"required,email,max=150"`
+}
+
+// GetBoardRequest pages every status column on its own, by the page or
+// the cursor given for the column.
+type GetBoardRequest struct {
+	ProjectId uint              `json:"-" validate:"required"`
+	Email     string            `json:"-" validate:"required"`
+	Statuses  []string          `json:"-" validate:"dive,oneof=pending in_progress completed"`
+	Pages     map[string]int    `json:"-" validate:"dive,min=1"`
+	Cursors   map[string]string `json:"-" validate:"dive,max=1024"`
+	Size      int               `json:"-" validate:"min=1,max=100"`
+	Total     string            `json:"-" validate:"omitempty,oneof=exact estimate none"`
+}
+
+type BoardColumnResponse struct {
+	Status string         `json:"status"`
+	Tasks  []TaskResponse `json:"tasks"`
+	Paging *PageMetadata  `json:"paging"`
+}
+
+type MoveTaskRequest struct {
+	ID       string `json:"-" validate:"required"`
+	Email    string `json:"-" validate:"required"`
+	Status   string `json:"status" validate:"required,oneof=pending in_progress completed"`
+	AfterId  uint   `json:"after_id"`
+	BeforeId uint   `json:"before_id"`
+}
+
+type FullTextSearchRequest struct {
+	Email     string `json:"-" validate:"required"`
+	ProjectId uint   `json:"project_id"`
+	Query     string `json:"q" validate:"required,max=200"`
+	Page      int    `json:"page" validate:"min=1"`
+	Size      int    `json:"size" validate:"min=1,max=100"`
+}
+
+type TaskSearchResult struct {
+	Task       TaskResponse        `json:"task"`
+	Score      float64             `json:"score"`
+	Highlights map[string][]string `json:"highlights,omitempty"`
+}
+
+type TaskCalendarRequest struct {
+	Email     string `json:"-" validate:"required"`
+	ProjectId uint   `json:"project_id"`
+	Month     string `json:"month" validate:"omitempty,datetime=2006-01,excluded_with=Week"`
+	Week      string `json:"week" validate:"omitempty,datetime=2006-01-02"`
+	Query     string `json:"q" validate:"max=500"`
+	Timezone  string `json:"tz" validate:"omitempty,timezone"`
+	Limit     int    `json:"limit" validate:"min=1,max=50"`
+}
+
+type TaskCalendarResponse struct {
+	From     string                `json:"from"`
+	To       string                `json:"to"`
+	Today    string                `json:"today"`
+	Timezone string                `json:"timezone"`
+	Days     []CalendarDayResponse `json:"days"`
+}
+
+type CalendarDayResponse struct {
+	Date  string                `json:"date"`
+	Count int                   `json:"count"`
+	Tasks []TaskSummaryResponse `json:"tasks"`
+}
+
+type TaskSummaryResponse struct {
+	ID        uint    `json:"id"`
+	ProjectId uint    `json:"project_id"`
+	Title     string  `json:"title"`
+	Status    string  `json:"status"`
+	Priority  string  `json:"priority"`
+	DueTime   *string `json:"due_time"`
+}
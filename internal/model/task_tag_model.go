@@ -23,16 +23,20 @@ type TaskTagToResponse struct {
 }
 
 type SearchTaskTagRequest struct {
-	Email string `json:"-" validate:"required"`
-	Page  int    `json:"page" validate:"min=1"`
-	Size  int    `json:"size" validate:"min=1,max=100"`
+	Email  string `json:"-" validate:"required"`
+	Page   int    `json:"page" validate:"min=1"`
+	Size   int    `json:"size" validate:"min=1,max=100"`
+	Cursor string `json:"cursor" validate:"max=1024"`
+	Total  string `json:"total" validate:"omitempty,oneof=exact estimate none"`
 }
 
 type SearchTaskTagRequestWithTagId struct {
-	Email string `json:"-" validate:"required"`
-	TagId uint   `json:"-" validate:"required"`
-	Page  int    `json:"page" validate:"min=1"`
-	Size  int    `json:"size" validate:"min=1,max=100"`
+	Email  string `json:"-" validate:"required"`
+	TagId  uint   `json:"-" validate:"required"`
+	Page   int    `json:"page" validate:"min=1"`
+	Size   int    `json:"size" validate:"min=1,max=100"`
+	Cursor string `json:"cursor" validate:"max=1024"`
+	Total  string `json:"total" validate:"omitempty,oneof=exact estimate none"`
 }
 
 type TaskTagResult struct {
@@ -40,12 +44,30 @@ type TaskTagResult struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	Status      string `json:"status"`
-	DueDate     string `json:"due_date"`
+	DueDate     *string `json:"due_date"`
 	TagID       uint   `json:"tag_id"`
 }
 
+// TaskWithTagsResponse is a task together with the tags attached to it.
+type TaskWithTagsResponse struct {
+	ID          uint             `json:"id"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	Status      string           `json:"status"`
+	DueDate     *string          `json:"due_date"`
+	Tags        []TaskTagSummary `json:"tags" gorm:"-"`
+}
+
 type GetTaskTagForDelete struct {
 	Email  string `json:"-" validate:"required"`
 	TaskId uint   `json:"-" validate:"required"`
 	TagId  uint   `json:"-" validate:"required"`
+}
+
+// TaskTagSummary is a tag as seen from one of the tasks it is attached to.
+type TaskTagSummary struct {
+	TaskId  uint   `json:"-"`
+	ID      uint   `json:"id"`
+	Name    string `json:"name"`
+	Version uint   `json:"-"`
 }
\ No newline at end of file
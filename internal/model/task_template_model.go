@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// SaveTaskTemplateRequest creates a template or replaces one. Title,
+// description and subtask titles may hold {{placeholders}} filled in when
+// the template is instantiated. DueIn is a due date relative to that day,
+// such as today, tomorrow, +3d or +2w.
+type SaveTaskTemplateRequest struct {
+	ID          string            `json:"-"`
+	Email       string            `json:"-" validate:"required"`
+	Name        string            `json:"name" validate:"required,max=100"`
+	Title       string            `json:"title" validate:"required,max=150"`
+	Description string            `json:"description"`
+	Status      string            `json:"status" validate:"omitempty,oneof=pending in_progress completed"`
+	Priority    string            `json:"priority" validate:"omitempty,oneof=low medium high urgent"`
+	DueIn       string            `json:"due_in" validate:"max=20"`
+	Tags        []string          `json:"tags" validate:"max=20,dive,required,max=50"`
+	Subtasks    []TemplateSubtask `json:"subtasks" validate:"max=100,dive"`
+}
+
+// TemplateSubtask becomes a subtask of the task created from a template.
+type TemplateSubtask struct {
+	Title       string `json:"title" validate:"required,max=150"`
+	Description string `json:"description,omitempty"`
+	DueIn       string `json:"due_in,omitempty" validate:"max=20"`
+}
+
+type GetTaskTemplateRequest struct {
+	ID    string `json:"-" validate:"required"`
+	Email string `json:"-" validate:"required"`
+}
+
+// InstantiateTaskTemplateRequest creates the task of a template in a
+// project, the personal one when ProjectId is zero.
+type InstantiateTaskTemplateRequest struct {
+	ID        string            `json:"-" validate:"required"`
+	Email     string            `json:"-" validate:"required"`
+	Timezone  string            `json:"-"`
+	ProjectId uint              `json:"project_id"`
+	Variables map[string]string `json:"variables" validate:"dive,keys,max=50,endkeys,max=500"`
+}
+
+type TaskTemplateResponse struct {
+	ID          uint              `json:"id"`
+	Name        string            `json:"name"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Status      string            `json:"status"`
+	Priority    string            `json:"priority"`
+	DueIn       string            `json:"due_in,omitempty"`
+	Tags        []string          `json:"tags"`
+	Subtasks    []TemplateSubtask `json:"subtasks"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
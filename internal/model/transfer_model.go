@@ -0,0 +1,48 @@
+package model
+
+// TaskRecord is a task as it is exported and imported in bulk. Dates are
+// written as YYYY-MM-DD, due times as HH:MM and tags by name.
+type TaskRecord struct {
+	ID          uint     `json:"id,omitempty"`
+	Uid         string   `json:"uid,omitempty" validate:"max=255"`
+	ProjectId   uint     `json:"project_id,omitempty"`
+	Title       string   `json:"title" validate:"required,max=150"`
+	Description string   `json:"description"`
+	Status      string   `json:"status" validate:"omitempty,oneof=pending in_progress completed"`
+	Priority    string   `json:"priority" validate:"omitempty,oneof=low medium high urgent"`
+	DueDate     string   `json:"due_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	DueTime     string   `json:"due_time,omitempty" validate:"omitempty,excluded_without=DueDate,datetime=15:04"`
+	StartDate   string   `json:"start_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	Recurrence  string   `json:"recurrence,omitempty" validate:"max=500"`
+	Tags        []string `json:"tags" validate:"dive,required,max=50"`
+	CreatedAt   string   `json:"created_at,omitempty"`
+	UpdatedAt   string   `json:"updated_at,omitempty"`
+}
+
+// TaskRecordFields lists the columns of an export in order.
+var TaskRecordFields = []string{"id", "uid", "project_id", "title", "description", "status", "priority", "due_date", "due_time", "start_date", "recurrence", "tags", "created_at", "updated_at"}
+
+type ExportTasksRequest struct {
+	Email     string `json:"-" validate:"required"`
+	ProjectId uint   `json:"project_id"`
+	Query     string `json:"q" validate:"max=500"`
+	Timezone  string `json:"tz" validate:"omitempty,timezone"`
+	Format    string `json:"format" validate:"oneof=csv ndjson"`
+}
+
+// ImportTasksRequest imports CSV or NDJSON into one project. Mapping maps
+// a record field to the CSV column or JSON key holding it. Rows are
+// committed in chunks of ChunkSize unless Atomic is set; Offset skips the
+// rows an earlier, interrupted import already committed.
+type ImportTasksRequest struct {
+	Email      string            `json:"-" validate:"required"`
+	ProjectId  uint              `json:"project_id"`
+	Format     string            `json:"format" validate:"oneof=csv ndjson"`
+	Mapping    map[string]string `json:"mapping" validate:"dive,keys,oneof=id uid title description status priority due_date due_time start_date recurrence tags,endkeys,required,max=100"`
+	DryRun     bool              `json:"dry_run"`
+	Atomic     bool              `json:"atomic"`
+	CreateTags bool              `json:"create_tags"`
+	Offset     int               `json:"offset" validate:"min=0"`
+	ChunkSize  int               `json:"chunk_size" validate:"min=1,max=5000"`
+	Data       []byte            `json:"-" validate:"required"`
+}
@@ -3,6 +3,8 @@ package model
 type UserResponse struct {
 	Name        string `json:"name"`
 	Email       string `json:"email"`
+	Timezone    string `json:"timezone"`
+	Locale      string `json:"locale"`
 	AccessToken string `json:"access_token,omitempty"`
 }
 
@@ -14,6 +16,8 @@ type CreateUserRequest struct {
 	Name     string `json:"name,omitempty" validate:"required,max=100"`
 	Email    string `json:"email,omitempty" validate:"required,max=100"`
 	Password string `json:"password,omitempty" validate:"required,max=100"`
+	Timezone string `json:"timezone,omitempty" validate:"omitempty,timezone,max=64"`
+	Locale   string `json:"locale,omitempty" validate:"omitempty,bcp47_language_tag,max=35"`
 }
 
 type LoginUserRequest struct {
@@ -25,10 +29,26 @@ type LogoutUserRequest struct {
 	Email string `json:"email,omitempty" validate:"required,max=100"`
 }
 
+// UpdateUserRequest replaces the profile of the current user. Time zone
+// and locale fall back to UTC and en when left out; the password is only
+// changed when given.
 type UpdateUserRequest struct {
-	Name     string `json:"name,omitempty" validate:"max=100"`
+	Name     string `json:"name,omitempty" validate:"required,max=100"`
 	Email    string `json:"-" validate:"max=100"`
 	Password string `json:"password,omitempty" validate:"max=100"`
+	Timezone string `json:"timezone,omitempty" validate:"omitempty,timezone,max=64"`
+	Locale   string `json:"locale,omitempty" validate:"omitempty,bcp47_language_tag,max=35"`
+}
+
+// PatchUserRequest is a JSON merge patch of the current user. A null time
+// zone or locale resets it to the default; name and password cannot be
+// null.
+type PatchUserRequest struct {
+	Name     Optional[string] `json:"name" validate:"omitnil,min=1,max=100"`
+	Email    string           `json:"-" validate:"max=100"`
+	Password Optional[string] `json:"password" validate:"omitnil,min=1,max=100"`
+	Timezone Optional[string] `json:"timezone" validate:"omitnil,timezone,max=64"`
+	Locale   Optional[string] `json:"locale" validate:"omitnil,bcp47_language_tag,max=35"`
 }
 
 type GetUserRequest struct {
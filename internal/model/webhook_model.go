@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+// SaveWebhookRequest creates or replaces a webhook. Without ProjectId it
+// receives the events of every project of the user. Active re-enables a
+// webhook that was disabled after failing too often, or disables it.
+type SaveWebhookRequest struct {
+	ID        string   `json:"-"`
+	Email     string   `json:"-" validate:"required"`
+	URL       string   `json:"url" validate:"required,max=2048,http_url"`
+	Events    []string `json:"events" validate:"required,min=1,max=20,dive,oneof=task.created task.updated task.status_changed task.deleted task.tag_added task.tag_removed tag.created tag.updated tag.deleted comment.added comment.deleted"`
+	ProjectId *uint    `json:"project_id" validate:"omitnil,min=1"`
+	Active    *bool    `json:"active"`
+}
+
+type GetWebhookRequest struct {
+	ID    string `json:"-" validate:"required"`
+	Email string `json:"-" validate:"required"`
+}
+
+// WebhookResponse holds the secret only when the webhook was just created.
+type WebhookResponse struct {
+	ID           uint       `json:"id"`
+	ProjectId    *uint      `json:"project_id"`
+	URL          string     `json:"url"`
+	Events       []string   `json:"events"`
+	Active       bool       `json:"active"`
+	FailureCount int        `json:"failure_count"`
+	DisabledAt   *time.Time `json:"disabled_at,omitempty"`
+	Secret       string     `json:"secret,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+type SearchWebhookDeliveryRequest struct {
+	WebhookId string `json:"-" validate:"required"`
+	Email     string `json:"-" validate:"required"`
+	Status    string `json:"status" validate:"omitempty,oneof=pending succeeded failed"`
+	Page      int    `json:"page" validate:"min=1"`
+	Size      int    `json:"size" validate:"min=1,max=100"`
+	Cursor    string `json:"cursor" validate:"max=1024"`
+	Total     string `json:"total" validate:"omitempty,oneof=exact estimate none"`
+}
+
+type RedeliverWebhookRequest struct {
+	ID        string `json:"-" validate:"required"`
+	WebhookId string `json:"-" validate:"required"`
+	Email     string `json:"-" validate:"required"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID            uint       `json:"id"`
+	WebhookId     uint       `json:"webhook_id"`
+	ActivityId    uint       `json:"activity_id"`
+	Event         string     `json:"event"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	ResponseCode  *int       `json:"response_code"`
+	Error         string     `json:"error,omitempty"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// WebhookPayload is the body of a delivery.
+type WebhookPayload struct {
+	DeliveryId uint             `json:"delivery_id"`
+	Event      string           `json:"event"`
+	Activity   ActivityResponse `json:"activity"`
+}
@@ -0,0 +1,477 @@
+// Package quickadd reads a task typed as one line of text, such as
+// "Pay invoice tomorrow 5pm #finance !high every month".
+//
+// Besides the title the line may hold:
+//
+//   - a due date: today, tomorrow, a weekday name (the coming one, never
+//     today), next week/month/year, in 3 days, in 2 weeks, 2024-05-01 or a
+//     month and day such as "may 5" or "5 may", optionally with a year
+//   - a due time: 5pm, 5:30pm, 5 pm, 17:00 or noon; without a date it is
+//     due today, or tomorrow once the time has passed
+//   - tags: #finance
+//   - a project: @home, matched by name by the caller
+//   - a priority: !low, !medium, !high, !urgent or !1 (urgent) to !4 (low)
+//   - a recurrence: daily, weekly, monthly, yearly, every day, every 2
+//     weeks, every other month, every weekday or every monday; a recurring
+//     task without a date is due on its first occurrence
+//
+// Dates and times may be preceded by on, at, by or due. Only the first
+// date, time, project, priority and recurrence are taken; later ones stay in the
+// title, as does anything in double quotes.
+package quickadd
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrEmptyTitle is returned for a line with nothing left for the title.
+var ErrEmptyTitle = errors.New("title is empty")
+
+// Result is what was read from a line. DueDate is midnight of the due day
+// in the location of the parser and Recurrence an RRULE value such as
+// FREQ=WEEKLY;BYDAY=MO.
+type Result struct {
+	Title      string
+	DueDate    *time.Time
+	DueTime    *string
+	Tags       []string
+	Project    string
+	Priority   string
+	Recurrence string
+}
+
+// Parser reads lines relative to the current time of a clock in a
+// location.
+type Parser struct {
+	location *time.Location
+	now      func() time.Time
+}
+
+// NewParser returns a parser for the location. A nil now uses time.Now.
+func NewParser(location *time.Location, now func() time.Time) *Parser {
+	if location == nil {
+		location = time.UTC
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &Parser{location: location, now: now}
+}
+
+var (
+	isoDate    = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	clock12    = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)$`)
+	clock24    = regexp.MustCompile(`^(\d{1,2}):(\d{2})$`)
+	dayOfMonth = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)?,?$`)
+	year       = regexp.MustCompile(`^\d{4}$`)
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+var months = map[string]time.Month{
+	"jan": time.January, "january": time.January, "feb": time.February, "february": time.February,
+	"mar": time.March, "march": time.March, "apr": time.April, "april": time.April, "may": time.May,
+	"jun": time.June, "june": time.June, "jul": time.July, "july": time.July, "aug": time.August,
+	"august": time.August, "sep": time.September, "sept": time.September, "september": time.September,
+	"oct": time.October, "october": time.October, "nov": time.November, "november": time.November,
+	"dec": time.December, "december": time.December,
+}
+
+var frequencies = map[string]string{
+	"day": "DAILY", "days": "DAILY", "week": "WEEKLY", "weeks": "WEEKLY",
+	"month": "MONTHLY", "months": "MONTHLY", "year": "YEARLY", "years": "YEARLY",
+}
+
+var priorities = map[string]string{
+	"low": "low", "medium": "medium", "high": "high", "urgent": "urgent",
+	"1": "urgent", "2": "high", "3": "medium", "4": "low",
+}
+
+// token is a word of the line. Quoted tokens always belong to the title.
+type token struct {
+	text   string
+	quoted bool
+}
+
+// state is a line being read.
+type state struct {
+	today  time.Time
+	now    time.Time
+	tokens []token
+	result *Result
+	due    *time.Time
+	clock  *time.Duration
+	// first is the first occurrence of a recurrence, used as the due date
+	// when the line has none
+	first *time.Time
+}
+
+// Parse reads a line.
+func (p *Parser) Parse(line string) (*Result, error) {
+	now := p.now().In(p.location)
+	s := &state{
+		now:    now,
+		today:  time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, p.location),
+		tokens: tokenize(line),
+		result: &Result{Tags: []string{}},
+	}
+
+	var title []string
+	for i := 0; i < len(s.tokens); {
+		if s.tokens[i].quoted {
+			title = append(title, s.tokens[i].text)
+			i++
+			continue
+		}
+		if n := s.match(i); n > 0 {
+			i += n
+			continue
+		}
+		title = append(title, s.tokens[i].text)
+		i++
+	}
+
+	result := s.result
+	result.Title = strings.Join(title, " ")
+	if result.Title == "" {
+		return nil, ErrEmptyTitle
+	}
+	due := s.due
+	if due == nil && s.first != nil {
+		due = s.first
+	}
+	if due == nil && s.clock != nil {
+		today := s.today
+		due = &today
+		if !s.today.Add(*s.clock).After(now) {
+			tomorrow := s.today.AddDate(0, 0, 1)
+			due = &tomorrow
+		}
+	}
+	if due == nil && result.Recurrence != "" {
+		today := s.today
+		due = &today
+	}
+	result.DueDate = due
+	if s.clock != nil {
+		clock := fmt.Sprintf("%02d:%02d", int(s.clock.Hours()), int(s.clock.Minutes())%60)
+		result.DueTime = &clock
+	}
+	return result, nil
+}
+
+// match tries every kind of token at position i and returns how many
+// tokens it consumed.
+func (s *state) match(i int) int {
+	word := s.word(i)
+	switch {
+	case strings.HasPrefix(word, "#") && len(word) > 1:
+		s.addTag(s.tokens[i].text[1:])
+		return 1
+	case strings.HasPrefix(word, "@") && s.result.Project == "":
+		if name := strings.TrimRight(s.tokens[i].text[1:], ".,;:"); name != "" {
+			s.result.Project = name
+			return 1
+		}
+		return 0
+	case strings.HasPrefix(word, "!") && s.result.Priority == "":
+		if priority, ok := priorities[word[1:]]; ok {
+			s.result.Priority = priority
+			return 1
+		}
+		return 0
+	}
+	if n := s.recurrence(i); n > 0 {
+		return n
+	}
+	offset := 0
+	switch word {
+	case "on", "at", "by", "due":
+		offset = 1
+	}
+	if s.due == nil {
+		if n := s.date(i + offset); n > 0 {
+			return offset + n
+		}
+	}
+	if s.clock == nil {
+		if n := s.time(i + offset); n > 0 {
+			return offset + n
+		}
+	}
+	return 0
+}
+
+// word returns the lower case text of token i, or "" past the end or for
+// a quoted token.
+func (s *state) word(i int) string {
+	if i >= len(s.tokens) || s.tokens[i].quoted {
+		return ""
+	}
+	return strings.ToLower(s.tokens[i].text)
+}
+
+func (s *state) addTag(name string) {
+	name = strings.TrimRight(name, ".,;:")
+	if name == "" {
+		return
+	}
+	for _, tag := range s.result.Tags {
+		if strings.EqualFold(tag, name) {
+			return
+		}
+	}
+	s.result.Tags = append(s.result.Tags, name)
+}
+
+func (s *state) setDue(due time.Time) {
+	s.due = &due
+}
+
+// date reads a due date at position i.
+func (s *state) date(i int) int {
+	word := s.word(i)
+	switch word {
+	case "today":
+		s.setDue(s.today)
+		return 1
+	case "tomorrow":
+		s.setDue(s.today.AddDate(0, 0, 1))
+		return 1
+	case "next":
+		switch next := s.word(i + 1); next {
+		case "week":
+			s.setDue(s.today.AddDate(0, 0, 7))
+			return 2
+		case "month":
+			s.setDue(s.today.AddDate(0, 1, 0))
+			return 2
+		case "year":
+			s.setDue(s.today.AddDate(1, 0, 0))
+			return 2
+		default:
+			if weekday, ok := weekdays[next]; ok {
+				s.setDue(s.nextWeekday(weekday, false))
+				return 2
+			}
+		}
+		return 0
+	case "in":
+		count, unit := s.word(i+1), s.word(i+2)
+		n, err := strconv.Atoi(count)
+		if count == "a" || count == "an" {
+			n, err = 1, nil
+		}
+		if err != nil || n < 1 || n > 999 {
+			return 0
+		}
+		switch unit {
+		case "day", "days":
+			s.setDue(s.today.AddDate(0, 0, n))
+		case "week", "weeks":
+			s.setDue(s.today.AddDate(0, 0, 7*n))
+		case "month", "months":
+			s.setDue(s.today.AddDate(0, n, 0))
+		case "year", "years":
+			s.setDue(s.today.AddDate(n, 0, 0))
+		default:
+			return 0
+		}
+		return 3
+	}
+	if weekday, ok := weekdays[word]; ok {
+		s.setDue(s.nextWeekday(weekday, false))
+		return 1
+	}
+	if isoDate.MatchString(word) {
+		due, err := time.ParseInLocation(time.DateOnly, word, s.today.Location())
+		if err != nil {
+			return 0
+		}
+		s.setDue(due)
+		return 1
+	}
+	return s.monthDay(i)
+}
+
+// monthDay reads "may 5", "may 5th" or "5 may", optionally followed by a
+// year. Without a year a day that has passed means next year.
+func (s *state) monthDay(i int) int {
+	var month time.Month
+	var day string
+	if m, ok := months[s.word(i)]; ok {
+		month, day = m, s.word(i+1)
+	} else if m, ok := months[strings.TrimSuffix(s.word(i+1), ",")]; ok {
+		month, day = m, s.word(i)
+	} else {
+		return 0
+	}
+	match := dayOfMonth.FindStringSubmatch(day)
+	if match == nil {
+		return 0
+	}
+	d, _ := strconv.Atoi(match[1])
+	n := 2
+	y := s.today.Year()
+	if year.MatchString(s.word(i + 2)) {
+		y, _ = strconv.Atoi(s.word(i + 2))
+		n = 3
+	}
+	due := time.Date(y, month, d, 0, 0, 0, 0, s.today.Location())
+	if due.Day() != d {
+		// such as feb 30
+		return 0
+	}
+	if n == 2 && due.Before(s.today) {
+		due = due.AddDate(1, 0, 0)
+	}
+	s.setDue(due)
+	return n
+}
+
+// time reads a due time at position i.
+func (s *state) time(i int) int {
+	word := s.word(i)
+	if word == "noon" {
+		s.setClock(12, 0)
+		return 1
+	}
+	n := 1
+	if next := s.word(i + 1); next == "am" || next == "pm" {
+		word += next
+		n = 2
+	}
+	if match := clock12.FindStringSubmatch(word); match != nil {
+		hour, _ := strconv.Atoi(match[1])
+		minute, _ := strconv.Atoi(match[2])
+		if hour < 1 || hour > 12 || minute > 59 {
+			return 0
+		}
+		hour %= 12
+		if match[3] == "pm" {
+			hour += 12
+		}
+		s.setClock(hour, minute)
+		return n
+	}
+	if match := clock24.FindStringSubmatch(word); match != nil && n == 1 {
+		hour, _ := strconv.Atoi(match[1])
+		minute, _ := strconv.Atoi(match[2])
+		if hour > 23 || minute > 59 {
+			return 0
+		}
+		s.setClock(hour, minute)
+		return 1
+	}
+	return 0
+}
+
+func (s *state) setClock(hour int, minute int) {
+	clock := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute
+	s.clock = &clock
+}
+
+// recurrence reads a recurrence at position i.
+func (s *state) recurrence(i int) int {
+	if s.result.Recurrence != "" {
+		return 0
+	}
+	switch s.word(i) {
+	case "daily":
+		s.result.Recurrence = "FREQ=DAILY"
+		return 1
+	case "weekly":
+		s.result.Recurrence = "FREQ=WEEKLY"
+		return 1
+	case "monthly":
+		s.result.Recurrence = "FREQ=MONTHLY"
+		return 1
+	case "yearly", "annually":
+		s.result.Recurrence = "FREQ=YEARLY"
+		return 1
+	case "every":
+	default:
+		return 0
+	}
+
+	next := s.word(i + 1)
+	if frequency, ok := frequencies[next]; ok && !strings.HasSuffix(next, "s") {
+		s.result.Recurrence = "FREQ=" + frequency
+		return 2
+	}
+	if next == "weekday" {
+		s.result.Recurrence = "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"
+		first := s.today
+		for first.Weekday() == time.Saturday || first.Weekday() == time.Sunday {
+			first = first.AddDate(0, 0, 1)
+		}
+		s.first = &first
+		return 2
+	}
+	if weekday, ok := weekdays[next]; ok {
+		s.result.Recurrence = "FREQ=WEEKLY;BYDAY=" + strings.ToUpper(next[:2])
+		first := s.nextWeekday(weekday, true)
+		s.first = &first
+		return 2
+	}
+	interval, err := strconv.Atoi(next)
+	if next == "other" {
+		interval, err = 2, nil
+	}
+	if err != nil || interval < 1 || interval > 999 {
+		return 0
+	}
+	frequency, ok := frequencies[s.word(i+2)]
+	if !ok {
+		return 0
+	}
+	s.result.Recurrence = "FREQ=" + frequency
+	if interval > 1 {
+		s.result.Recurrence += ";INTERVAL=" + strconv.Itoa(interval)
+	}
+	return 3
+}
+
+// nextWeekday returns the coming day with the weekday, today only when
+// includeToday is set.
+func (s *state) nextWeekday(weekday time.Weekday, includeToday bool) time.Time {
+	days := (int(weekday) - int(s.today.Weekday()) + 7) % 7
+	if days == 0 && !includeToday {
+		days = 7
+	}
+	return s.today.AddDate(0, 0, days)
+}
+
+// tokenize splits a line into words, keeping "quoted text" together.
+func tokenize(line string) []token {
+	var tokens []token
+	for {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return tokens
+		}
+		if line[0] == '"' {
+			if end := strings.IndexByte(line[1:], '"'); end >= 0 {
+				if text := line[1 : end+1]; text != "" {
+					tokens = append(tokens, token{text: text, quoted: true})
+				}
+				line = line[end+2:]
+				continue
+			}
+		}
+		end := strings.IndexAny(line, " \t")
+		if end < 0 {
+			end = len(line)
+		}
+		tokens = append(tokens, token{text: line[:end]})
+		line = line[end:]
+	}
+}
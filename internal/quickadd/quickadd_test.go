@@ -0,0 +1,154 @@
+package quickadd
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a Wednesday evening
+	wednesday := time.Date(2026, 10, 14, 18, 30, 0, 0, jakarta)
+	newYearsEve := time.Date(2026, 12, 31, 9, 0, 0, 0, jakarta)
+
+	tests := []struct {
+		name       string
+		line       string
+		now        time.Time
+		title      string
+		due        string
+		clock      string
+		tags       []string
+		project    string
+		priority   string
+		recurrence string
+	}{
+		{name: "everything", line: "Pay invoice tomorrow 5pm #finance !high every month", title: "Pay invoice", due: "2026-10-15", clock: "17:00", tags: []string{"finance"}, priority: "high", recurrence: "FREQ=MONTHLY"},
+		{name: "title only", line: "Pay invoice", title: "Pay invoice"},
+		{name: "today", line: "Call bank today", title: "Call bank", due: "2026-10-14"},
+		{name: "weekday", line: "Call mom friday", title: "Call mom", due: "2026-10-16"},
+		{name: "weekday is never today", line: "Standup on wednesday", title: "Standup", due: "2026-10-21"},
+		{name: "next weekday", line: "Review next monday", title: "Review", due: "2026-10-19"},
+		{name: "next week", line: "Plan sprint next week", title: "Plan sprint", due: "2026-10-21"},
+		{name: "in days", line: "Ship in 3 days", title: "Ship", due: "2026-10-17"},
+		{name: "in a month", line: "Renew in a month", title: "Renew", due: "2026-11-14"},
+		{name: "tomorrow rolls into the next year", line: "Report tomorrow 5pm", now: newYearsEve, title: "Report", due: "2027-01-01", clock: "17:00"},
+		{name: "time later today", line: "Gym 8pm", title: "Gym", due: "2026-10-14", clock: "20:00"},
+		{name: "passed time rolls over to tomorrow", line: "Gym 5pm", title: "Gym", due: "2026-10-15", clock: "17:00"},
+		{name: "spaced time", line: "Gym at 7 am", title: "Gym", due: "2026-10-15", clock: "07:00"},
+		{name: "minutes", line: "Call 5:30pm tomorrow", title: "Call", due: "2026-10-15", clock: "17:30"},
+		{name: "24 hour clock", line: "Deploy by 2026-10-20 23:15", title: "Deploy", due: "2026-10-20", clock: "23:15"},
+		{name: "noon", line: "Lunch noon tomorrow", title: "Lunch", due: "2026-10-15", clock: "12:00"},
+		{name: "month and day", line: "Party dec 24th", title: "Party", due: "2026-12-24"},
+		{name: "passed month and day is next year", line: "Dentist may 5", title: "Dentist", due: "2027-05-05"},
+		{name: "day and month with year", line: "Dentist 5 may 2026", title: "Dentist", due: "2026-05-05"},
+		{name: "invalid day stays in title", line: "Pay feb 30", title: "Pay feb 30"},
+		{name: "invalid iso date stays in title", line: "Launch 2026-13-01", title: "Launch 2026-13-01"},
+		{name: "invalid time stays in title", line: "Meet at 25:00", title: "Meet at 25:00"},
+		{name: "invalid 12 hour time stays in title", line: "Meet 13pm", title: "Meet 13pm"},
+		{name: "only the first date", line: "Move friday to monday", title: "Move to monday", due: "2026-10-16"},
+		{name: "quoted title", line: `"Read tomorrow" today`, title: "Read tomorrow", due: "2026-10-14"},
+		{name: "quoted words stay in title", line: `Watch "next week" tonight`, title: "Watch next week tonight"},
+		{name: "tags", line: "#work fix bug #Work #urgent.", title: "fix bug", tags: []string{"work", "urgent"}},
+		{name: "lone hash stays in title", line: "Press # key", title: "Press # key"},
+		{name: "numbered priority", line: "!1 deploy", title: "deploy", priority: "urgent"},
+		{name: "unknown priority stays in title", line: "deploy !9", title: "deploy !9"},
+		{name: "only the first priority", line: "deploy !low !high", title: "deploy !high", priority: "low"},
+		{name: "project", line: "Buy milk @home #errand", title: "Buy milk", tags: []string{"errand"}, project: "home"},
+		{name: "only the first project", line: "Email @work, @home", title: "Email @home", project: "work"},
+		{name: "lone at stays in title", line: "Meet @ office", title: "Meet @ office"},
+		{name: "recurrence without date", line: "Water plants every other week", title: "Water plants", due: "2026-10-14", recurrence: "FREQ=WEEKLY;INTERVAL=2"},
+		{name: "every weekday", line: "Standup every weekday 9:00", title: "Standup", due: "2026-10-14", clock: "09:00", recurrence: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"},
+		{name: "every weekday on a saturday", line: "Standup every weekday", now: time.Date(2026, 10, 17, 9, 0, 0, 0, jakarta), title: "Standup", due: "2026-10-19", recurrence: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"},
+		{name: "every weekday name", line: "Yoga every monday", title: "Yoga", due: "2026-10-19", recurrence: "FREQ=WEEKLY;BYDAY=MO"},
+		{name: "every today's weekday", line: "Yoga every wednesday", title: "Yoga", due: "2026-10-14", recurrence: "FREQ=WEEKLY;BYDAY=WE"},
+		{name: "recurrence with date", line: "Rent daily next month", title: "Rent", due: "2026-11-14", recurrence: "FREQ=DAILY"},
+		{name: "unknown interval stays in title", line: "Run every 3 fortnights", title: "Run every 3 fortnights"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now := test.now
+			if now.IsZero() {
+				now = wednesday
+			}
+			result, err := NewParser(jakarta, func() time.Time { return now }).Parse(test.line)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", test.line, err)
+			}
+			if result.Title != test.title {
+				t.Errorf("title = %q, want %q", result.Title, test.title)
+			}
+			due := ""
+			if result.DueDate != nil {
+				due = result.DueDate.Format(time.DateOnly)
+				if result.DueDate.Location() != jakarta || result.DueDate.Hour() != 0 {
+					t.Errorf("due date = %v, want midnight in %v", result.DueDate, jakarta)
+				}
+			}
+			if due != test.due {
+				t.Errorf("due date = %q, want %q", due, test.due)
+			}
+			clock := ""
+			if result.DueTime != nil {
+				clock = *result.DueTime
+			}
+			if clock != test.clock {
+				t.Errorf("due time = %q, want %q", clock, test.clock)
+			}
+			if test.tags == nil {
+				test.tags = []string{}
+			}
+			if !slices.Equal(result.Tags, test.tags) {
+				t.Errorf("tags = %q, want %q", result.Tags, test.tags)
+			}
+			if result.Project != test.project {
+				t.Errorf("project = %q, want %q", result.Project, test.project)
+			}
+			if result.Priority != test.priority {
+				t.Errorf("priority = %q, want %q", result.Priority, test.priority)
+			}
+			if result.Recurrence != test.recurrence {
+				t.Errorf("recurrence = %q, want %q", result.Recurrence, test.recurrence)
+			}
+		})
+	}
+}
+
+func TestParseEmptyTitle(t *testing.T) {
+	for _, line := range []string{"", "   ", "tomorrow 5pm #work !high", `""`} {
+		if _, err := NewParser(nil, nil).Parse(line); !errors.Is(err, ErrEmptyTitle) {
+			t.Errorf("Parse(%q) error = %v, want %v", line, err, ErrEmptyTitle)
+		}
+	}
+}
+
+func TestParseUsesLocation(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// still the 14th in UTC, already the 15th in Jakarta
+	now := time.Date(2026, 10, 14, 20, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	for _, test := range []struct {
+		location *time.Location
+		due      string
+	}{
+		{location: time.UTC, due: "2026-10-14"},
+		{location: jakarta, due: "2026-10-15"},
+	} {
+		result, err := NewParser(test.location, clock).Parse("Call today")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := result.DueDate.Format(time.DateOnly); got != test.due {
+			t.Errorf("today in %v = %s, want %s", test.location, got, test.due)
+		}
+	}
+}
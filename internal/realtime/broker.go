@@ -0,0 +1,222 @@
+// Package realtime fans change events out to the streams of connected
+// users.
+//
+// Events are published to a Redis channel so every server instance sees
+// them, and each instance hands them to the streams it serves. The last
+// events of every user are also kept in Redis, so a stream that was cut
+// off can resume from the id of the last event it received.
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	channel = "events"
+	// bufferSize is the number of events kept per user for resuming.
+	bufferSize = 200
+	// bufferTTL is how long the events of a user are kept after the last
+	// one.
+	bufferTTL = 24 * time.Hour
+	// queueSize is the number of events a stream may fall behind before
+	// it is closed.
+	queueSize = 64
+)
+
+// Event is a change, named by Type, with an id that grows with every
+// change. Data is sent to the client as it is.
+type Event struct {
+	ID   uint            `json:"id"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// message is what is published on the Redis channel.
+type message struct {
+	Emails []string `json:"emails"`
+	Events []Event  `json:"events"`
+}
+
+// Broker publishes events and delivers them to the local subscriptions of
+// their users.
+type Broker struct {
+	client *redis.Client
+	log    *logrus.Logger
+
+	mu            sync.Mutex
+	subscriptions map[string]map[*Subscription]struct{}
+}
+
+func NewBroker(client *redis.Client, log *logrus.Logger) *Broker {
+	return &Broker{
+		client:        client,
+		log:           log,
+		subscriptions: map[string]map[*Subscription]struct{}{},
+	}
+}
+
+func bufferKey(email string) string {
+	return "events:email:" + email
+}
+
+// Publish sends the events to the users with the given emails on every
+// instance and keeps them for resuming.
+func (b *Broker) Publish(ctx context.Context, emails []string, events ...Event) error {
+	if len(emails) == 0 || len(events) == 0 {
+		return nil
+	}
+	members := make([]*redis.Z, len(events))
+	for i, event := range events {
+		eventJSON, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		members[i] = &redis.Z{Score: float64(event.ID), Member: eventJSON}
+	}
+	messageJSON, err := json.Marshal(message{Emails: emails, Events: events})
+	if err != nil {
+		return err
+	}
+
+	pipe := b.client.TxPipeline()
+	for _, email := range emails {
+		pipe.ZAdd(ctx, bufferKey(email), members...)
+		pipe.ZRemRangeByRank(ctx, bufferKey(email), 0, -bufferSize-1)
+		pipe.Expire(ctx, bufferKey(email), bufferTTL)
+	}
+	pipe.Publish(ctx, channel, messageJSON)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// Replay returns the kept events of the user after the given id, oldest
+// first. It reports a gap when older events were dropped from a full
+// buffer, so events after the id may be missing.
+func (b *Broker) Replay(ctx context.Context, email string, after uint) ([]Event, bool, error) {
+	key := bufferKey(email)
+	values, err := b.client.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min: "(" + strconv.FormatUint(uint64(after), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	count, err := b.client.ZCard(ctx, key).Result()
+	if err != nil {
+		return nil, false, err
+	}
+
+	events := make([]Event, 0, len(values))
+	for _, value := range values {
+		var event Event
+		if err := json.Unmarshal([]byte(value), &event); err != nil {
+			return nil, false, err
+		}
+		events = append(events, event)
+	}
+	gap := count >= bufferSize && int64(len(events)) == count
+	return events, gap, nil
+}
+
+// Subscription receives the events of one user on this instance. Events
+// is closed when the subscription fell too far behind or was closed.
+type Subscription struct {
+	Events <-chan Event
+
+	broker *Broker
+	email  string
+	events chan Event
+	once   sync.Once
+}
+
+// Subscribe starts receiving the events of the user.
+func (b *Broker) Subscribe(email string) *Subscription {
+	events := make(chan Event, queueSize)
+	subscription := &Subscription{Events: events, broker: b, email: email, events: events}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subscriptions[email] == nil {
+		b.subscriptions[email] = map[*Subscription]struct{}{}
+	}
+	b.subscriptions[email][subscription] = struct{}{}
+	return subscription
+}
+
+// Close stops the subscription.
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.close()
+}
+
+// close removes the subscription while the broker is locked.
+func (s *Subscription) close() {
+	s.once.Do(func() {
+		delete(s.broker.subscriptions[s.email], s)
+		if len(s.broker.subscriptions[s.email]) == 0 {
+			delete(s.broker.subscriptions, s.email)
+		}
+		close(s.events)
+	})
+}
+
+// Run hands the published events to the local subscriptions until ctx is
+// done. It reconnects to Redis when the connection is lost.
+func (b *Broker) Run(ctx context.Context) {
+	pubsub := b.client.Subscribe(ctx, channel)
+	defer pubsub.Close()
+
+	for {
+		received, err := pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			b.log.WithError(err).Warn("error receive events")
+			time.Sleep(time.Second)
+			continue
+		}
+		published, ok := received.(*redis.Message)
+		if !ok {
+			continue
+		}
+		var m message
+		if err := json.Unmarshal([]byte(published.Payload), &m); err != nil {
+			b.log.WithError(err).Warn("error read published events")
+			continue
+		}
+		b.dispatch(&m)
+	}
+}
+
+// dispatch hands the events to the subscriptions of their users. A
+// subscription that cannot keep up is closed; its client resumes from the
+// buffer when it reconnects.
+func (b *Broker) dispatch(m *message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, email := range m.Emails {
+		for subscription := range b.subscriptions[email] {
+			subscription.send(m.Events)
+		}
+	}
+}
+
+func (s *Subscription) send(events []Event) {
+	for _, event := range events {
+		select {
+		case s.events <- event:
+		default:
+			s.close()
+			return
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ActivityRepository struct {
+	Repository[entity.Activity]
+	Log *logrus.Logger
+}
+
+func NewActivityRepository(log *logrus.Logger) *ActivityRepository {
+	return &ActivityRepository{
+		Log: log,
+	}
+}
+
+// Record stores the activities in one insert and queues their webhook
+// deliveries.
+func (r *ActivityRepository) Record(db *gorm.DB, activities ...*entity.Activity) error {
+	if len(activities) == 0 {
+		return nil
+	}
+	if err := db.Create(activities).Error; err != nil {
+		return err
+	}
+	for _, activity := range activities {
+		if err := enqueueDeliveries(db, activity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// activityOrder lists the newest activities first.
+var activityOrder = keyset[entity.Activity]{Name: "-id", Keys: []sortKey{{Column: "id", Desc: true}}, Values: func(activity *entity.Activity) []any {
+	return []any{activity.ID}
+}}
+
+func (r *ActivityRepository) Search(db *gorm.DB, request *model.SearchActivityRequest) ([]entity.Activity, *model.PageMetadata, error) {
+	query := db.Model(&entity.Activity{}).Where("project_id IN (?)", memberProjects(db, request.Email))
+	if request.ProjectId != 0 {
+		query = query.Where("project_id = ?", request.ProjectId)
+	}
+	if len(request.Types) > 0 {
+		query = query.Where("type IN ?", request.Types)
+	}
+	if request.EntityType != "" {
+		query = query.Where("entity_type = ?", request.EntityType)
+	}
+	if request.EntityId != 0 {
+		query = query.Where("entity_id = ?", request.EntityId)
+	}
+	return paginate(query, activityOrder, pageOptions{
+		Page:   request.Page,
+		Size:   request.Size,
+		Cursor: request.Cursor,
+		Total:  request.Total,
+	})
+}
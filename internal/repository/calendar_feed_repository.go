@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type CalendarFeedRepository struct {
+	Repository[entity.CalendarFeed]
+	Log *logrus.Logger
+}
+
+func NewCalendarFeedRepository(log *logrus.Logger) *CalendarFeedRepository {
+	return &CalendarFeedRepository{
+		Log: log,
+	}
+}
+
+func (r *CalendarFeedRepository) FindByToken(db *gorm.DB, feed *entity.CalendarFeed, token string) error {
+	return db.Where("token = ?", token).Take(feed).Error
+}
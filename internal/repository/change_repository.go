@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// changeSequenceBatch is how many changes one transaction of Sequence
+	// numbers.
+	changeSequenceBatch = 1000
+	// changePruneBatch is how many changes one statement of Prune deletes.
+	changePruneBatch = 10000
+)
+
+type ChangeRepository struct {
+	Repository[entity.Change]
+	Log *logrus.Logger
+}
+
+func NewChangeRepository(log *logrus.Logger) *ChangeRepository {
+	return &ChangeRepository{
+		Log: log,
+	}
+}
+
+// changeSession runs statements on the transaction of db without the
+// conditions its caller may have put on it.
+func changeSession(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true})
+}
+
+func taskChange(projectId uint, taskId uint) entity.Change {
+	return entity.Change{ProjectId: projectId, EntityType: "task", TaskId: &taskId}
+}
+
+func tagChange(projectId uint, tagId uint) entity.Change {
+	return entity.Change{ProjectId: projectId, EntityType: "tag", TagId: &tagId}
+}
+
+// recordChanges logs the changes for delta sync.
+func recordChanges(db *gorm.DB, changes ...entity.Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	return changeSession(db).Create(&changes).Error
+}
+
+// linkChanges returns a change for every link between a task and a tag
+// matching the condition on task_tags and tasks, for links written or
+// removed together with their task or tag.
+func linkChanges(db *gorm.DB, query string, args ...any) ([]entity.Change, error) {
+	var changes []entity.Change
+	err := changeSession(db).Table("task_tags").
+		Select("tasks.project_id, 'task_tag' AS entity_type, task_tags.task_id, task_tags.tag_id").
+		Joins("JOIN tasks ON tasks.id = task_tags.task_id").
+		Where(query, args...).
+		Scan(&changes).Error
+	return changes, err
+}
+
+// recordLinkChanges logs the links of the task to the tags.
+func recordLinkChanges(db *gorm.DB, taskId uint, tagIds []uint) error {
+	if len(tagIds) == 0 {
+		return nil
+	}
+	var projectIds []uint
+	if err := changeSession(db).Model(&entity.Task{}).Where("id = ?", taskId).Pluck("project_id", &projectIds).Error; err != nil || len(projectIds) == 0 {
+		return err
+	}
+	changes := make([]entity.Change, len(tagIds))
+	for i, tagId := range tagIds {
+		changes[i] = entity.Change{ProjectId: projectIds[0], EntityType: "task_tag", TaskId: &taskId, TagId: &tagId}
+	}
+	return recordChanges(db, changes...)
+}
+
+// Sequence numbers the committed changes that have no seq yet, in id
+// order. Ids are taken when a change is written but only become visible
+// when its transaction commits, so a long transaction can commit changes
+// with lower ids than ones already synced. Its changes are locked until
+// then and skipped here, and get a seq after they commit: seq follows
+// commit order, and a sync token on it never moves past a change that is
+// still to come. Only one caller numbers at a time; the others return at
+// once.
+func (r *ChangeRepository) Sequence(db *gorm.DB) error {
+	for {
+		count, err := r.sequenceBatch(db)
+		if err != nil || count < changeSequenceBatch {
+			return err
+		}
+	}
+}
+
+func (r *ChangeRepository) sequenceBatch(db *gorm.DB) (int, error) {
+	var ids []uint
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var sequences []entity.ChangeSequence
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).Find(&sequences).Error; err != nil || len(sequences) == 0 {
+			return err
+		}
+		if err := tx.Model(&entity.Change{}).
+			Where("seq IS NULL").
+			Order("id").Limit(changeSequenceBatch).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Pluck("id", &ids).Error; err != nil || len(ids) == 0 {
+			return err
+		}
+
+		sequence := &sequences[0]
+		seqs := strings.Repeat("WHEN ? THEN ? ", len(ids))
+		args := make([]any, 0, 2*len(ids))
+		for i, id := range ids {
+			args = append(args, id, sequence.Value+uint(i)+1)
+		}
+		if err := tx.Model(&entity.Change{}).Where("id IN ?", ids).
+			Update("seq", gorm.Expr("CASE id "+seqs+"END", args...)).Error; err != nil {
+			return err
+		}
+		return tx.Model(sequence).Update("value", sequence.Value+uint(len(ids))).Error
+	})
+	return len(ids), err
+}
+
+// FindSequence reads the last seq given to a change and the last one
+// pruned.
+func (r *ChangeRepository) FindSequence(db *gorm.DB, sequence *entity.ChangeSequence) error {
+	return db.Take(sequence).Error
+}
+
+// Since returns the entities of the projects changed after the change
+// with the given seq, each once at its last change, in seq order.
+func (r *ChangeRepository) Since(db *gorm.DB, projectIds []uint, after uint, limit int) ([]model.ChangedEntity, error) {
+	var changed []model.ChangedEntity
+	err := db.Model(&entity.Change{}).
+		Select("entity_type, task_id, tag_id, MAX(seq) AS seq").
+		Where("project_id IN ? AND seq > ?", projectIds, after).
+		Group("entity_type, task_id, tag_id").
+		Order("MAX(seq)").
+		Limit(limit).
+		Scan(&changed).Error
+	return changed, err
+}
+
+// Deleted reports whether the task or tag was deleted from a project the
+// email is a member of: it is gone, and the change log still holds a
+// change of it in one of those projects. An entity that exists, belongs to
+// other projects or was never created is not.
+func (r *ChangeRepository) Deleted(db *gorm.DB, entityType string, id uint, email string) (bool, error) {
+	table, column := "tasks", "task_id"
+	if entityType == "tag" {
+		table, column = "tags", "tag_id"
+	}
+	var count int64
+	if err := db.Table(table).Where("id = ?", id).Count(&count).Error; err != nil || count > 0 {
+		return false, err
+	}
+	err := db.Model(&entity.Change{}).
+		Where("entity_type = ? AND "+column+" = ? AND project_id IN (?)", entityType, id, memberProjects(db, email)).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Prune deletes the numbered changes written before the given time and
+// returns how many it deleted. The last seq it deletes is saved as pruned
+// first, so a sync token from before it can be told that changes after it
+// are gone.
+func (r *ChangeRepository) Prune(db *gorm.DB, before time.Time) (int64, error) {
+	var seqs []uint
+	if err := db.Model(&entity.Change{}).
+		Where("seq IS NOT NULL AND created_at < ?", before).
+		Order("seq DESC").Limit(1).
+		Pluck("seq", &seqs).Error; err != nil || len(seqs) == 0 {
+		return 0, err
+	}
+	if err := db.Model(&entity.ChangeSequence{}).Where("pruned < ?", seqs[0]).Update("pruned", seqs[0]).Error; err != nil {
+		return 0, err
+	}
+	var deleted int64
+	for {
+		result := db.Where("seq <= ?", seqs[0]).Limit(changePruneBatch).Delete(&entity.Change{})
+		deleted += result.RowsAffected
+		if result.Error != nil || result.RowsAffected < changePruneBatch {
+			return deleted, result.Error
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type CommentRepository struct {
+	Repository[entity.Comment]
+	Log *logrus.Logger
+}
+
+func NewCommentRepository(log *logrus.Logger) *CommentRepository {
+	return &CommentRepository{
+		Log: log,
+	}
+}
+
+var commentOrder = keyset[entity.Comment]{Name: "id", Keys: []sortKey{{Column: "id"}}, Values: func(comment *entity.Comment) []any {
+	return []any{comment.ID}
+}}
+
+func (r *CommentRepository) SearchByTask(db *gorm.DB, taskId uint, request *model.SearchCommentRequest) ([]entity.Comment, *model.PageMetadata, error) {
+	query := db.Model(&entity.Comment{}).Where("task_id = ?", taskId)
+	return paginate(query, commentOrder, pageOptions{
+		Page:   request.Page,
+		Size:   request.Size,
+		Cursor: request.Cursor,
+		Total:  request.Total,
+	})
+}
+
+func (r *CommentRepository) FindByTaskIds(db *gorm.DB, taskIds []uint) ([]entity.Comment, error) {
+	var comments []entity.Comment
+	err := db.Where("task_id IN ?", taskIds).Order("id").Find(&comments).Error
+	return comments, err
+}
+
+func (r *CommentRepository) FindByTaskAndId(db *gorm.DB, comment *entity.Comment, id string, taskId uint) error {
+	return db.Where("id = ? AND task_id = ?", id, taskId).Take(comment).Error
+}
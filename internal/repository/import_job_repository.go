@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ImportJobRepository struct {
+	Repository[entity.ImportJob]
+	Log *logrus.Logger
+}
+
+func NewImportJobRepository(log *logrus.Logger) *ImportJobRepository {
+	return &ImportJobRepository{
+		Log: log,
+	}
+}
+
+// FindRecent lists the most recent import jobs of a user, newest first.
+func (r *ImportJobRepository) FindRecent(db *gorm.DB, email string, limit int) ([]entity.ImportJob, error) {
+	var jobs []entity.ImportJob
+	err := db.Omit("payload").Where("email = ?", email).Order("id DESC").Limit(limit).Find(&jobs).Error
+	return jobs, err
+}
+
+func (r *ImportJobRepository) FindByEmailAndId(db *gorm.DB, job *entity.ImportJob, id string, email string) error {
+	return db.Omit("payload").Where("id = ? AND email = ?", id, email).Take(job).Error
+}
+
+// UpdateRun saves the state of a job, unless another worker took the job
+// over since it was loaded, in which case it returns ErrStaleVersion.
+func (r *ImportJobRepository) UpdateRun(db *gorm.DB, job *entity.ImportJob) error {
+	result := db.Model(job).Where("runs = ?", job.Runs).
+		Select("status", "processed", "created", "updated", "skipped", "error", "payload", "lease_until", "finished_at").
+		Updates(job)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrStaleVersion
+	}
+	return nil
+}
+
+// Claim takes up to limit unfinished jobs whose lease ran out at now, such
+// as the ones running when the service stopped. Their lease is moved lease
+// ahead so other workers skip them while they run, and their runs are
+// counted so the worker they are taken from can no longer save them.
+func (r *ImportJobRepository) Claim(db *gorm.DB, now time.Time, lease time.Duration, limit int) ([]entity.ImportJob, error) {
+	var ids []uint
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.ImportJob{}).
+			Where("status IN ? AND (lease_until IS NULL OR lease_until <= ?)", []string{"queued", "running"}, now).
+			Order("id").Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		return tx.Model(&entity.ImportJob{}).Where("id IN ?", ids).
+			Updates(map[string]any{"lease_until": now.Add(lease), "runs": gorm.Expr("runs + 1")}).Error
+	})
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	var jobs []entity.ImportJob
+	err = db.Where("id IN ?", ids).Order("id").Find(&jobs).Error
+	return jobs, err
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository struct {
+	Repository[entity.Notification]
+	Log *logrus.Logger
+}
+
+func NewNotificationRepository(log *logrus.Logger) *NotificationRepository {
+	return &NotificationRepository{
+		Log: log,
+	}
+}
+
+// Insert stores the notifications, skipping those whose dedupe key the
+// user already has.
+func (r *NotificationRepository) Insert(db *gorm.DB, notifications []entity.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&notifications).Error
+}
+
+func (r *NotificationRepository) FindByEmailAndId(db *gorm.DB, notification *entity.Notification, id string, email string) error {
+	return db.Where("id = ? AND email = ? AND in_app", id, email).Take(notification).Error
+}
+
+// notificationOrder lists the newest notifications first.
+var notificationOrder = keyset[entity.Notification]{Name: "-id", Keys: []sortKey{{Column: "id", Desc: true}}, Values: func(notification *entity.Notification) []any {
+	return []any{notification.ID}
+}}
+
+func (r *NotificationRepository) Search(db *gorm.DB, request *model.SearchNotificationRequest) ([]entity.Notification, *model.PageMetadata, error) {
+	query := db.Model(&entity.Notification{}).Where("email = ? AND in_app", request.Email)
+	if request.Unread {
+		query = query.Where("read_at IS NULL")
+	}
+	if request.Type != "" {
+		query = query.Where("type = ?", request.Type)
+	}
+	return paginate(query, notificationOrder, pageOptions{
+		Page:   request.Page,
+		Size:   request.Size,
+		Cursor: request.Cursor,
+		Total:  request.Total,
+	})
+}
+
+// CountUnread counts the unread notifications of the user by type.
+func (r *NotificationRepository) CountUnread(db *gorm.DB, email string) (map[string]int64, error) {
+	var rows []struct {
+		Type  string
+		Count int64
+	}
+	err := db.Model(&entity.Notification{}).
+		Select("type, COUNT(*) AS count").
+		Where("email = ? AND in_app AND read_at IS NULL", email).
+		Group("type").
+		Scan(&rows).Error
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Type] = row.Count
+	}
+	return counts, err
+}
+
+// MarkRead marks the notification as read unless it already was.
+func (r *NotificationRepository) MarkRead(db *gorm.DB, notification *entity.Notification, now time.Time) error {
+	if notification.ReadAt != nil {
+		return nil
+	}
+	notification.ReadAt = &now
+	return db.Model(notification).Update("read_at", now).Error
+}
+
+// MarkAllRead marks the unread notifications of the user, optionally of
+// one type, as read and returns how many there were.
+func (r *NotificationRepository) MarkAllRead(db *gorm.DB, email string, notificationType string, now time.Time) (int64, error) {
+	query := db.Model(&entity.Notification{}).Where("email = ? AND in_app AND read_at IS NULL", email)
+	if notificationType != "" {
+		query = query.Where("type = ?", notificationType)
+	}
+	result := query.Update("read_at", now)
+	return result.RowsAffected, result.Error
+}
+
+// Claim takes up to limit notifications with an email or webhook due at
+// now. Their delivery is moved lease ahead so other workers skip them
+// while they are sent.
+func (r *NotificationRepository) Claim(db *gorm.DB, now time.Time, lease time.Duration, limit int) ([]entity.Notification, error) {
+	var notifications []entity.Notification
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("(email_status = ? OR webhook_status = ?) AND deliver_after <= ?", "pending", "pending", now).
+			Order("deliver_after").Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Find(&notifications).Error; err != nil {
+			return err
+		}
+		if len(notifications) == 0 {
+			return nil
+		}
+		ids := make([]uint, len(notifications))
+		for i, notification := range notifications {
+			ids[i] = notification.ID
+		}
+		return tx.Model(&entity.Notification{}).Where("id IN ?", ids).Update("deliver_after", now.Add(lease)).Error
+	})
+	return notifications, err
+}
+
+// SaveDelivery stores the email and webhook statuses and attempts of the
+// notification, and when it is tried next.
+func (r *NotificationRepository) SaveDelivery(db *gorm.DB, notification *entity.Notification) error {
+	return db.Model(notification).Select("email_status", "webhook_status", "email_attempts", "webhook_attempts", "deliver_after").Updates(notification).Error
+}
+
+// FindDigest loads the notifications of the user waiting for the digest.
+func (r *NotificationRepository) FindDigest(db *gorm.DB, email string) ([]entity.Notification, error) {
+	var notifications []entity.Notification
+	err := db.Where("email = ? AND email_status = ?", email, "digest").Order("id").Find(&notifications).Error
+	return notifications, err
+}
+
+// SetEmailStatus moves the emails of the notifications from one status to
+// another.
+func (r *NotificationRepository) SetEmailStatus(db *gorm.DB, email string, from string, to string, deliverAfter *time.Time) error {
+	return db.Model(&entity.Notification{}).Where("email = ? AND email_status = ?", email, from).
+		Updates(map[string]any{"email_status": to, "deliver_after": deliverAfter}).Error
+}
+
+// MarkEmailed records that the emails of the notifications were sent.
+func (r *NotificationRepository) MarkEmailed(db *gorm.DB, ids []uint, status string) error {
+	return db.Model(&entity.Notification{}).Where("id IN ?", ids).Update("email_status", status).Error
+}
+
+type NotificationSettingRepository struct {
+	Repository[entity.NotificationSetting]
+	Log *logrus.Logger
+}
+
+func NewNotificationSettingRepository(log *logrus.Logger) *NotificationSettingRepository {
+	return &NotificationSettingRepository{
+		Log: log,
+	}
+}
+
+// FindByEmails loads the settings of the users that have any.
+func (r *NotificationSettingRepository) FindByEmails(db *gorm.DB, emails []string) ([]entity.NotificationSetting, error) {
+	var settings []entity.NotificationSetting
+	err := db.Where("email IN ?", emails).Find(&settings).Error
+	return settings, err
+}
+
+func (r *NotificationSettingRepository) FindDigests(db *gorm.DB) ([]entity.NotificationSetting, error) {
+	var settings []entity.NotificationSetting
+	err := db.Where("digest").Find(&settings).Error
+	return settings, err
+}
+
+// ClaimDigest records that the digest due at due was sent at now. It
+// reports false when it already was, by this or another worker.
+func (r *NotificationSettingRepository) ClaimDigest(db *gorm.DB, email string, due time.Time, now time.Time) (bool, error) {
+	result := db.Model(&entity.NotificationSetting{}).
+		Where("email = ? AND digest AND (last_digest_at IS NULL OR last_digest_at < ?)", email, due).
+		Update("last_digest_at", now)
+	return result.RowsAffected > 0, result.Error
+}
+
+type NotificationPreferenceRepository struct {
+	Repository[entity.NotificationPreference]
+	Log *logrus.Logger
+}
+
+func NewNotificationPreferenceRepository(log *logrus.Logger) *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{
+		Log: log,
+	}
+}
+
+func (r *NotificationPreferenceRepository) FindByEmails(db *gorm.DB, emails []string) ([]entity.NotificationPreference, error) {
+	var preferences []entity.NotificationPreference
+	err := db.Where("email IN ?", emails).Order("type").Find(&preferences).Error
+	return preferences, err
+}
+
+// Replace swaps the preferences of the user for the given ones.
+func (r *NotificationPreferenceRepository) Replace(db *gorm.DB, email string, preferences []entity.NotificationPreference) error {
+	if err := db.Where("email = ?", email).Delete(&entity.NotificationPreference{}).Error; err != nil {
+		return err
+	}
+	if len(preferences) == 0 {
+		return nil
+	}
+	return db.Create(&preferences).Error
+}
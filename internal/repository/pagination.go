@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"gorm.io/gorm"
+)
+
+// estimateLimit caps how many rows are counted when an estimated total is
+// requested.
+const estimateLimit = 10000
+
+// sortKey is one column of a keyset ordering. Columns must never be NULL,
+// nullable columns are wrapped in COALESCE.
+type sortKey struct {
+	Column string
+	Desc   bool
+}
+
+// keyset is an ordering that is unique per row, so a page can continue
+// right after the last row of the previous one.
+type keyset[T any] struct {
+	Name   string
+	Keys   []sortKey
+	Values func(row *T) []any
+}
+
+type pageOptions struct {
+	Page   int
+	Size   int
+	Cursor string
+	Total  string
+}
+
+// paginate loads one page of the query. Without a cursor it pages by offset
+// and counts the total like before; with a cursor it seeks past the cursor
+// row and only counts when asked to. Both modes return next/prev cursors.
+func paginate[T any](query *gorm.DB, order keyset[T], options pageOptions) ([]T, *model.PageMetadata, error) {
+	query = query.Session(&gorm.Session{})
+	paging := &model.PageMetadata{Size: options.Size}
+
+	total := options.Total
+	if total == "" {
+		total = "exact"
+		if options.Cursor != "" {
+			total = "none"
+		}
+	}
+	switch total {
+	case "exact":
+		var count int64
+		if err := query.Count(&count).Error; err != nil {
+			return nil, nil, err
+		}
+		paging.SetTotal(count, false)
+	case "estimate":
+		var count int64
+		limited := query.Session(&gorm.Session{}).Select("1").Limit(estimateLimit + 1)
+		if err := query.Session(&gorm.Session{NewDB: true}).Table("(?) AS counted", limited).Count(&count).Error; err != nil {
+			return nil, nil, err
+		}
+		paging.SetTotal(min(count, estimateLimit), count > estimateLimit)
+	}
+
+	find := query
+	backward := false
+	if options.Cursor != "" {
+		cursor, err := helper.DecodeCursor(options.Cursor)
+		if err != nil || cursor.Sort != order.Name || len(cursor.Values) != len(order.Keys) {
+			return nil, nil, model.ErrInvalidCursor
+		}
+		backward = cursor.Backward
+		condition, args := order.after(cursor.Values, backward)
+		find = find.Where(condition, args...)
+	} else {
+		paging.Page = options.Page
+		find = find.Offset((options.Page - 1) * options.Size)
+	}
+
+	var rows []T
+	if err := find.Order(order.orderBy(backward)).Limit(options.Size + 1).Find(&rows).Error; err != nil {
+		return nil, nil, err
+	}
+	more := len(rows) > options.Size
+	if more {
+		rows = rows[:options.Size]
+	}
+	hasNext, hasPrev := more, options.Cursor != "" || options.Page > 1
+	if backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+		hasNext, hasPrev = true, more
+	}
+	if len(rows) > 0 {
+		if hasNext {
+			paging.Next = helper.EncodeCursor(&helper.Cursor{Sort: order.Name, Values: order.Values(&rows[len(rows)-1])})
+		}
+		if hasPrev {
+			paging.Prev = helper.EncodeCursor(&helper.Cursor{Sort: order.Name, Values: order.Values(&rows[0]), Backward: true})
+		}
+	}
+	return rows, paging, nil
+}
+
+func (k keyset[T]) orderBy(backward bool) string {
+	columns := make([]string, len(k.Keys))
+	for i, key := range k.Keys {
+		if key.Desc != backward {
+			columns[i] = key.Column + " DESC"
+		} else {
+			columns[i] = key.Column + " ASC"
+		}
+	}
+	return strings.Join(columns, ", ")
+}
+
+// after builds the row comparison (a, b, c) > (va, vb, vc) honouring the
+// direction of every key.
+func (k keyset[T]) after(values []any, backward bool) (string, []any) {
+	var alternatives []string
+	var args []any
+	for i, key := range k.Keys {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, k.Keys[j].Column+" = ?")
+			args = append(args, values[j])
+		}
+		if key.Desc != backward {
+			parts = append(parts, key.Column+" < ?")
+		} else {
+			parts = append(parts, key.Column+" > ?")
+		}
+		args = append(args, values[i])
+		alternatives = append(alternatives, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ProjectInvitationRepository struct {
+	Repository[entity.ProjectInvitation]
+	Log *logrus.Logger
+}
+
+func NewProjectInvitationRepository(log *logrus.Logger) *ProjectInvitationRepository {
+	return &ProjectInvitationRepository{
+		Log: log,
+	}
+}
+
+func (r *ProjectInvitationRepository) CountPending(db *gorm.DB, projectId uint, email string) (int64, error) {
+	var count int64
+	err := db.Model(&entity.ProjectInvitation{}).Where("project_id = ? AND email = ? AND status = ?", projectId, email, "pending").Count(&count).Error
+	return count, err
+}
+
+func (r *ProjectInvitationRepository) FindPendingByEmail(db *gorm.DB, email string) ([]entity.ProjectInvitation, error) {
+	var invitations []entity.ProjectInvitation
+	err := db.Preload("Project").Where("email = ? AND status = ?", email, "pending").Order("id").Find(&invitations).Error
+	return invitations, err
+}
+
+func (r *ProjectInvitationRepository) FindPendingByEmailAndId(db *gorm.DB, invitation *entity.ProjectInvitation, id string, email string) error {
+	return db.Where("id = ? AND email = ? AND status = ?", id, email, "pending").Take(invitation).Error
+}
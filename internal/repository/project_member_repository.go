@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ProjectMemberRepository struct {
+	Repository[entity.ProjectMember]
+	Log *logrus.Logger
+}
+
+func NewProjectMemberRepository(log *logrus.Logger) *ProjectMemberRepository {
+	return &ProjectMemberRepository{
+		Log: log,
+	}
+}
+
+// memberProjects builds a subquery selecting the ids of every project the
+// email belongs to. When roles are given only those memberships count.
+func memberProjects(db *gorm.DB, email string, roles ...string) *gorm.DB {
+	query := db.Session(&gorm.Session{NewDB: true}).Table("project_members").Select("project_id").Where("email = ?", email)
+	if len(roles) > 0 {
+		query = query.Where("role IN ?", roles)
+	}
+	return query
+}
+
+func (r *ProjectMemberRepository) FindByProjectAndEmail(db *gorm.DB, member *entity.ProjectMember, projectId uint, email string) error {
+	return db.Where("project_id = ? AND email = ?", projectId, email).Take(member).Error
+}
+
+func (r *ProjectMemberRepository) HasRole(db *gorm.DB, projectId uint, email string, roles ...string) (bool, error) {
+	var count int64
+	err := db.Model(&entity.ProjectMember{}).Where("project_id = ? AND email = ? AND role IN ?", projectId, email, roles).Count(&count).Error
+	return count > 0, err
+}
+
+// ResolveWritable returns the project a new task or tag should be stored in.
+// A zero projectId falls back to the personal project of the email.
+func (r *ProjectMemberRepository) ResolveWritable(db *gorm.DB, projectId uint, email string) (uint, error) {
+	query := db.Model(&entity.ProjectMember{}).
+		Joins("JOIN projects ON projects.id = project_members.project_id").
+		Where("project_members.email = ? AND project_members.role IN ?", email, model.ProjectWriteRoles)
+	if projectId == 0 {
+		query = query.Where("projects.is_personal = ? AND projects.owner_email = ?", true, email)
+	} else {
+		query = query.Where("project_members.project_id = ?", projectId)
+	}
+
+	var ids []uint
+	if err := query.Limit(1).Pluck("project_members.project_id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+	return ids[0], nil
+}
+
+// FindWritableByName returns the project with the name the email may add
+// tasks to, preferring the personal one.
+func (r *ProjectMemberRepository) FindWritableByName(db *gorm.DB, name string, email string) (uint, error) {
+	var ids []uint
+	err := db.Model(&entity.ProjectMember{}).
+		Joins("JOIN projects ON projects.id = project_members.project_id").
+		Where("project_members.email = ? AND project_members.role IN ? AND projects.name = ?", email, model.ProjectWriteRoles, name).
+		Order("projects.is_personal DESC, projects.id").
+		Limit(1).
+		Pluck("project_members.project_id", &ids).Error
+	if err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+	return ids[0], nil
+}
+
+func (r *ProjectMemberRepository) CountMembers(db *gorm.DB, projectId uint, emails []string) (int64, error) {
+	var count int64
+	err := db.Model(&entity.ProjectMember{}).Where("project_id = ? AND email IN ?", projectId, emails).Count(&count).Error
+	return count, err
+}
+
+func (r *ProjectMemberRepository) ProjectIds(db *gorm.DB, email string) ([]uint, error) {
+	var projectIds []uint
+	err := db.Model(&entity.ProjectMember{}).Where("email = ?", email).Pluck("project_id", &projectIds).Error
+	return projectIds, err
+}
+
+func (r *ProjectMemberRepository) Emails(db *gorm.DB, projectId uint) ([]string, error) {
+	var emails []string
+	err := db.Model(&entity.ProjectMember{}).Where("project_id = ?", projectId).Pluck("email", &emails).Error
+	return emails, err
+}
+
+// EmailsByProjects returns the distinct members of the projects.
+func (r *ProjectMemberRepository) EmailsByProjects(db *gorm.DB, projectIds []uint) ([]string, error) {
+	var emails []string
+	err := db.Model(&entity.ProjectMember{}).Distinct("email").Where("project_id IN ?", projectIds).Pluck("email", &emails).Error
+	return emails, err
+}
+
+func (r *ProjectMemberRepository) EmailsByTask(db *gorm.DB, taskId uint) ([]string, error) {
+	var emails []string
+	err := db.Model(&entity.ProjectMember{}).
+		Where("project_id = (?)", db.Session(&gorm.Session{NewDB: true}).Table("tasks").Select("project_id").Where("id = ?", taskId)).
+		Pluck("email", &emails).Error
+	return emails, err
+}
+
+func (r *ProjectMemberRepository) FindByProject(db *gorm.DB, projectId uint) ([]entity.ProjectMember, error) {
+	var members []entity.ProjectMember
+	err := db.Where("project_id = ?", projectId).Order("id").Find(&members).Error
+	return members, err
+}
+
+var memberProjectOrder = keyset[entity.ProjectMember]{Name: "project_id", Keys: []sortKey{{Column: "project_id"}}, Values: func(member *entity.ProjectMember) []any {
+	return []any{member.ProjectId}
+}}
+
+// SearchByEmail pages through the memberships of the email with their
+// projects. The projects are loaded after the page, so counting it does
+// not load them too.
+func (r *ProjectMemberRepository) SearchByEmail(db *gorm.DB, request *model.SearchProjectRequest) ([]entity.ProjectMember, *model.PageMetadata, error) {
+	query := db.Model(&entity.ProjectMember{}).Where("email = ?", request.Email)
+	members, paging, err := paginate(query, memberProjectOrder, pageOptions{
+		Page:   request.Page,
+		Size:   request.Size,
+		Cursor: request.Cursor,
+		Total:  request.Total,
+	})
+	if err != nil || len(members) == 0 {
+		return members, paging, err
+	}
+
+	projectIds := make([]uint, len(members))
+	for i, member := range members {
+		projectIds[i] = member.ProjectId
+	}
+	var projects []entity.Project
+	if err := db.Where("id IN ?", projectIds).Find(&projects).Error; err != nil {
+		return nil, nil, err
+	}
+	byId := make(map[uint]entity.Project, len(projects))
+	for _, project := range projects {
+		byId[project.ID] = project
+	}
+	for i := range members {
+		members[i].Project = byId[members[i].ProjectId]
+	}
+	return members, paging, nil
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ProjectRepository struct {
+	Repository[entity.Project]
+	Log *logrus.Logger
+}
+
+func NewProjectRepository(log *logrus.Logger) *ProjectRepository {
+	return &ProjectRepository{
+		Log: log,
+	}
+}
+
+func (r *ProjectRepository) FindPersonal(db *gorm.DB, project *entity.Project, email string) error {
+	return db.Where("owner_email = ? AND is_personal = ?", email, true).Take(project).Error
+}
+
+func (r *ProjectRepository) FindByMember(db *gorm.DB, project *entity.Project, id string, email string, roles ...string) error {
+	return db.Where("id = ? AND id IN (?)", id, memberProjects(db, email, roles...)).Take(project).Error
+}
@@ -1,6 +1,14 @@
 package repository
 
-import "gorm.io/gorm"
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrStaleVersion is returned when a versioned row was changed after it
+// was loaded.
+var ErrStaleVersion = errors.New("row was changed after it was loaded")
 
 type Repository[T any] struct {
 	DB *gorm.DB
@@ -36,4 +44,25 @@ func (r *Repository[T]) Update(db *gorm.DB, entity *T) error {
 
 func (r *Repository[T]) Delete(db *gorm.DB, entity *T) error {
 	return db.Delete(entity).Error
-}
\ No newline at end of file
+}
+
+// saveVersion saves an entity while its row still has the given version.
+// Selecting all columns keeps Save from inserting the row instead when
+// nothing matched.
+func saveVersion(db *gorm.DB, entity any, version uint) error {
+	result := db.Select("*").Where("version = ?", version).Save(entity)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return ErrStaleVersion
+	}
+	return result.Error
+}
+
+// deleteVersion deletes an entity while its row still has the given
+// version.
+func deleteVersion(db *gorm.DB, entity any, version uint) error {
+	result := db.Where("version = ?", version).Delete(entity)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return ErrStaleVersion
+	}
+	return result.Error
+}
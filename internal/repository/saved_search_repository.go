@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type SavedSearchRepository struct {
+	Repository[entity.SavedSearch]
+	Log *logrus.Logger
+}
+
+func NewSavedSearchRepository(log *logrus.Logger) *SavedSearchRepository {
+	return &SavedSearchRepository{
+		Log: log,
+	}
+}
+
+// FindByEmail lists the saved searches of a user, pinned ones first.
+func (r *SavedSearchRepository) FindByEmail(db *gorm.DB, email string) ([]entity.SavedSearch, error) {
+	var searches []entity.SavedSearch
+	err := db.Where("email = ?", email).Order("pinned DESC, name, id").Find(&searches).Error
+	return searches, err
+}
+
+func (r *SavedSearchRepository) FindByEmailAndId(db *gorm.DB, search *entity.SavedSearch, id string, email string) error {
+	return db.Where("id = ? AND email = ?", id, email).Take(search).Error
+}
+
+func (r *SavedSearchRepository) CountByName(db *gorm.DB, email string, name string, exceptId uint) (int64, error) {
+	var total int64
+	err := db.Model(&entity.SavedSearch{}).Where("email = ? AND name = ? AND id <> ?", email, name, exceptId).Count(&total).Error
+	return total, err
+}
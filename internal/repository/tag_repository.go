@@ -18,24 +18,60 @@ func NewTagRepository(log *logrus.Logger) *TagRepository {
 	}
 }
 
+// Create stores a new tag at its first version.
+func (r *TagRepository) Create(db *gorm.DB, tag *entity.Tag) error {
+	tag.Version = 1
+	if err := db.Create(tag).Error; err != nil {
+		return err
+	}
+	return recordChanges(db, tagChange(tag.ProjectId, tag.ID))
+}
 
-func (r *TagRepository) Search(db *gorm.DB, request *model.SearchTagRequest) ([]entity.Tag, int64, error) {
-	var tags []entity.Tag
-	if err := db.Scopes(r.FilterTag(request)).Offset((request.Page - 1) * request.Size).Limit(request.Size).Find(&tags).Error; err != nil {
-		return nil, 0, err
+// Update saves the tag as its next version. It fails with ErrStaleVersion
+// when the tag was changed after it was loaded.
+func (r *TagRepository) Update(db *gorm.DB, tag *entity.Tag) error {
+	tag.Version++
+	if err := saveVersion(db, tag, tag.Version-1); err != nil {
+		tag.Version--
+		return err
 	}
+	return recordChanges(db, tagChange(tag.ProjectId, tag.ID))
+}
 
-	var total int64 = 0
-	if err := db.Model(&entity.Tag{}).Scopes(r.FilterTag(request)).Count(&total).Error; err != nil {
-		return nil, 0, err
+// Delete removes the tag unless it was changed after it was loaded. Its
+// links to tasks go with it and are recorded as changed too.
+func (r *TagRepository) Delete(db *gorm.DB, tag *entity.Tag) error {
+	changes, err := linkChanges(db, "task_tags.tag_id = ?", tag.ID)
+	if err != nil {
+		return err
+	}
+	if err := deleteVersion(db, tag, tag.Version); err != nil {
+		return err
 	}
+	return recordChanges(db, append(changes, tagChange(tag.ProjectId, tag.ID))...)
+}
+
+
+var tagOrder = keyset[entity.Tag]{Name: "id", Keys: []sortKey{{Column: "id"}}, Values: func(tag *entity.Tag) []any {
+	return []any{tag.ID}
+}}
 
-	return tags, total, nil
+func (r *TagRepository) Search(db *gorm.DB, request *model.SearchTagRequest) ([]entity.Tag, *model.PageMetadata, error) {
+	query := db.Model(&entity.Tag{}).Scopes(r.FilterTag(request))
+	return paginate(query, tagOrder, pageOptions{
+		Page:   request.Page,
+		Size:   request.Size,
+		Cursor: request.Cursor,
+		Total:  request.Total,
+	})
 }
 
 func (r *TagRepository) FilterTag(request *model.SearchTagRequest) func(tx *gorm.DB) *gorm.DB {
     return func(tx *gorm.DB) *gorm.DB {
-        tx = tx.Where("email = ?", request.Email)
+        tx = tx.Where("project_id IN (?)", memberProjects(tx, request.Email))
+        if request.ProjectId != 0 {
+            tx = tx.Where("project_id = ?", request.ProjectId)
+        }
 
         if name := request.Name; name != "" {
             name = "%" + name + "%"
@@ -45,6 +81,33 @@ func (r *TagRepository) FilterTag(request *model.SearchTagRequest) func(tx *gorm
     }
 }
 
-func (r *TagRepository) FindByEmailAndId(db *gorm.DB, tag *entity.Tag, id string, email string) error {
-	return db.Where("id = ? AND email = ?", id, email).Take(tag).Error
-}
\ No newline at end of file
+func (r *TagRepository) FindByName(db *gorm.DB, tag *entity.Tag, projectId uint, name string) error {
+	return db.Where("project_id = ? AND name = ?", projectId, name).Take(tag).Error
+}
+
+func (r *TagRepository) FindByEmailAndId(db *gorm.DB, tag *entity.Tag, id string, email string, roles ...string) error {
+	return db.Where("id = ? AND project_id IN (?)", id, memberProjects(db, email, roles...)).Take(tag).Error
+}
+
+func (r *TagRepository) TaskIds(db *gorm.DB, tagId uint) ([]uint, error) {
+	var taskIds []uint
+	err := db.Table("task_tags").Where("tag_id = ?", tagId).Pluck("task_id", &taskIds).Error
+	return taskIds, err
+}
+// FindInProjects loads the tags of the projects, or only those with the
+// ids when ids is not nil, ordered by id.
+func (r *TagRepository) FindInProjects(db *gorm.DB, projectIds []uint, ids []uint) ([]entity.Tag, error) {
+	query := db.Where("project_id IN ?", projectIds)
+	if ids != nil {
+		query = query.Where("id IN ?", ids)
+	}
+	var tags []entity.Tag
+	err := query.Order("id").Find(&tags).Error
+	return tags, err
+}
+
+func (r *TagRepository) FindByIds(db *gorm.DB, ids []uint) ([]entity.Tag, error) {
+	var tags []entity.Tag
+	err := db.Where("id IN ?", ids).Find(&tags).Error
+	return tags, err
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type TaskAssigneeRepository struct {
+	Repository[entity.TaskAssignee]
+	Log *logrus.Logger
+}
+
+func NewTaskAssigneeRepository(log *logrus.Logger) *TaskAssigneeRepository {
+	return &TaskAssigneeRepository{
+		Log: log,
+	}
+}
+
+func (r *TaskAssigneeRepository) FindEmailsByTask(db *gorm.DB, taskId uint) ([]string, error) {
+	var emails []string
+	err := db.Model(&entity.TaskAssignee{}).Where("task_id = ?", taskId).Order("id").Pluck("email", &emails).Error
+	return emails, err
+}
+
+func (r *TaskAssigneeRepository) IsAssignee(db *gorm.DB, taskId uint, email string) (bool, error) {
+	var count int64
+	err := db.Model(&entity.TaskAssignee{}).Where("task_id = ? AND email = ?", taskId, email).Count(&count).Error
+	return count > 0, err
+}
+
+// Replace swaps the assignees of a task for the given emails.
+func (r *TaskAssigneeRepository) Replace(db *gorm.DB, taskId uint, emails []string) error {
+	if err := db.Where("task_id = ?", taskId).Delete(&entity.TaskAssignee{}).Error; err != nil {
+		return err
+	}
+	if len(emails) == 0 {
+		return nil
+	}
+	assignees := make([]entity.TaskAssignee, len(emails))
+	for i, email := range emails {
+		assignees[i] = entity.TaskAssignee{TaskId: taskId, Email: email}
+	}
+	return db.Create(&assignees).Error
+}
+
+func (r *TaskAssigneeRepository) FindByTasks(db *gorm.DB, taskIds []uint) ([]entity.TaskAssignee, error) {
+	var assignees []entity.TaskAssignee
+	err := db.Where("task_id IN ?", taskIds).Order("id").Find(&assignees).Error
+	return assignees, err
+}
@@ -1,7 +1,13 @@
 package repository
 
 import (
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/filter"
+	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
 	"github.com/abdisetiakawan/go-clean-arch/internal/model"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
@@ -18,23 +24,161 @@ func NewTaskRepository(log *logrus.Logger) *TaskRepository {
 	}
 }
 
-func (r *TaskRepository) Search(db *gorm.DB, request *model.SearchTaskRequest) ([]entity.Task, int64, error) {
-	var tasks []entity.Task
-	if err := db.Scopes(r.FilterTask(request)).Offset((request.Page - 1) * request.Size).Limit(request.Size).Find(&tasks).Error; err != nil {
-		return nil, 0, err
+// Create stores a new task at its first version.
+func (r *TaskRepository) Create(db *gorm.DB, task *entity.Task) error {
+	task.Version = 1
+	markCompleted(task)
+	if err := db.Create(task).Error; err != nil {
+		return err
 	}
+	return recordChanges(db, taskChange(task.ProjectId, task.ID))
+}
 
-	var total int64 = 0
-	if err := db.Model(&entity.Task{}).Scopes(r.FilterTask(request)).Count(&total).Error; err != nil {
-		return nil, 0, err
+// Update saves the task as its next version. It fails with ErrStaleVersion
+// when the task was changed after it was loaded. A task moved to another
+// project is recorded as changed in both.
+func (r *TaskRepository) Update(db *gorm.DB, task *entity.Task) error {
+	var projectIds []uint
+	if err := changeSession(db).Model(&entity.Task{}).Where("id = ?", task.ID).Pluck("project_id", &projectIds).Error; err != nil {
+		return err
+	}
+	markCompleted(task)
+	task.Version++
+	if err := saveVersion(db, task, task.Version-1); err != nil {
+		task.Version--
+		return err
 	}
+	changes := []entity.Change{taskChange(task.ProjectId, task.ID)}
+	if len(projectIds) > 0 && projectIds[0] != task.ProjectId {
+		changes = append(changes, taskChange(projectIds[0], task.ID))
+	}
+	return recordChanges(db, changes...)
+}
 
-	return tasks, total, nil
+// Touch moves a task to a new version when its assignees, watchers or
+// tags change, since they are part of what clients see of it.
+func (r *TaskRepository) Touch(db *gorm.DB, task *entity.Task) error {
+	if err := touchTask(db, task.ID); err != nil {
+		return err
+	}
+	task.Version++
+	return nil
+}
+
+// touchTask bumps the version of the task and logs the change for delta
+// sync.
+func touchTask(db *gorm.DB, taskId uint) error {
+	var projectIds []uint
+	if err := changeSession(db).Model(&entity.Task{}).Where("id = ?", taskId).Pluck("project_id", &projectIds).Error; err != nil || len(projectIds) == 0 {
+		return err
+	}
+	if err := changeSession(db).Model(&entity.Task{}).Where("id = ?", taskId).UpdateColumn("version", gorm.Expr("version + 1")).Error; err != nil {
+		return err
+	}
+	return recordChanges(db, taskChange(projectIds[0], taskId))
+}
+
+// markCompleted keeps the completion time in step with the status: it is
+// set when a task becomes completed and cleared when it is reopened.
+func markCompleted(task *entity.Task) {
+	if task.Status != "completed" {
+		task.CompletedAt = nil
+		return
+	}
+	if task.CompletedAt == nil {
+		now := time.Now()
+		task.CompletedAt = &now
+	}
 }
 
-func (r *TaskRepository) FilterTask(request *model.SearchTaskRequest) func(tx *gorm.DB) *gorm.DB {
+// Delete removes the task unless it was changed after it was loaded. Its
+// subtasks and links to tags go with it and are recorded as changed too.
+func (r *TaskRepository) Delete(db *gorm.DB, task *entity.Task) error {
+	changes, err := linkChanges(db, "tasks.id = ? OR tasks.parent_id = ?", task.ID, task.ID)
+	if err != nil {
+		return err
+	}
+	subtaskIds, err := r.SubtaskIds(changeSession(db), task.ID)
+	if err != nil {
+		return err
+	}
+	if err := deleteVersion(db, task, task.Version); err != nil {
+		return err
+	}
+	changes = append(changes, taskChange(task.ProjectId, task.ID))
+	for _, id := range subtaskIds {
+		changes = append(changes, taskChange(task.ProjectId, id))
+	}
+	return recordChanges(db, changes...)
+}
+
+// taskSorts maps the sort values accepted by SearchTaskRequest to keyset
+// orderings. Tasks without a due date sort last when sorting by due date.
+var taskSorts = map[string]keyset[entity.Task]{
+	"": {Name: "id", Keys: []sortKey{{Column: "id"}}, Values: func(task *entity.Task) []any {
+		return []any{task.ID}
+	}},
+	"due_date": {Name: "due_date", Keys: []sortKey{{Column: "COALESCE(due_date, '9999-12-31')"}, {Column: "id"}}, Values: func(task *entity.Task) []any {
+		return []any{formatDate(task.DueDate, "9999-12-31"), task.ID}
+	}},
+	"-due_date": {Name: "-due_date", Keys: []sortKey{{Column: "COALESCE(due_date, '0001-01-01')", Desc: true}, {Column: "id"}}, Values: func(task *entity.Task) []any {
+		return []any{formatDate(task.DueDate, "0001-01-01"), task.ID}
+	}},
+	"priority": {Name: "priority", Keys: []sortKey{{Column: "priority+0"}, {Column: "id"}}, Values: func(task *entity.Task) []any {
+		return []any{slices.Index(model.TaskPriorities, task.Priority) + 1, task.ID}
+	}},
+	"-priority": {Name: "-priority", Keys: []sortKey{{Column: "priority+0", Desc: true}, {Column: "id"}}, Values: func(task *entity.Task) []any {
+		return []any{slices.Index(model.TaskPriorities, task.Priority) + 1, task.ID}
+	}},
+	"created_at": {Name: "created_at", Keys: []sortKey{{Column: "created_at"}, {Column: "id"}}, Values: func(task *entity.Task) []any {
+		return []any{task.CreatedAt.Format(time.DateTime), task.ID}
+	}},
+	"-created_at": {Name: "-created_at", Keys: []sortKey{{Column: "created_at", Desc: true}, {Column: "id", Desc: true}}, Values: func(task *entity.Task) []any {
+		return []any{task.CreatedAt.Format(time.DateTime), task.ID}
+	}},
+	"updated_at": {Name: "updated_at", Keys: []sortKey{{Column: "updated_at"}, {Column: "id"}}, Values: func(task *entity.Task) []any {
+		return []any{task.UpdatedAt.Format(time.DateTime), task.ID}
+	}},
+	"-updated_at": {Name: "-updated_at", Keys: []sortKey{{Column: "updated_at", Desc: true}, {Column: "id", Desc: true}}, Values: func(task *entity.Task) []any {
+		return []any{task.UpdatedAt.Format(time.DateTime), task.ID}
+	}},
+	"title": {Name: "title", Keys: []sortKey{{Column: "title"}, {Column: "id"}}, Values: func(task *entity.Task) []any {
+		return []any{task.Title, task.ID}
+	}},
+	"-title": {Name: "-title", Keys: []sortKey{{Column: "title", Desc: true}, {Column: "id"}}, Values: func(task *entity.Task) []any {
+		return []any{task.Title, task.ID}
+	}},
+	"position": {Name: "position", Keys: []sortKey{{Column: "position"}, {Column: "id"}}, Values: func(task *entity.Task) []any {
+		return []any{task.Position, task.ID}
+	}},
+}
+
+func formatDate(date *time.Time, fallback string) string {
+	if date == nil {
+		return fallback
+	}
+	return date.Format(time.DateOnly)
+}
+
+func (r *TaskRepository) Search(db *gorm.DB, request *model.SearchTaskRequest, expr filter.Expr) ([]entity.Task, *model.PageMetadata, error) {
+	now := time.Now().In(helper.Location(request.Timezone))
+	query := db.Model(&entity.Task{}).Scopes(r.FilterTask(request, now), r.FilterExpression(expr, request.Email, now))
+	return paginate(query, taskSorts[request.Sort], pageOptions{
+		Page:   request.Page,
+		Size:   request.Size,
+		Cursor: request.Cursor,
+		Total:  request.Total,
+	})
+}
+
+// FilterTask applies the plain query filters. Relative dates such as
+// overdue are resolved against now, which carries the user's time zone.
+func (r *TaskRepository) FilterTask(request *model.SearchTaskRequest, now time.Time) func(tx *gorm.DB) *gorm.DB {
     return func(tx *gorm.DB) *gorm.DB {
-        tx = tx.Where("email = ?", request.Email)
+        tx = tx.Where("project_id IN (?)", memberProjects(tx, request.Email))
+        if request.ProjectId != 0 {
+            tx = tx.Where("project_id = ?", request.ProjectId)
+        }
 
         if title := request.Title; title != "" {
             title = "%" + title + "%"
@@ -44,15 +188,388 @@ func (r *TaskRepository) FilterTask(request *model.SearchTaskRequest) func(tx *g
             description = "%" + description + "%"
             tx = tx.Where("description LIKE ?", description )
         }
+        if assignee := request.Assignee; assignee != "" {
+            tx = tx.Where("id IN (?)", tx.Session(&gorm.Session{NewDB: true}).Table("task_assignees").Select("task_id").Where("email = ?", assignee))
+        }
         if status := request.Status; status != "" {
-            status = "%" + status + "%"
-            tx = tx.Where("status LIKE ?", status )
+            tx = tx.Where("status = ?", status)
+        }
+        if request.DueFrom != "" {
+            tx = tx.Where("due_date >= ?", request.DueFrom)
+        }
+        if request.DueTo != "" {
+            tx = tx.Where("due_date <= ?", request.DueTo)
+        }
+        if request.Overdue {
+            // due times are wall clock times in the user's zone, like due dates
+            today, clock := now.Format(time.DateOnly), now.Format(time.TimeOnly)
+            tx = tx.Where("(due_date < ? OR (due_date = ? AND due_time < ?)) AND status <> ?", today, today, clock, "completed")
+        }
+        if request.CreatedAfter != "" {
+            created, _ := time.ParseInLocation(time.DateOnly, request.CreatedAfter, now.Location())
+            tx = tx.Where("created_at >= ?", created)
+        }
+        if request.UpdatedAfter != "" {
+            updated, _ := time.ParseInLocation(time.DateOnly, request.UpdatedAfter, now.Location())
+            tx = tx.Where("updated_at >= ?", updated)
         }
 
         return tx
     }
 }
 
-func (r *TaskRepository) FindByEmailAndId(db *gorm.DB, task *entity.Task, id string, email string) error {
-	return db.Where("id = ? AND email = ?", id, email).Take(task).Error
-}
\ No newline at end of file
+// FilterExpression translates a parsed filter expression into a scope.
+// The email resolves `assignee:me` and now resolves relative dates.
+func (r *TaskRepository) FilterExpression(expr filter.Expr, email string, now time.Time) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if expr == nil {
+			return tx
+		}
+		query, args := r.filterSQL(tx.Session(&gorm.Session{NewDB: true}), expr, email, now)
+		return tx.Where(query, args...)
+	}
+}
+
+func (r *TaskRepository) filterSQL(db *gorm.DB, expr filter.Expr, email string, now time.Time) (string, []any) {
+	switch e := expr.(type) {
+	case *filter.And:
+		return r.joinSQL(db, e.Exprs, " AND ", email, now)
+	case *filter.Or:
+		return r.joinSQL(db, e.Exprs, " OR ", email, now)
+	case *filter.Not:
+		query, args := r.filterSQL(db, e.Expr, email, now)
+		return "NOT " + query, args
+	case *filter.Text:
+		value := "%" + e.Value + "%"
+		return "(title LIKE ? OR description LIKE ?)", []any{value, value}
+	case *filter.Condition:
+		return r.conditionSQL(db, e, email, now)
+	}
+	return "1 = 1", nil
+}
+
+func (r *TaskRepository) joinSQL(db *gorm.DB, exprs []filter.Expr, separator string, email string, now time.Time) (string, []any) {
+	queries := make([]string, len(exprs))
+	var args []any
+	for i, expr := range exprs {
+		query, exprArgs := r.filterSQL(db, expr, email, now)
+		queries[i] = query
+		args = append(args, exprArgs...)
+	}
+	return "(" + strings.Join(queries, separator) + ")", args
+}
+
+func (r *TaskRepository) conditionSQL(db *gorm.DB, c *filter.Condition, email string, now time.Time) (string, []any) {
+	switch c.Field {
+	case "status":
+		return "(status IN ?)", []any{c.Values()}
+	case "priority":
+		if c.Op == ":" {
+			return "(priority IN ?)", []any{c.Values()}
+		}
+		// ENUM values compare by their declared order once turned into numbers.
+		return "(priority+0 " + c.Op + " ?)", []any{slices.Index(model.TaskPriorities, c.Value) + 1}
+	case "tag":
+		tags := db.Table("task_tags").Select("task_tags.task_id").Joins("JOIN tags ON tags.id = task_tags.tag_id").Where("tags.name IN ?", c.Values())
+		return "(id IN (?))", []any{tags}
+	case "assignee":
+		assignees := db.Table("task_assignees").Select("task_id")
+		switch c.Value {
+		case "none":
+			return "(id NOT IN (?))", []any{assignees}
+		case "me":
+			return "(id IN (?))", []any{assignees.Where("email = ?", email)}
+		}
+		return "(id IN (?))", []any{assignees.Where("email = ?", c.Value)}
+	case "project":
+		return "(project_id = ?)", []any{c.Value}
+	case "title", "description":
+		return "(" + c.Field + " LIKE ?)", []any{"%" + c.Value + "%"}
+	case "due":
+		if c.Value == "none" {
+			return "(due_date IS NULL)", nil
+		}
+		return dateSQL("due_date", c, now)
+	case "start":
+		if c.Value == "none" {
+			return "(start_date IS NULL)", nil
+		}
+		return dateSQL("start_date", c, now)
+	case "created":
+		return dateSQL("created_at", c, now)
+	case "updated":
+		return dateSQL("updated_at", c, now)
+	}
+	return "1 = 1", nil
+}
+
+// dateSQL compares a column with whole days, so `due:2026-11-01` matches
+// the entire day and `due<=2026-11-01` includes it. Days start at midnight
+// in now's zone for timestamps, while DATE columns such as due_date hold
+// the user's calendar day already and are compared as plain dates.
+func dateSQL(column string, c *filter.Condition, now time.Time) (string, []any) {
+	start, _ := filter.ParseDate(c.Value, now)
+	var day, next any = start, start.AddDate(0, 0, 1)
+	if column == "due_date" || column == "start_date" {
+		day, next = start.Format(time.DateOnly), start.AddDate(0, 0, 1).Format(time.DateOnly)
+	}
+	switch c.Op {
+	case "<":
+		return "(" + column + " < ?)", []any{day}
+	case "<=":
+		return "(" + column + " < ?)", []any{next}
+	case ">":
+		return "(" + column + " >= ?)", []any{next}
+	case ">=":
+		return "(" + column + " >= ?)", []any{day}
+	}
+	return "(" + column + " >= ? AND " + column + " < ?)", []any{day, next}
+}
+
+// FindDue loads the calendar fields of every task matching the request,
+// ordered by due date, all day tasks before timed ones, then priority. Callers bound the range with DueFrom
+// and DueTo.
+func (r *TaskRepository) FindDue(db *gorm.DB, request *model.SearchTaskRequest, expr filter.Expr) ([]entity.Task, error) {
+	now := time.Now().In(helper.Location(request.Timezone))
+	var tasks []entity.Task
+	err := db.Model(&entity.Task{}).
+		Select("id", "project_id", "title", "status", "priority", "due_date", "due_time").
+		Scopes(r.FilterTask(request, now), r.FilterExpression(expr, request.Email, now)).
+		Order("due_date, due_time IS NOT NULL, due_time, priority+0 DESC, position, id").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// FindScheduled loads every task with a due date matching the request
+// together with its tags, for calendar feeds.
+func (r *TaskRepository) FindScheduled(db *gorm.DB, request *model.SearchTaskRequest, expr filter.Expr) ([]entity.Task, error) {
+	now := time.Now().In(helper.Location(request.Timezone))
+	var tasks []entity.Task
+	err := db.Preload("Tags").
+		Scopes(r.FilterTask(request, now), r.FilterExpression(expr, request.Email, now)).
+		Where("due_date IS NOT NULL").
+		Order("due_date, id").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// FindByUid loads the task of the project an imported item refers to. Tasks
+// exported before they had a uid are matched by their id.
+func (r *TaskRepository) FindByUid(db *gorm.DB, task *entity.Task, projectId uint, uid string, id uint) error {
+	return db.Where("project_id = ? AND (uid = ? OR (uid IS NULL AND id = ?))", projectId, uid, id).Take(task).Error
+}
+
+// FindByEmailAndId loads a task from any project the email is a member of,
+// optionally restricted to memberships holding one of the given roles.
+func (r *TaskRepository) FindByEmailAndId(db *gorm.DB, task *entity.Task, id string, email string, roles ...string) error {
+	return db.Where("id = ? AND project_id IN (?)", id, memberProjects(db, email, roles...)).Take(task).Error
+}
+
+// SubtaskIds returns the tasks created under a task.
+func (r *TaskRepository) SubtaskIds(db *gorm.DB, parentId uint) ([]uint, error) {
+	var ids []uint
+	err := db.Model(&entity.Task{}).Where("parent_id = ?", parentId).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// MaxPosition returns the last position in the status column of the
+// board, or "" for an empty column.
+func (r *TaskRepository) MaxPosition(db *gorm.DB, projectId uint, status string) (string, error) {
+	var positions []string
+	err := db.Model(&entity.Task{}).Where("project_id = ? AND status = ?", projectId, status).Order("position DESC").Limit(1).Pluck("position", &positions).Error
+	if err != nil || len(positions) == 0 {
+		return "", err
+	}
+	return positions[0], nil
+}
+
+// SpreadColumn gives the tasks of the status column, except the one being
+// moved, evenly spread positions in their current order, and returns their
+// ids. It is used once positions in the column get too long.
+func (r *TaskRepository) SpreadColumn(db *gorm.DB, projectId uint, status string, movedId uint) ([]uint, error) {
+	var tasks []entity.Task
+	if err := db.Select("id").Where("project_id = ? AND status = ? AND id <> ?", projectId, status, movedId).Order("position, id").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	ids := make([]uint, len(tasks))
+	changes := make([]entity.Change, len(tasks))
+	for i, position := range helper.SpreadRanks(len(tasks)) {
+		ids[i] = tasks[i].ID
+		if err := db.Model(&entity.Task{}).Where("id = ?", tasks[i].ID).
+			Updates(map[string]any{"position": position, "version": gorm.Expr("version + 1")}).Error; err != nil {
+			return nil, err
+		}
+		changes[i] = taskChange(projectId, tasks[i].ID)
+	}
+	return ids, recordChanges(db, changes...)
+}
+
+// boardOrder lists the tasks of a board column in their manual order.
+var boardOrder = keyset[entity.Task]{Name: "position", Keys: []sortKey{{Column: "position"}, {Column: "id"}}, Values: func(task *entity.Task) []any {
+	return []any{task.Position, task.ID}
+}}
+
+// SearchColumn loads one page of the status column of the board.
+func (r *TaskRepository) SearchColumn(db *gorm.DB, request *model.GetBoardRequest, status string, page int) ([]entity.Task, *model.PageMetadata, error) {
+	query := db.Model(&entity.Task{}).Where("project_id = ? AND status = ?", request.ProjectId, status)
+	return paginate(query, boardOrder, pageOptions{
+		Page:   page,
+		Size:   request.Size,
+		Cursor: request.Cursors[status],
+		Total:  request.Total,
+	})
+}
+
+func (r *TaskRepository) FindInColumn(db *gorm.DB, task *entity.Task, id uint, projectId uint, status string) error {
+	return db.Where("id = ? AND project_id = ? AND status = ?", id, projectId, status).Take(task).Error
+}
+
+func (r *TaskRepository) FindByIds(db *gorm.DB, ids []uint) ([]entity.Task, error) {
+	var tasks []entity.Task
+	err := db.Where("id IN ?", ids).Find(&tasks).Error
+	return tasks, err
+}
+
+// FindInProjects loads the tasks of the projects, or only those with the
+// ids when ids is not nil, ordered by id.
+func (r *TaskRepository) FindInProjects(db *gorm.DB, projectIds []uint, ids []uint) ([]entity.Task, error) {
+	query := db.Where("project_id IN ?", projectIds)
+	if ids != nil {
+		query = query.Where("id IN ?", ids)
+	}
+	var tasks []entity.Task
+	err := query.Order("id").Find(&tasks).Error
+	return tasks, err
+}
+
+// FindBatch returns up to limit tasks with an id above afterId, ordered by id.
+func (r *TaskRepository) FindBatch(db *gorm.DB, afterId uint, limit int) ([]entity.Task, error) {
+	var tasks []entity.Task
+	err := db.Where("id > ?", afterId).Order("id").Limit(limit).Find(&tasks).Error
+	return tasks, err
+}
+// FindExportBatch returns up to limit tasks matching the request with an id
+// above afterId, ordered by id and with their tags loaded.
+func (r *TaskRepository) FindExportBatch(db *gorm.DB, request *model.SearchTaskRequest, expr filter.Expr, afterId uint, limit int) ([]entity.Task, error) {
+	now := time.Now().In(helper.Location(request.Timezone))
+	var tasks []entity.Task
+	err := db.Preload("Tags").
+		Scopes(r.FilterTask(request, now), r.FilterExpression(expr, request.Email, now)).
+		Where("id > ?", afterId).
+		Order("id").
+		Limit(limit).
+		Find(&tasks).Error
+	return tasks, err
+}
+
+func (r *TaskRepository) FindInProject(db *gorm.DB, task *entity.Task, id uint, projectId uint) error {
+	return db.Where("id = ? AND project_id = ?", id, projectId).Take(task).Error
+}
+
+// FindAccessible loads the tasks with the ids that belong to a project the
+// email is a member of, with their tags.
+func (r *TaskRepository) FindAccessible(db *gorm.DB, ids []uint, email string) ([]entity.Task, error) {
+	var tasks []entity.Task
+	err := db.Preload("Tags").Where("id IN ? AND project_id IN (?)", ids, memberProjects(db, email)).Order("id").Find(&tasks).Error
+	return tasks, err
+}
+
+// FindMatching loads up to limit tasks matching the request, with their
+// tags, ordered by id.
+func (r *TaskRepository) FindMatching(db *gorm.DB, request *model.SearchTaskRequest, expr filter.Expr, limit int) ([]entity.Task, error) {
+	now := time.Now().In(helper.Location(request.Timezone))
+	var tasks []entity.Task
+	err := db.Preload("Tags").
+		Scopes(r.FilterTask(request, now), r.FilterExpression(expr, request.Email, now)).
+		Order("id").
+		Limit(limit).
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// statsScope limits statistics to the projects the email is a member of,
+// or to one of them.
+func statsScope(email string, projectId uint) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		tx = tx.Where("tasks.project_id IN (?)", memberProjects(tx, email))
+		if projectId != 0 {
+			tx = tx.Where("tasks.project_id = ?", projectId)
+		}
+		return tx
+	}
+}
+
+// CountByStatusAndPriority counts the tasks for every status and priority.
+func (r *TaskRepository) CountByStatusAndPriority(db *gorm.DB, email string, projectId uint) ([]model.TaskStatsCount, error) {
+	var counts []model.TaskStatsCount
+	err := db.Model(&entity.Task{}).
+		Select("status, priority, COUNT(*) AS count").
+		Scopes(statsScope(email, projectId)).
+		Group("status, priority").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// CountOverdue counts the open tasks due before now, which carries the
+// user's time zone.
+func (r *TaskRepository) CountOverdue(db *gorm.DB, email string, projectId uint, now time.Time) (int64, error) {
+	var count int64
+	today, clock := now.Format(time.DateOnly), now.Format(time.TimeOnly)
+	err := db.Model(&entity.Task{}).
+		Scopes(statsScope(email, projectId)).
+		Where("(due_date < ? OR (due_date = ? AND due_time < ?)) AND status <> ?", today, today, clock, "completed").
+		Count(&count).Error
+	return count, err
+}
+
+// CountByTag counts the tasks of every tag, largest first.
+func (r *TaskRepository) CountByTag(db *gorm.DB, email string, projectId uint, limit int) ([]model.TagStats, error) {
+	var counts []model.TagStats
+	err := db.Table("task_tags").
+		Select("tags.id, tags.name, COUNT(*) AS count").
+		Joins("JOIN tasks ON tasks.id = task_tags.task_id").
+		Joins("JOIN tags ON tags.id = task_tags.tag_id").
+		Scopes(statsScope(email, projectId)).
+		Group("tags.id, tags.name").
+		Order("count DESC, tags.id").
+		Limit(limit).
+		Scan(&counts).Error
+	return counts, err
+}
+
+// CountCreated counts the tasks created in [from, to) and how many of them
+// are completed.
+func (r *TaskRepository) CountCreated(db *gorm.DB, email string, projectId uint, from time.Time, to time.Time) (int64, int64, error) {
+	var result struct {
+		Created   int64
+		Completed int64
+	}
+	err := db.Model(&entity.Task{}).
+		Select("COUNT(*) AS created, COALESCE(SUM(status = ?), 0) AS completed", "completed").
+		Scopes(statsScope(email, projectId)).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Scan(&result).Error
+	return result.Created, result.Completed, err
+}
+
+// FindCompleted loads the creation and completion times of the tasks
+// completed in [from, to), ordered by completion.
+func (r *TaskRepository) FindCompleted(db *gorm.DB, email string, projectId uint, from time.Time, to time.Time) ([]entity.Task, error) {
+	var tasks []entity.Task
+	err := db.Select("id", "created_at", "completed_at").
+		Scopes(statsScope(email, projectId)).
+		Where("completed_at >= ? AND completed_at < ?", from, to).
+		Order("completed_at").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// FindOpenDueBetween loads the unfinished tasks due on a date in
+// [from, to].
+func (r *TaskRepository) FindOpenDueBetween(db *gorm.DB, from time.Time, to time.Time) ([]entity.Task, error) {
+	var tasks []entity.Task
+	err := db.Where("status <> ? AND due_date BETWEEN ? AND ?", "completed", from.Format(time.DateOnly), to.Format(time.DateOnly)).
+		Order("id").
+		Find(&tasks).Error
+	return tasks, err
+}
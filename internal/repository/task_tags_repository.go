@@ -20,7 +20,7 @@ func NewtaskTagRepository(log *logrus.Logger) *TaskTagRepository {
 
 func (r *TaskTagRepository) CreateTaskTag(db *gorm.DB, taskTag *entity.TaskTag, email string) error {
     var count int64
-    err := db.Table("tasks").Where("id = ? AND email = ?", taskTag.TaskId, email).Count(&count).Error
+    err := db.Table("tasks").Where("id = ? AND project_id IN (?)", taskTag.TaskId, memberProjects(db, email, model.ProjectWriteRoles...)).Count(&count).Error
     if err != nil {
         r.Log.WithError(err).Error("failed to validate task id and email")
         return err
@@ -29,7 +29,8 @@ func (r *TaskTagRepository) CreateTaskTag(db *gorm.DB, taskTag *entity.TaskTag,
         return gorm.ErrRecordNotFound
     }
 
-    err = db.Table("tags").Where("id = ? AND email = ?", taskTag.TagId, email).Count(&count).Error
+    // tag harus berada di project yang sama dengan task
+    err = db.Table("tags").Where("id = ? AND project_id = (?)", taskTag.TagId, db.Session(&gorm.Session{NewDB: true}).Table("tasks").Select("project_id").Where("id = ?", taskTag.TaskId)).Count(&count).Error
     if err != nil {
         r.Log.WithError(err).Error("failed to validate tag id and email")
         return err
@@ -41,53 +42,66 @@ func (r *TaskTagRepository) CreateTaskTag(db *gorm.DB, taskTag *entity.TaskTag,
         r.Log.WithError(err).Error("failed to create task tag")
         return err
     }
+    if err := touchTask(db, taskTag.TaskId); err != nil {
+        return err
+    }
     
-    return nil
+    return recordLinkChanges(db, taskTag.TaskId, []uint{taskTag.TagId})
 }
 
-func (r *TaskTagRepository) SearchTaskTag(db *gorm.DB, request *model.SearchTaskTagRequest) ([]model.TaskTagResult, int64, error) {
-    var count int64
-    var taskTags []model.TaskTagResult
+var taskTagOrder = keyset[model.TaskTagResult]{Name: "id", Keys: []sortKey{{Column: "tasks.id"}, {Column: "task_tags.tag_id"}}, Values: func(result *model.TaskTagResult) []any {
+    return []any{result.ID, result.TagID}
+}}
+
+var taskWithTagsOrder = keyset[model.TaskWithTagsResponse]{Name: "id", Keys: []sortKey{{Column: "tasks.id"}}, Values: func(result *model.TaskWithTagsResponse) []any {
+    return []any{result.ID}
+}}
+
+// SearchTaskTag pages through the accessible tasks that have at least one
+// tag, one row per task. Their tags are loaded with FindTagsByTaskIds.
+func (r *TaskTagRepository) SearchTaskTag(db *gorm.DB, request *model.SearchTaskTagRequest) ([]model.TaskWithTagsResponse, *model.PageMetadata, error) {
     query := db.Table("tasks").
-        Select("tasks.id, tasks.title, tasks.description, tasks.status, tasks.due_date, task_tags.tag_id").
-        Joins("INNER JOIN task_tags ON tasks.id = task_tags.task_id").
-        Where("tasks.email = ?", request.Email)
-    if err := query.Count(&count).Error; err != nil {
-        r.Log.WithError(err).Error("failed to count tasks")
-        return nil, 0, err
-    }
-    if count == 0 {
-        return nil, 0, gorm.ErrRecordNotFound
-    }
-    if err := query.Offset((request.Page - 1) * request.Size).Limit(request.Size).Scan(&taskTags).Error; err != nil {
-        r.Log.WithError(err).Error("failed to fetch task tags")
-        return nil, 0, err
+        Select("tasks.id, tasks.title, tasks.description, tasks.status, tasks.due_date").
+        Where("tasks.project_id IN (?)", memberProjects(db, request.Email)).
+        Where("EXISTS (SELECT 1 FROM task_tags WHERE task_tags.task_id = tasks.id)")
+    tasks, paging, err := paginate(query, taskWithTagsOrder, pageOptions{
+        Page:   request.Page,
+        Size:   request.Size,
+        Cursor: request.Cursor,
+        Total:  request.Total,
+    })
+    if err != nil {
+        r.Log.WithError(err).Error("failed to fetch tasks with tags")
+        return nil, nil, err
     }
-
-    return taskTags, count, nil
+    return tasks, paging, nil
 }
 
-func (r *TaskTagRepository) SearchTaskTagRequestWithTagId(db *gorm.DB, request *model.SearchTaskTagRequestWithTagId) ([]model.TaskTagResult, int64, error) {
-    var count int64
-    var taskTags []model.TaskTagResult
+func (r *TaskTagRepository) SearchTaskTagRequestWithTagId(db *gorm.DB, request *model.SearchTaskTagRequestWithTagId) ([]model.TaskTagResult, *model.PageMetadata, error) {
     query := db.Table("tasks").
     Select("tasks.id, tasks.title, tasks.description, tasks.status, tasks.due_date, task_tags.tag_id").
     Joins("INNER JOIN task_tags ON tasks.id = task_tags.task_id").
-    Where("tasks.email = ?", request.Email).
+    Where("tasks.project_id IN (?)", memberProjects(db, request.Email)).
     Where("task_tags.tag_id = ?", request.TagId)
-    if err := query.Count(&count).Error; err != nil {
-        r.Log.WithError(err).Error("failed to count tasks")
-        return nil, 0, err
-    }
-    if count == 0 {
-        return nil, 0, gorm.ErrRecordNotFound
-    }
-    if err := query.Offset((request.Page - 1) * request.Size).Limit(request.Size).Scan(&taskTags).Error; err != nil {
+    return r.searchTaskTags(query, pageOptions{
+        Page:   request.Page,
+        Size:   request.Size,
+        Cursor: request.Cursor,
+        Total:  request.Total,
+    })
+}
+
+func (r *TaskTagRepository) searchTaskTags(query *gorm.DB, options pageOptions) ([]model.TaskTagResult, *model.PageMetadata, error) {
+    taskTags, paging, err := paginate(query, taskTagOrder, options)
+    if err != nil {
         r.Log.WithError(err).Error("failed to fetch task tags")
-        return nil, 0, err
+        return nil, nil, err
+    }
+    if paging.TotalItem != nil && *paging.TotalItem == 0 {
+        return nil, nil, gorm.ErrRecordNotFound
     }
 
-    return taskTags, count, nil
+    return taskTags, paging, nil
 }
 
 func (r *TaskTagRepository) CheckTaskTag(db *gorm.DB, taskId int, tagId int) error {
@@ -105,6 +119,77 @@ func (r *TaskTagRepository) CheckTaskTag(db *gorm.DB, taskId int, tagId int) err
 func (r *TaskTagRepository) CheckIsAdded(db *gorm.DB, taskTag *entity.TaskTag, request *model.GetTaskTagForDelete) error {
     return db.Table("task_tags").
         Joins("JOIN tasks ON task_tags.task_id = tasks.id").
-        Where("task_tags.task_id = ? AND task_tags.tag_id = ? AND tasks.email = ?", request.TaskId, request.TagId, request.Email).
+        Where("task_tags.task_id = ? AND task_tags.tag_id = ? AND tasks.project_id IN (?)", request.TaskId, request.TagId, memberProjects(db, request.Email, model.ProjectWriteRoles...)).
         Take(taskTag).Error
-}
\ No newline at end of file
+}
+
+func (r *TaskTagRepository) FindTagsByTaskIds(db *gorm.DB, taskIds []uint) ([]model.TaskTagSummary, error) {
+    var tags []model.TaskTagSummary
+    err := db.Table("task_tags").
+        Select("task_tags.task_id, tags.id, tags.name, tags.version").
+        Joins("JOIN tags ON tags.id = task_tags.tag_id").
+        Where("task_tags.task_id IN ?", taskIds).
+        Order("tags.name").
+        Scan(&tags).Error
+    return tags, err
+}
+
+// Attach links the tags to the task, skipping the ones already linked.
+func (r *TaskTagRepository) Attach(db *gorm.DB, taskId uint, tagIds []uint) error {
+    for _, tagId := range tagIds {
+        taskTag := &entity.TaskTag{TaskId: taskId, TagId: tagId}
+        if err := db.Where(taskTag).FirstOrCreate(taskTag).Error; err != nil {
+            return err
+        }
+    }
+    return recordLinkChanges(db, taskId, tagIds)
+}
+
+// Detach unlinks the tags from the task.
+func (r *TaskTagRepository) Detach(db *gorm.DB, taskId uint, tagIds []uint) error {
+    if err := db.Where("task_id = ? AND tag_id IN ?", taskId, tagIds).Delete(&entity.TaskTag{}).Error; err != nil {
+        return err
+    }
+    return recordLinkChanges(db, taskId, tagIds)
+}
+
+// Delete unlinks the tag of the task tag from its task.
+func (r *TaskTagRepository) Delete(db *gorm.DB, taskTag *entity.TaskTag) error {
+    if err := db.Delete(taskTag).Error; err != nil {
+        return err
+    }
+    if err := touchTask(db, taskTag.TaskId); err != nil {
+        return err
+    }
+    return recordLinkChanges(db, taskTag.TaskId, []uint{taskTag.TagId})
+}
+
+// FindLinks loads the links between tasks and tags of the projects, or
+// only the given ones when links is not nil.
+func (r *TaskTagRepository) FindLinks(db *gorm.DB, projectIds []uint, links []model.TaskTagLink) ([]model.TaskTagLink, error) {
+    query := db.Table("task_tags").
+        Select("task_tags.task_id, task_tags.tag_id").
+        Joins("JOIN tasks ON tasks.id = task_tags.task_id").
+        Where("tasks.project_id IN ?", projectIds)
+    if links != nil {
+        if len(links) == 0 {
+            return nil, nil
+        }
+        pairs := make([][]any, len(links))
+        for i, link := range links {
+            pairs[i] = []any{link.TaskId, link.TagId}
+        }
+        query = query.Where("(task_tags.task_id, task_tags.tag_id) IN ?", pairs)
+    }
+    var found []model.TaskTagLink
+    err := query.Order("task_tags.task_id, task_tags.tag_id").Scan(&found).Error
+    return found, err
+}
+
+// LoadTaskAndTag fills in the task and the tag a task tag links.
+func (r *TaskTagRepository) LoadTaskAndTag(db *gorm.DB, taskTag *entity.TaskTag) error {
+    if err := db.Take(&taskTag.Task, taskTag.TaskId).Error; err != nil {
+        return err
+    }
+    return db.Take(&taskTag.Tag, taskTag.TagId).Error
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type TaskTemplateRepository struct {
+	Repository[entity.TaskTemplate]
+	Log *logrus.Logger
+}
+
+func NewTaskTemplateRepository(log *logrus.Logger) *TaskTemplateRepository {
+	return &TaskTemplateRepository{
+		Log: log,
+	}
+}
+
+// FindByEmail lists the templates of a user by name.
+func (r *TaskTemplateRepository) FindByEmail(db *gorm.DB, email string) ([]entity.TaskTemplate, error) {
+	var templates []entity.TaskTemplate
+	err := db.Where("email = ?", email).Order("name, id").Find(&templates).Error
+	return templates, err
+}
+
+func (r *TaskTemplateRepository) FindByEmailAndId(db *gorm.DB, template *entity.TaskTemplate, id string, email string) error {
+	return db.Where("id = ? AND email = ?", id, email).Take(template).Error
+}
+
+func (r *TaskTemplateRepository) CountByName(db *gorm.DB, email string, name string, exceptId uint) (int64, error) {
+	var total int64
+	err := db.Model(&entity.TaskTemplate{}).Where("email = ? AND name = ? AND id <> ?", email, name, exceptId).Count(&total).Error
+	return total, err
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type TaskWatcherRepository struct {
+	Repository[entity.TaskWatcher]
+	Log *logrus.Logger
+}
+
+func NewTaskWatcherRepository(log *logrus.Logger) *TaskWatcherRepository {
+	return &TaskWatcherRepository{
+		Log: log,
+	}
+}
+
+func (r *TaskWatcherRepository) FindEmailsByTask(db *gorm.DB, taskId uint) ([]string, error) {
+	var emails []string
+	err := db.Model(&entity.TaskWatcher{}).Where("task_id = ?", taskId).Order("id").Pluck("email", &emails).Error
+	return emails, err
+}
+
+func (r *TaskWatcherRepository) FindByTaskAndEmail(db *gorm.DB, watcher *entity.TaskWatcher, taskId uint, email string) error {
+	return db.Where("task_id = ? AND email = ?", taskId, email).Take(watcher).Error
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 type UserRepository struct {
@@ -14,4 +15,9 @@ func NewUserRepository(log *logrus.Logger) *UserRepository {
 	return &UserRepository{
 		Log: log,
 	}
-}
\ No newline at end of file
+}
+func (r *UserRepository) FindByEmails(db *gorm.DB, emails []string) ([]entity.User, error) {
+	var users []entity.User
+	err := db.Where("email IN ?", emails).Find(&users).Error
+	return users, err
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository struct {
+	Repository[entity.Webhook]
+	Log *logrus.Logger
+}
+
+func NewWebhookRepository(log *logrus.Logger) *WebhookRepository {
+	return &WebhookRepository{
+		Log: log,
+	}
+}
+
+func (r *WebhookRepository) FindByEmailAndId(db *gorm.DB, webhook *entity.Webhook, id string, email string) error {
+	return db.Where("id = ? AND email = ?", id, email).Take(webhook).Error
+}
+
+func (r *WebhookRepository) FindAllByEmail(db *gorm.DB, email string) ([]entity.Webhook, error) {
+	var webhooks []entity.Webhook
+	err := db.Where("email = ?", email).Order("id").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// RecordSuccess clears the failures of the webhook.
+func (r *WebhookRepository) RecordSuccess(db *gorm.DB, id uint) error {
+	return db.Model(&entity.Webhook{}).Where("id = ? AND failure_count > 0", id).Update("failure_count", 0).Error
+}
+
+// RecordFailure counts a failed try of the webhook and returns how many
+// tries in a row failed.
+func (r *WebhookRepository) RecordFailure(db *gorm.DB, id uint) (int, error) {
+	if err := db.Model(&entity.Webhook{}).Where("id = ?", id).Update("failure_count", gorm.Expr("failure_count + 1")).Error; err != nil {
+		return 0, err
+	}
+	var failures []int
+	err := db.Model(&entity.Webhook{}).Where("id = ?", id).Pluck("failure_count", &failures).Error
+	if err != nil || len(failures) == 0 {
+		return 0, err
+	}
+	return failures[0], nil
+}
+
+// Disable turns off an active webhook. It reports whether the webhook was
+// still active.
+func (r *WebhookRepository) Disable(db *gorm.DB, id uint, now time.Time) (bool, error) {
+	result := db.Model(&entity.Webhook{}).Where("id = ? AND active", id).
+		Updates(map[string]any{"active": false, "disabled_at": now})
+	return result.RowsAffected > 0, result.Error
+}
+
+// enqueueDeliveries queues a delivery of the activity to every active
+// webhook subscribed to its type whose owner is a member of its project.
+// It runs in the transaction recording the activity, so a delivery exists
+// exactly when the change it describes was committed.
+func enqueueDeliveries(db *gorm.DB, activity *entity.Activity) error {
+	now := time.Now()
+	return db.Exec(`INSERT INTO webhook_deliveries (webhook_id, activity_id, event, status, attempts, next_attempt_at, error, created_at, updated_at)
+		SELECT webhooks.id, ?, ?, 'pending', 0, ?, '', ?, ?
+		FROM webhooks
+		JOIN project_members ON project_members.email = webhooks.email AND project_members.project_id = ?
+		WHERE webhooks.active
+		AND (webhooks.project_id IS NULL OR webhooks.project_id = ?)
+		AND JSON_CONTAINS(webhooks.events, JSON_QUOTE(?))`,
+		activity.ID, activity.Type, now, now, now, activity.ProjectId, activity.ProjectId, activity.Type).Error
+}
+
+type WebhookDeliveryRepository struct {
+	Repository[entity.WebhookDelivery]
+	Log *logrus.Logger
+}
+
+func NewWebhookDeliveryRepository(log *logrus.Logger) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{
+		Log: log,
+	}
+}
+
+// FindByWebhookAndId loads the delivery with its activity.
+func (r *WebhookDeliveryRepository) FindByWebhookAndId(db *gorm.DB, delivery *entity.WebhookDelivery, id string, webhookId uint) error {
+	return db.Preload("Activity").Where("id = ? AND webhook_id = ?", id, webhookId).Take(delivery).Error
+}
+
+// Save stores the delivery without touching the webhook and activity it
+// was loaded with.
+func (r *WebhookDeliveryRepository) Save(db *gorm.DB, delivery *entity.WebhookDelivery) error {
+	return db.Omit(clause.Associations).Save(delivery).Error
+}
+
+// Claim takes up to limit pending deliveries of active webhooks that are
+// due at now, with their webhook and activity. Their next try is moved
+// lease ahead so other workers skip them while they are sent; a delivery
+// whose worker stopped is tried again when the lease ran out.
+func (r *WebhookDeliveryRepository) Claim(db *gorm.DB, now time.Time, lease time.Duration, limit int) ([]entity.WebhookDelivery, error) {
+	var ids []uint
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.WebhookDelivery{}).
+			Joins("JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id").
+			Where("webhook_deliveries.status = ? AND webhook_deliveries.next_attempt_at <= ? AND webhooks.active", "pending", now).
+			Order("webhook_deliveries.next_attempt_at").Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "webhook_deliveries"}, Options: "SKIP LOCKED"}).
+			Pluck("webhook_deliveries.id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		return tx.Model(&entity.WebhookDelivery{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	var deliveries []entity.WebhookDelivery
+	err = db.Preload("Webhook").Preload("Activity").Where("id IN ?", ids).Order("id").Find(&deliveries).Error
+	return deliveries, err
+}
+
+// deliveryOrder lists the newest deliveries first.
+var deliveryOrder = keyset[entity.WebhookDelivery]{Name: "-id", Keys: []sortKey{{Column: "id", Desc: true}}, Values: func(delivery *entity.WebhookDelivery) []any {
+	return []any{delivery.ID}
+}}
+
+func (r *WebhookDeliveryRepository) Search(db *gorm.DB, webhookId uint, request *model.SearchWebhookDeliveryRequest) ([]entity.WebhookDelivery, *model.PageMetadata, error) {
+	query := db.Model(&entity.WebhookDelivery{}).Where("webhook_id = ?", webhookId)
+	if request.Status != "" {
+		query = query.Where("status = ?", request.Status)
+	}
+	return paginate(query, deliveryOrder, pageOptions{
+		Page:   request.Page,
+		Size:   request.Size,
+		Cursor: request.Cursor,
+		Total:  request.Total,
+	})
+}
@@ -0,0 +1,92 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+const snippetContext = 40
+
+// Highlight returns up to maxSnippets fragments of text around the matched
+// terms. Matches are wrapped in <mark> and everything else is HTML escaped.
+func Highlight(text string, terms []Term, maxSnippets int) []string {
+	spans := tokenSpans(text)
+	tokens := make([]string, len(spans))
+	for i, span := range spans {
+		tokens[i] = strings.ToLower(text[span[0]:span[1]])
+	}
+
+	marked := make([]bool, len(spans))
+	found := false
+	for _, term := range terms {
+		if term.Negated {
+			continue
+		}
+		for _, position := range term.occurrences(tokens) {
+			for j := range term.Words {
+				marked[position+j] = true
+				found = true
+			}
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	// group marked tokens into windows and merge the ones that overlap
+	var windows [][2]int
+	for i, span := range spans {
+		if !marked[i] {
+			continue
+		}
+		start, end := span[0]-snippetContext, span[1]+snippetContext
+		if len(windows) > 0 && start <= windows[len(windows)-1][1] {
+			windows[len(windows)-1][1] = end
+			continue
+		}
+		if len(windows) == maxSnippets {
+			break
+		}
+		windows = append(windows, [2]int{start, end})
+	}
+
+	snippets := make([]string, 0, len(windows))
+	for _, window := range windows {
+		start, end := clampToRune(text, window[0]), clampToRune(text, window[1])
+		var builder strings.Builder
+		if start > 0 {
+			builder.WriteString("…")
+		}
+		cursor := start
+		for i, span := range spans {
+			if !marked[i] || span[0] < start || span[1] > end {
+				continue
+			}
+			builder.WriteString(html.EscapeString(text[cursor:span[0]]))
+			builder.WriteString("<mark>")
+			builder.WriteString(html.EscapeString(text[span[0]:span[1]]))
+			builder.WriteString("</mark>")
+			cursor = span[1]
+		}
+		builder.WriteString(html.EscapeString(text[cursor:end]))
+		if end < len(text) {
+			builder.WriteString("…")
+		}
+		snippets = append(snippets, builder.String())
+	}
+	return snippets
+}
+
+func clampToRune(text string, offset int) int {
+	if offset <= 0 {
+		return 0
+	}
+	if offset >= len(text) {
+		return len(text)
+	}
+	for offset > 0 && !utf8.RuneStart(text[offset]) {
+		offset--
+	}
+	return offset
+}
@@ -0,0 +1,34 @@
+package search
+
+import "context"
+
+// Document is the searchable view of a task.
+type Document struct {
+	TaskId      uint
+	ProjectId   uint
+	Title       string
+	Description string
+	Comments    []string
+	Tags        []string
+}
+
+type Query struct {
+	Text       string
+	ProjectIds []uint
+	Page       int
+	Size       int
+}
+
+type Hit struct {
+	TaskId uint
+	Score  float64
+}
+
+// Index is a full-text index over tasks. Backends that read straight from
+// the database report NeedsSync false and ignore Sync and Remove calls.
+type Index interface {
+	NeedsSync() bool
+	Sync(ctx context.Context, documents ...Document) error
+	Remove(ctx context.Context, taskIds ...uint) error
+	Search(ctx context.Context, query Query) ([]Hit, int64, error)
+}
@@ -0,0 +1,105 @@
+package search
+
+import (
+	"context"
+	"os"
+	"slices"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// indexCases are run against every backend, which must agree on which
+// tasks a query finds. Ranking is left to each backend.
+var indexCases = []struct {
+	text string
+	want []uint
+}{
+	{text: "deploy", want: []uint{1, 3}},
+	{text: "release notes", want: []uint{1, 2}},
+	{text: "deploy review", want: []uint{3}},
+	{text: "login draft", want: []uint{3}},
+	{text: "release docs", want: []uint{2}},
+	{text: "login docs", want: nil},
+	{text: "deploy* release", want: []uint{1, 3}},
+	{text: `"release notes" docs`, want: []uint{2}},
+	{text: "release -notes", want: []uint{3}},
+}
+
+func testIndex(t *testing.T, index Index) {
+	for _, test := range indexCases {
+		hits, total, err := index.Search(context.Background(), Query{Text: test.text, ProjectIds: []uint{1}, Page: 1, Size: 100})
+		if err != nil {
+			t.Fatalf("Search(%q) failed: %v", test.text, err)
+		}
+		var got []uint
+		for _, hit := range hits {
+			got = append(got, hit.TaskId)
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, test.want) || total != int64(len(test.want)) {
+			t.Errorf("Search(%q) = %v of %d, want %v", test.text, got, total, test.want)
+		}
+	}
+}
+
+func TestMemoryIndexCases(t *testing.T) {
+	testIndex(t, newTestIndex(t))
+}
+
+// TestMySQLIndexCases needs SEARCH_TEST_MYSQL_DSN to point at an empty
+// scratch database; it creates the tables it searches there.
+func TestMySQLIndexCases(t *testing.T) {
+	dsn := os.Getenv("SEARCH_TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("SEARCH_TEST_MYSQL_DSN is not set")
+	}
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exec := func(statement string, args ...any) {
+		t.Helper()
+		if err := db.Exec(statement, args...).Error; err != nil {
+			t.Fatalf("%s: %v", statement, err)
+		}
+	}
+	exec("DROP TABLE IF EXISTS task_tags, tags, task_comments, tasks")
+	exec("CREATE TABLE tasks (id INT PRIMARY KEY, project_id INT NOT NULL, title VARCHAR(255) NOT NULL, description TEXT, FULLTEXT (title, description))")
+	exec("CREATE TABLE task_comments (id INT AUTO_INCREMENT PRIMARY KEY, task_id INT NOT NULL, body TEXT NOT NULL, FULLTEXT (body))")
+	exec("CREATE TABLE tags (id INT AUTO_INCREMENT PRIMARY KEY, name VARCHAR(100) NOT NULL, FULLTEXT (name))")
+	exec("CREATE TABLE task_tags (task_id INT NOT NULL, tag_id INT NOT NULL)")
+	tagId := 0
+	for _, document := range testDocuments {
+		exec("INSERT INTO tasks VALUES (?, ?, ?, ?)", document.TaskId, document.ProjectId, document.Title, document.Description)
+		for _, comment := range document.Comments {
+			exec("INSERT INTO task_comments (task_id, body) VALUES (?, ?)", document.TaskId, comment)
+		}
+		for _, tag := range document.Tags {
+			tagId++
+			exec("INSERT INTO tags VALUES (?, ?)", tagId, tag)
+			exec("INSERT INTO task_tags VALUES (?, ?)", document.TaskId, tagId)
+		}
+	}
+	t.Cleanup(func() { db.Exec("DROP TABLE IF EXISTS task_tags, tags, task_comments, tasks") })
+
+	testIndex(t, NewMySQLIndex(db))
+}
+
+func TestBooleanExpressions(t *testing.T) {
+	terms, err := ParseQuery(`deploy* "release notes" review -draft`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	match, required, exclude := booleanExpressions(terms)
+	if match != `deploy* "release notes" review` {
+		t.Errorf("match = %q", match)
+	}
+	if want := []string{"+deploy*", `+"release notes"`, "+review"}; !slices.Equal(required, want) {
+		t.Errorf("required = %q, want %q", required, want)
+	}
+	if exclude != "draft" {
+		t.Errorf("exclude = %q, want %q", exclude, "draft")
+	}
+}
@@ -0,0 +1,142 @@
+package search
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// fieldWeights boosts matches in the title over the other fields.
+var fieldWeights = map[string]float64{
+	"title":       3,
+	"tags":        2,
+	"description": 1,
+	"comments":    1,
+}
+
+type memoryDocument struct {
+	projectId uint
+	fields    map[string][]string
+}
+
+// MemoryIndex is an embedded in-process index. It keeps its own copy of the
+// documents, so it suits tests and single instance deployments.
+type MemoryIndex struct {
+	mu        sync.RWMutex
+	documents map[uint]*memoryDocument
+}
+
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{documents: map[uint]*memoryDocument{}}
+}
+
+func (i *MemoryIndex) NeedsSync() bool {
+	return true
+}
+
+func (i *MemoryIndex) Sync(ctx context.Context, documents ...Document) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, document := range documents {
+		fields := map[string][]string{
+			"title":       Tokenize(document.Title),
+			"description": Tokenize(document.Description),
+		}
+		for _, comment := range document.Comments {
+			// a gap keeps phrases from matching across two comments
+			fields["comments"] = append(append(fields["comments"], Tokenize(comment)...), "")
+		}
+		for _, tag := range document.Tags {
+			fields["tags"] = append(append(fields["tags"], Tokenize(tag)...), "")
+		}
+		i.documents[document.TaskId] = &memoryDocument{projectId: document.ProjectId, fields: fields}
+	}
+	return nil
+}
+
+func (i *MemoryIndex) Remove(ctx context.Context, taskIds ...uint) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, taskId := range taskIds {
+		delete(i.documents, taskId)
+	}
+	return nil
+}
+
+func (i *MemoryIndex) Search(ctx context.Context, query Query) ([]Hit, int64, error) {
+	terms, err := ParseQuery(query.Text)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	candidates := map[uint]*memoryDocument{}
+	for taskId, document := range i.documents {
+		if slices.Contains(query.ProjectIds, document.projectId) {
+			candidates[taskId] = document
+		}
+	}
+
+	// document frequency per term, used as a simple idf
+	frequencies := make([]int, len(terms))
+	for t, term := range terms {
+		for _, document := range candidates {
+			if document.matches(term) {
+				frequencies[t]++
+			}
+		}
+	}
+
+	var hits []Hit
+	for taskId, document := range candidates {
+		score := 0.0
+		excluded := false
+		for t, term := range terms {
+			// every term must be found and no negated one, each in any
+			// field
+			if term.Negated == document.matches(term) {
+				excluded = true
+				break
+			}
+			if term.Negated {
+				continue
+			}
+			idf := math.Log(1 + float64(len(candidates))/float64(frequencies[t]))
+			for field, tokens := range document.fields {
+				if count := len(term.occurrences(tokens)); count > 0 {
+					score += fieldWeights[field] * idf * (1 + math.Log(float64(count)))
+				}
+			}
+		}
+		if !excluded && score > 0 {
+			hits = append(hits, Hit{TaskId: taskId, Score: score})
+		}
+	}
+
+	sort.Slice(hits, func(a, b int) bool {
+		if hits[a].Score != hits[b].Score {
+			return hits[a].Score > hits[b].Score
+		}
+		return hits[a].TaskId > hits[b].TaskId
+	})
+
+	total := int64(len(hits))
+	start := min((query.Page-1)*query.Size, len(hits))
+	end := min(start+query.Size, len(hits))
+	return hits[start:end], total, nil
+}
+
+func (d *memoryDocument) matches(term Term) bool {
+	for _, tokens := range d.fields {
+		if len(term.occurrences(tokens)) > 0 {
+			return true
+		}
+	}
+	return false
+}
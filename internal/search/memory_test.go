@@ -0,0 +1,206 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+var testDocuments = []Document{
+	{TaskId: 1, ProjectId: 1, Title: "Deploy release", Description: "Ship the release notes"},
+	{TaskId: 2, ProjectId: 1, Title: "Write release notes", Tags: []string{"docs"}},
+	{TaskId: 3, ProjectId: 1, Title: "Fix login", Description: "deploy after review", Comments: []string{"draft ready", "release it"}},
+	{TaskId: 4, ProjectId: 2, Title: "Deploy release", Description: "another team"},
+	{TaskId: 5, ProjectId: 1, Title: "Plan deployment", Tags: []string{"ops team"}},
+}
+
+func newTestIndex(t *testing.T) *MemoryIndex {
+	t.Helper()
+	index := NewMemoryIndex()
+	if err := index.Sync(context.Background(), testDocuments...); err != nil {
+		t.Fatal(err)
+	}
+	return index
+}
+
+func search(t *testing.T, index *MemoryIndex, text string, projectIds ...uint) []uint {
+	t.Helper()
+	hits, total, err := index.Search(context.Background(), Query{Text: text, ProjectIds: projectIds, Page: 1, Size: 100})
+	if err != nil {
+		t.Fatalf("Search(%q) failed: %v", text, err)
+	}
+	if total != int64(len(hits)) {
+		t.Errorf("Search(%q) total = %d, want %d", text, total, len(hits))
+	}
+	var ids []uint
+	for _, hit := range hits {
+		ids = append(ids, hit.TaskId)
+	}
+	return ids
+}
+
+func TestMemoryIndexSearch(t *testing.T) {
+	index := newTestIndex(t)
+	tests := []struct {
+		name string
+		text string
+		want []uint
+	}{
+		{name: "word in any field", text: "deploy", want: []uint{1, 3}},
+		{name: "case insensitive", text: "DEPLOY", want: []uint{1, 3}},
+		{name: "prefix", text: "deploy*", want: []uint{5, 1, 3}},
+		{name: "phrase", text: `"release notes"`, want: []uint{2, 1}},
+		{name: "phrase in order only", text: `"notes release"`, want: nil},
+		{name: "phrase does not span comments", text: `"ready release"`, want: nil},
+		{name: "tag", text: "docs", want: []uint{2}},
+		{name: "multi word tag", text: `"ops team"`, want: []uint{5}},
+		{name: "comment", text: "draft", want: []uint{3}},
+		{name: "negated term", text: "release -notes", want: []uint{3}},
+		{name: "every term", text: "deploy review", want: []uint{3}},
+		{name: "terms in different fields", text: "login draft", want: []uint{3}},
+		{name: "not every term", text: "login docs", want: nil},
+		{name: "no match", text: "nothing", want: nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := search(t, index, test.text, 1); !slices.Equal(got, test.want) {
+				t.Errorf("Search(%q) = %v, want %v", test.text, got, test.want)
+			}
+		})
+	}
+}
+
+func TestMemoryIndexRanking(t *testing.T) {
+	index := NewMemoryIndex()
+	index.Sync(context.Background(),
+		Document{TaskId: 1, ProjectId: 1, Title: "Budget", Description: "invoice"},
+		Document{TaskId: 2, ProjectId: 1, Title: "Invoice"},
+		Document{TaskId: 3, ProjectId: 1, Title: "Budget", Tags: []string{"invoice"}},
+		Document{TaskId: 4, ProjectId: 1, Title: "Budget", Description: "invoice invoice invoice"},
+		Document{TaskId: 5, ProjectId: 1, Title: "Budget", Comments: []string{"invoice"}},
+		Document{TaskId: 6, ProjectId: 1, Title: "Other"},
+	)
+
+	hits, _, err := index.Search(context.Background(), Query{Text: "invoice", ProjectIds: []uint{1}, Page: 1, Size: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]uint, len(hits))
+	for i, hit := range hits {
+		got[i] = hit.TaskId
+	}
+	// title first, a word repeated three times over a tag, a tag over a
+	// single word, and equal scores newest task first
+	if want := []uint{2, 4, 3, 5, 1}; !slices.Equal(got, want) {
+		t.Errorf("ranking = %v, want %v", got, want)
+	}
+	for i := 1; i < len(hits); i++ {
+		if hits[i].Score > hits[i-1].Score {
+			t.Errorf("hit %d scores %v, more than %v before it", i, hits[i].Score, hits[i-1].Score)
+		}
+	}
+
+	// a rare term weighs more than a common one: both tasks hold both
+	// terms, the one with the rare term in its title comes first
+	index.Sync(context.Background(),
+		Document{TaskId: 7, ProjectId: 1, Title: "Budget", Description: "report"},
+		Document{TaskId: 8, ProjectId: 1, Title: "Report", Description: "budget"},
+	)
+	hits, _, _ = index.Search(context.Background(), Query{Text: "budget report", ProjectIds: []uint{1}, Page: 1, Size: 10})
+	if len(hits) != 2 || hits[0].TaskId != 8 {
+		t.Errorf("hits = %v, want the task with the rare term in its title first", hits)
+	}
+}
+
+func TestMemoryIndexProjects(t *testing.T) {
+	index := newTestIndex(t)
+	if got, want := search(t, index, `"deploy release"`, 1), []uint{1}; !slices.Equal(got, want) {
+		t.Errorf("project 1 = %v, want %v", got, want)
+	}
+	if got, want := search(t, index, `"deploy release"`, 2), []uint{4}; !slices.Equal(got, want) {
+		t.Errorf("project 2 = %v, want %v", got, want)
+	}
+	if got, want := search(t, index, `"deploy release"`, 1, 2), []uint{4, 1}; !slices.Equal(got, want) {
+		t.Errorf("both projects = %v, want %v", got, want)
+	}
+	if got := search(t, index, "deploy", 3); got != nil {
+		t.Errorf("project without tasks = %v, want nothing", got)
+	}
+	if got := search(t, index, "deploy"); got != nil {
+		t.Errorf("no projects = %v, want nothing", got)
+	}
+}
+
+func TestMemoryIndexSyncAndRemove(t *testing.T) {
+	index := newTestIndex(t)
+	ctx := context.Background()
+
+	// a task synced again replaces what was indexed for it
+	if err := index.Sync(ctx, Document{TaskId: 2, ProjectId: 1, Title: "Write changelog"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := search(t, index, "docs", 1); got != nil {
+		t.Errorf("old tag still found: %v", got)
+	}
+	if got, want := search(t, index, "changelog", 1), []uint{2}; !slices.Equal(got, want) {
+		t.Errorf("new title = %v, want %v", got, want)
+	}
+
+	// a task moved to another project is only found there
+	if err := index.Sync(ctx, Document{TaskId: 2, ProjectId: 2, Title: "Write changelog"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := search(t, index, "changelog", 1); got != nil {
+		t.Errorf("moved task found in its old project: %v", got)
+	}
+
+	if err := index.Remove(ctx, 1, 3, 99); err != nil {
+		t.Fatal(err)
+	}
+	if got := search(t, index, "deploy", 1); got != nil {
+		t.Errorf("removed tasks still found: %v", got)
+	}
+	if !index.NeedsSync() {
+		t.Error("the memory index must be synced")
+	}
+}
+
+func TestMemoryIndexPaging(t *testing.T) {
+	index := NewMemoryIndex()
+	for id := uint(1); id <= 5; id++ {
+		index.Sync(context.Background(), Document{TaskId: id, ProjectId: 1, Title: "task"})
+	}
+
+	var pages [][]uint
+	for page := 1; page <= 4; page++ {
+		hits, total, err := index.Search(context.Background(), Query{Text: "task", ProjectIds: []uint{1}, Page: page, Size: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 5 {
+			t.Errorf("page %d total = %d, want 5", page, total)
+		}
+		ids := []uint{}
+		for _, hit := range hits {
+			ids = append(ids, hit.TaskId)
+		}
+		pages = append(pages, ids)
+	}
+	want := [][]uint{{5, 4}, {3, 2}, {1}, {}}
+	for i := range want {
+		if !slices.Equal(pages[i], want[i]) {
+			t.Errorf("page %d = %v, want %v", i+1, pages[i], want[i])
+		}
+	}
+}
+
+func TestMemoryIndexInvalidQuery(t *testing.T) {
+	index := newTestIndex(t)
+	for _, text := range []string{"", "   ", "-draft", `-"release notes"`, `""`} {
+		_, _, err := index.Search(context.Background(), Query{Text: text, ProjectIds: []uint{1}, Page: 1, Size: 10})
+		if !errors.Is(err, ErrEmptyQuery) {
+			t.Errorf("Search(%q) error = %v, want %v", text, err, ErrEmptyQuery)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package search
+
+import (
+	"context"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// MySQLIndex searches the FULLTEXT indexes on tasks, task_comments and tags
+// directly, so there is nothing to keep in sync.
+type MySQLIndex struct {
+	DB *gorm.DB
+}
+
+func NewMySQLIndex(db *gorm.DB) *MySQLIndex {
+	return &MySQLIndex{DB: db}
+}
+
+func (i *MySQLIndex) NeedsSync() bool {
+	return false
+}
+
+func (i *MySQLIndex) Sync(ctx context.Context, documents ...Document) error {
+	return nil
+}
+
+func (i *MySQLIndex) Remove(ctx context.Context, taskIds ...uint) error {
+	return nil
+}
+
+const mysqlScore = `(MATCH(tasks.title, tasks.description) AGAINST (@match IN BOOLEAN MODE) * 2
+	+ COALESCE((SELECT SUM(MATCH(task_comments.body) AGAINST (@match IN BOOLEAN MODE)) FROM task_comments WHERE task_comments.task_id = tasks.id), 0)
+	+ COALESCE((SELECT SUM(MATCH(tags.name) AGAINST (@match IN BOOLEAN MODE)) * 1.5 FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE task_tags.task_id = tasks.id), 0))`
+
+// mysqlRequire keeps the tasks with the required term in one of their
+// fields. A term is required by itself since a single MATCH only sees one
+// field: a task with one term in its title and the other in a tag holds
+// them all.
+const mysqlRequire = `(MATCH(tasks.title, tasks.description) AGAINST (@term IN BOOLEAN MODE)
+	OR EXISTS (SELECT 1 FROM task_comments WHERE task_comments.task_id = tasks.id AND MATCH(task_comments.body) AGAINST (@term IN BOOLEAN MODE))
+	OR EXISTS (SELECT 1 FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE task_tags.task_id = tasks.id AND MATCH(tags.name) AGAINST (@term IN BOOLEAN MODE)))`
+
+const mysqlExclude = `NOT MATCH(tasks.title, tasks.description) AGAINST (@exclude IN BOOLEAN MODE)
+	AND NOT EXISTS (SELECT 1 FROM task_comments WHERE task_comments.task_id = tasks.id AND MATCH(task_comments.body) AGAINST (@exclude IN BOOLEAN MODE))
+	AND NOT EXISTS (SELECT 1 FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE task_tags.task_id = tasks.id AND MATCH(tags.name) AGAINST (@exclude IN BOOLEAN MODE))`
+
+func (i *MySQLIndex) Search(ctx context.Context, query Query) ([]Hit, int64, error) {
+	terms, err := ParseQuery(query.Text)
+	if err != nil {
+		return nil, 0, err
+	}
+	match, required, exclude := booleanExpressions(terms)
+	arguments := map[string]any{"match": match, "exclude": exclude}
+
+	scored := i.DB.WithContext(ctx).Table("tasks").
+		Select("tasks.id AS task_id, "+mysqlScore+" AS score", arguments).
+		Where("tasks.project_id IN ?", query.ProjectIds)
+	for _, term := range required {
+		scored = scored.Where(mysqlRequire, map[string]any{"term": term})
+	}
+	if exclude != "" {
+		scored = scored.Where(mysqlExclude, arguments)
+	}
+	scored = scored.Having("score > 0").Session(&gorm.Session{})
+
+	var total int64
+	if err := i.DB.WithContext(ctx).Table("(?) AS scored", scored).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var hits []Hit
+	err = scored.Order("score DESC, tasks.id DESC").
+		Offset((query.Page - 1) * query.Size).Limit(query.Size).
+		Scan(&hits).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return hits, total, nil
+}
+
+// booleanExpressions renders the terms as MySQL boolean mode expressions:
+// one for ranking, one per term every result must hold, marked with "+",
+// and one holding the excluded terms.
+func booleanExpressions(terms []Term) (string, []string, string) {
+	var match, required, exclude []string
+	for _, term := range terms {
+		expression := strings.Join(term.Words, " ")
+		if term.Phrase {
+			expression = `"` + expression + `"`
+		} else if term.Prefix {
+			expression += "*"
+		}
+		if term.Negated {
+			exclude = append(exclude, expression)
+		} else {
+			match = append(match, expression)
+			required = append(required, "+"+expression)
+		}
+	}
+	return strings.Join(match, " "), required, strings.Join(exclude, " ")
+}
@@ -0,0 +1,118 @@
+package search
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+var ErrEmptyQuery = errors.New("search query has no positive terms")
+
+// Term is a single word or a quoted phrase of a search query. A trailing
+// asterisk turns a word into a prefix match and a leading minus excludes it.
+type Term struct {
+	Words   []string
+	Phrase  bool
+	Prefix  bool
+	Negated bool
+}
+
+// ParseQuery splits raw user input into terms, e.g.
+// `deploy* "release notes" -draft`.
+func ParseQuery(text string) ([]Term, error) {
+	var terms []Term
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+		negated := false
+		if runes[i] == '-' {
+			negated = true
+			i++
+		}
+		if i < len(runes) && runes[i] == '"' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if words := Tokenize(string(runes[i+1 : end])); len(words) > 0 {
+				terms = append(terms, Term{Words: words, Phrase: len(words) > 1, Negated: negated})
+			}
+			i = end + 1
+			continue
+		}
+		end := i
+		for end < len(runes) && !unicode.IsSpace(runes[end]) {
+			end++
+		}
+		raw := string(runes[i:end])
+		prefix := strings.HasSuffix(raw, "*")
+		for _, word := range Tokenize(raw) {
+			terms = append(terms, Term{Words: []string{word}, Prefix: prefix, Negated: negated})
+		}
+		i = end
+	}
+
+	for _, term := range terms {
+		if !term.Negated {
+			return terms, nil
+		}
+	}
+	return nil, ErrEmptyQuery
+}
+
+// Tokenize lowercases text and splits it on anything that is not a letter
+// or a digit.
+func Tokenize(text string) []string {
+	var tokens []string
+	for _, span := range tokenSpans(text) {
+		tokens = append(tokens, strings.ToLower(text[span[0]:span[1]]))
+	}
+	return tokens
+}
+
+func tokenSpans(text string) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range text {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if word && start < 0 {
+			start = i
+		} else if !word && start >= 0 {
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(text)})
+	}
+	return spans
+}
+
+func (t Term) matchesWord(token string, word string) bool {
+	if t.Prefix {
+		return strings.HasPrefix(token, word)
+	}
+	return token == word
+}
+
+// occurrences returns how often the term appears in a tokenized field and
+// the token positions where each occurrence starts.
+func (t Term) occurrences(tokens []string) []int {
+	var positions []int
+	for i := 0; i+len(t.Words) <= len(tokens); i++ {
+		matched := true
+		for j, word := range t.Words {
+			if !t.matchesWord(tokens[i+j], word) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			positions = append(positions, i)
+		}
+	}
+	return positions
+}
@@ -0,0 +1,63 @@
+package search
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		text string
+		want []Term
+	}{
+		{text: "deploy", want: []Term{{Words: []string{"deploy"}}}},
+		{text: "Deploy* \"Release  Notes\" -draft", want: []Term{
+			{Words: []string{"deploy"}, Prefix: true},
+			{Words: []string{"release", "notes"}, Phrase: true},
+			{Words: []string{"draft"}, Negated: true},
+		}},
+		{text: `"one"`, want: []Term{{Words: []string{"one"}}}},
+		{text: `-"bad idea" good`, want: []Term{
+			{Words: []string{"bad", "idea"}, Phrase: true, Negated: true},
+			{Words: []string{"good"}},
+		}},
+		{text: `"unclosed phrase`, want: []Term{{Words: []string{"unclosed", "phrase"}, Phrase: true}}},
+		{text: "e-mail", want: []Term{{Words: []string{"e"}}, {Words: []string{"mail"}}}},
+	}
+	for _, test := range tests {
+		got, err := ParseQuery(test.text)
+		if err != nil {
+			t.Errorf("ParseQuery(%q) failed: %v", test.text, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseQuery(%q) = %+v, want %+v", test.text, got, test.want)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("Fix bug #42: Straße, naïve café!")
+	want := []string{"fix", "bug", "42", "straße", "naïve", "café"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Tokenize() = %q, want %q", got, want)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	terms, _ := ParseQuery(`deploy* "release notes" -draft`)
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{text: "Deploying the <b>release notes</b> draft", want: []string{"<mark>Deploying</mark> the &lt;b&gt;<mark>release</mark> <mark>notes</mark>&lt;/b&gt; draft"}},
+		{text: "nothing here", want: nil},
+		{text: "release only", want: nil},
+	}
+	for _, test := range tests {
+		if got := Highlight(test.text, terms, 3); !slices.Equal(got, test.want) {
+			t.Errorf("Highlight(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
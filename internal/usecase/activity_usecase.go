@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model/converter"
+	"github.com/abdisetiakawan/go-clean-arch/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ActivityUseCase reads the activity feed. Activities are written by the
+// use cases that change tasks, tags and comments, in the same transaction
+// as the change.
+type ActivityUseCase struct {
+	DB                 *gorm.DB
+	Log                *logrus.Logger
+	Validate           *validator.Validate
+	ActivityRepository *repository.ActivityRepository
+}
+
+func NewActivityUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, activityRepository *repository.ActivityRepository) *ActivityUseCase {
+	return &ActivityUseCase{
+		DB:                 db,
+		Log:                log,
+		Validate:           validate,
+		ActivityRepository: activityRepository,
+	}
+}
+
+func (c *ActivityUseCase) Search(ctx context.Context, request *model.SearchActivityRequest) ([]model.ActivityResponse, *model.PageMetadata, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return nil, nil, model.ErrBadRequest
+	}
+	if request.EntityId != 0 && request.EntityType == "" {
+		return nil, nil, model.NewApiError(model.ErrBadRequest.StatusCode, "entity_id requires entity_type")
+	}
+	activities, paging, err := c.ActivityRepository.Search(tx, request)
+	if err != nil {
+		c.Log.WithError(err).Error("error search activities")
+		return nil, nil, pageError(err, model.ErrInternalServer)
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error search activities")
+		return nil, nil, model.ErrInternalServer
+	}
+
+	responses := make([]model.ActivityResponse, len(activities))
+	for i, activity := range activities {
+		responses[i] = *converter.ActivityToResponse(&activity)
+	}
+	return responses, paging, nil
+}
+
+// newActivity describes a change made by actor. Titles and names are
+// shortened so the summary fits its column.
+func newActivity(projectId uint, actor string, activityType string, entityType string, entityId uint, data map[string]any, format string, args ...any) *entity.Activity {
+	for i, arg := range args {
+		if value, ok := arg.(string); ok {
+			args[i] = truncate(value, 80)
+		}
+	}
+	activity := &entity.Activity{
+		ProjectId:  projectId,
+		Actor:      actor,
+		Type:       activityType,
+		EntityType: entityType,
+		EntityId:   entityId,
+		Summary:    truncate(fmt.Sprintf(format, args...), 255),
+	}
+	if len(data) > 0 {
+		dataJSON, _ := json.Marshal(data)
+		activity.Data = string(dataJSON)
+	}
+	return activity
+}
+
+func taskCreatedActivity(task *entity.Task, actor string) *entity.Activity {
+	return newActivity(task.ProjectId, actor, model.ActivityTaskCreated, "task", task.ID, nil, "Created task %q", task.Title)
+}
+
+func taskDeletedActivity(task *entity.Task, actor string) *entity.Activity {
+	return newActivity(task.ProjectId, actor, model.ActivityTaskDeleted, "task", task.ID, nil, "Deleted task %q", task.Title)
+}
+
+// taskEditActivities describes an edit of a task: a status change and an
+// update of the other fields are separate activities. extra names changes
+// not visible on the task itself, such as its tags.
+func taskEditActivities(before *entity.Task, after *entity.Task, actor string, extra ...string) []*entity.Activity {
+	var activities []*entity.Activity
+	if before.Status != after.Status {
+		activities = append(activities, newActivity(after.ProjectId, actor, model.ActivityTaskStatusChanged, "task", after.ID,
+			map[string]any{"from": before.Status, "to": after.Status},
+			"Changed status of %q from %s to %s", after.Title, before.Status, after.Status))
+	}
+	if fields := append(taskChanges(before, after), extra...); len(fields) > 0 {
+		activities = append(activities, newActivity(after.ProjectId, actor, model.ActivityTaskUpdated, "task", after.ID,
+			map[string]any{"fields": fields},
+			"Updated %s of %q", strings.Join(fields, ", "), after.Title))
+	}
+	return activities
+}
+
+func taskTagActivity(task *entity.Task, tag *entity.Tag, actor string, added bool) *entity.Activity {
+	data := map[string]any{"tag_id": tag.ID, "tag": tag.Name}
+	if added {
+		return newActivity(task.ProjectId, actor, model.ActivityTaskTagAdded, "task", task.ID, data, "Added tag %q to %q", tag.Name, task.Title)
+	}
+	return newActivity(task.ProjectId, actor, model.ActivityTaskTagRemoved, "task", task.ID, data, "Removed tag %q from %q", tag.Name, task.Title)
+}
+
+func commentActivity(task *entity.Task, comment *entity.Comment, actor string, added bool) *entity.Activity {
+	data := map[string]any{"comment_id": comment.ID}
+	if added {
+		return newActivity(task.ProjectId, actor, model.ActivityCommentAdded, "task", task.ID, data, "Commented on %q", task.Title)
+	}
+	return newActivity(task.ProjectId, actor, model.ActivityCommentDeleted, "task", task.ID, data, "Deleted a comment on %q", task.Title)
+}
+
+func tagCreatedActivity(tag *entity.Tag, actor string) *entity.Activity {
+	return newActivity(tag.ProjectId, actor, model.ActivityTagCreated, "tag", tag.ID, nil, "Created tag %q", tag.Name)
+}
+
+func tagDeletedActivity(tag *entity.Tag, actor string) *entity.Activity {
+	return newActivity(tag.ProjectId, actor, model.ActivityTagDeleted, "tag", tag.ID, nil, "Deleted tag %q", tag.Name)
+}
+
+// tagRenamedActivity describes a change of the name of a tag.
+func tagRenamedActivity(before *entity.Tag, after *entity.Tag, actor string) *entity.Activity {
+	return newActivity(after.ProjectId, actor, model.ActivityTagUpdated, "tag", after.ID,
+		map[string]any{"from": before.Name, "to": after.Name},
+		"Renamed tag %q to %q", before.Name, after.Name)
+}
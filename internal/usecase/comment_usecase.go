@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model/converter"
+	"github.com/abdisetiakawan/go-clean-arch/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type CommentUseCase struct {
+	DB                      *gorm.DB
+	Log                     *logrus.Logger
+	Validate                *validator.Validate
+	CommentRepository       *repository.CommentRepository
+	TaskRepository          *repository.TaskRepository
+	ProjectMemberRepository *repository.ProjectMemberRepository
+	Indexer                 *SearchUseCase
+	ActivityRepository      *repository.ActivityRepository
+	Events                  *EventUseCase
+	Notifier                helper.Notifier
+}
+
+// mentionPattern matches an @ followed by an email address.
+var mentionPattern = regexp.MustCompile(`@([A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+
+func NewCommentUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, commentRepository *repository.CommentRepository, taskRepository *repository.TaskRepository, projectMemberRepository *repository.ProjectMemberRepository, indexer *SearchUseCase, activityRepository *repository.ActivityRepository, events *EventUseCase, notifier helper.Notifier) *CommentUseCase {
+	return &CommentUseCase{
+		DB:                      db,
+		Log:                     log,
+		Validate:                validate,
+		CommentRepository:       commentRepository,
+		TaskRepository:          taskRepository,
+		ProjectMemberRepository: projectMemberRepository,
+		Indexer:                 indexer,
+		ActivityRepository:      activityRepository,
+		Events:                  events,
+		Notifier:                notifier,
+	}
+}
+
+func (c *CommentUseCase) Create(ctx context.Context, request *model.CreateCommentRequest) (*model.CommentResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	task := new(entity.Task)
+	if err := c.TaskRepository.FindByEmailAndId(tx, task, request.TaskId, request.Email); err != nil {
+		c.Log.WithError(err).Error("error search task")
+		return nil, model.ErrNotFound
+	}
+	comment := &entity.Comment{
+		TaskId: task.ID,
+		Email:  request.Email,
+		Body:   request.Body,
+	}
+	if err := c.CommentRepository.Create(tx, comment); err != nil {
+		c.Log.WithError(err).Error("error create comment")
+		return nil, model.ErrInternalServer
+	}
+	activity := commentActivity(task, comment, request.Email, true)
+	if err := c.ActivityRepository.Record(tx, activity); err != nil {
+		c.Log.WithError(err).Error("error record activity")
+		return nil, model.ErrInternalServer
+	}
+	members, err := c.ProjectMemberRepository.Emails(tx, task.ProjectId)
+	if err != nil {
+		c.Log.WithError(err).Error("error search project member")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error create comment")
+		return nil, model.ErrInternalServer
+	}
+	c.Events.Publish(ctx, activity)
+
+	c.Indexer.Refresh(ctx, task.ID)
+	c.Notifier.Notify(ctx, mentionNotifications(task, comment, members)...)
+	return converter.CommentToResponse(comment), nil
+}
+
+func (c *CommentUseCase) Search(ctx context.Context, request *model.SearchCommentRequest) ([]model.CommentResponse, *model.PageMetadata, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return nil, nil, model.ErrBadRequest
+	}
+	task := new(entity.Task)
+	if err := c.TaskRepository.FindByEmailAndId(tx, task, request.TaskId, request.Email); err != nil {
+		c.Log.WithError(err).Error("error search task")
+		return nil, nil, model.ErrNotFound
+	}
+	comments, paging, err := c.CommentRepository.SearchByTask(tx, task.ID, request)
+	if err != nil {
+		c.Log.WithError(err).Error("error search comments")
+		return nil, nil, pageError(err, model.ErrInternalServer)
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error search comments")
+		return nil, nil, model.ErrInternalServer
+	}
+
+	responses := make([]model.CommentResponse, len(comments))
+	for i, comment := range comments {
+		responses[i] = *converter.CommentToResponse(&comment)
+	}
+	return responses, paging, nil
+}
+
+// Delete lets authors remove their own comments and editors remove any
+// comment on the task.
+func (c *CommentUseCase) Delete(ctx context.Context, request *model.GetCommentRequest) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return model.ErrBadRequest
+	}
+	task := new(entity.Task)
+	if err := c.TaskRepository.FindByEmailAndId(tx, task, request.TaskId, request.Email); err != nil {
+		c.Log.WithError(err).Error("error search task")
+		return model.ErrNotFound
+	}
+	comment := new(entity.Comment)
+	if err := c.CommentRepository.FindByTaskAndId(tx, comment, request.ID, task.ID); err != nil {
+		c.Log.WithError(err).Error("error search comment")
+		return model.ErrNotFound
+	}
+	if comment.Email != request.Email {
+		if err := checkWriteAccess(tx, c.ProjectMemberRepository, task.ProjectId, request.Email); err != nil {
+			c.Log.WithError(err).Error("error delete comment")
+			return err
+		}
+	}
+	if err := c.CommentRepository.Delete(tx, comment); err != nil {
+		c.Log.WithError(err).Error("error delete comment")
+		return model.ErrInternalServer
+	}
+	activity := commentActivity(task, comment, request.Email, false)
+	if err := c.ActivityRepository.Record(tx, activity); err != nil {
+		c.Log.WithError(err).Error("error record activity")
+		return model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error delete comment")
+		return model.ErrInternalServer
+	}
+	c.Events.Publish(ctx, activity)
+
+	c.Indexer.Refresh(ctx, task.ID)
+	return nil
+}
+
+// mentionNotifications notifies the project members mentioned in the
+// comment as @email, except its author.
+func mentionNotifications(task *entity.Task, comment *entity.Comment, members []string) []model.Notification {
+	var notifications []model.Notification
+	var mentioned []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(comment.Body, -1) {
+		email := strings.ToLower(match[1])
+		if email == comment.Email || slices.Contains(mentioned, email) || !slices.Contains(members, email) {
+			continue
+		}
+		mentioned = append(mentioned, email)
+		notifications = append(notifications, model.Notification{
+			Email: email,
+			Type:  model.NotificationCommentMentioned,
+			Title: comment.Email + " mentioned you on " + task.Title,
+			Body:  truncate(comment.Body, 500),
+			Data:  map[string]any{"task_id": task.ID, "project_id": task.ProjectId, "comment_id": comment.ID, "actor": comment.Email},
+		})
+	}
+	return notifications
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model/converter"
+	"github.com/abdisetiakawan/go-clean-arch/internal/realtime"
+	"github.com/abdisetiakawan/go-clean-arch/internal/repository"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// EventUseCase streams changes to the members of their projects. The
+// events are the activities recorded with the changes, published once
+// their transaction is committed.
+type EventUseCase struct {
+	DB                      *gorm.DB
+	Log                     *logrus.Logger
+	ProjectMemberRepository *repository.ProjectMemberRepository
+	Broker                  *realtime.Broker
+}
+
+func NewEventUseCase(db *gorm.DB, log *logrus.Logger, projectMemberRepository *repository.ProjectMemberRepository, broker *realtime.Broker) *EventUseCase {
+	return &EventUseCase{
+		DB:                      db,
+		Log:                     log,
+		ProjectMemberRepository: projectMemberRepository,
+		Broker:                  broker,
+	}
+}
+
+// EventStream is a subscription to the events of a user. Missed holds the
+// kept events after the id the stream resumes from; Reset is set when
+// some of them were already dropped and the client has to reload.
+type EventStream struct {
+	*realtime.Subscription
+	Missed []realtime.Event
+	Reset  bool
+}
+
+// Publish sends the activities to the current members of their projects.
+// A failure only loses the events, the changes are already committed.
+func (c *EventUseCase) Publish(ctx context.Context, activities ...*entity.Activity) {
+	byProject := map[uint][]realtime.Event{}
+	var projectIds []uint
+	for _, activity := range activities {
+		data, _ := json.Marshal(converter.ActivityToResponse(activity))
+		if _, ok := byProject[activity.ProjectId]; !ok {
+			projectIds = append(projectIds, activity.ProjectId)
+		}
+		byProject[activity.ProjectId] = append(byProject[activity.ProjectId], realtime.Event{
+			ID:   activity.ID,
+			Type: activity.Type,
+			Data: data,
+		})
+	}
+	for _, projectId := range projectIds {
+		emails, err := c.ProjectMemberRepository.Emails(c.DB.WithContext(ctx), projectId)
+		if err != nil {
+			c.Log.WithError(err).Warn("error load project members for events")
+			continue
+		}
+		if err := c.Broker.Publish(ctx, emails, byProject[projectId]...); err != nil {
+			c.Log.WithError(err).Warn("error publish events")
+		}
+	}
+}
+
+// Subscribe starts a stream of the events of the user. A lastEventId
+// resumes the stream after that event.
+func (c *EventUseCase) Subscribe(ctx context.Context, email string, lastEventId string) (*EventStream, error) {
+	var after uint64
+	if lastEventId != "" {
+		var err error
+		if after, err = strconv.ParseUint(lastEventId, 10, 64); err != nil {
+			c.Log.WithError(err).Error("error parse last event id")
+			return nil, model.NewApiError(model.ErrBadRequest.StatusCode, "Last-Event-ID must be an event id")
+		}
+	}
+
+	// subscribe before reading the buffer so no event falls in between
+	stream := &EventStream{Subscription: c.Broker.Subscribe(email)}
+	if lastEventId == "" {
+		return stream, nil
+	}
+	missed, gap, err := c.Broker.Replay(ctx, email, uint(after))
+	if err != nil {
+		stream.Close()
+		c.Log.WithError(err).Error("error replay events")
+		return nil, model.ErrInternalServer
+	}
+	stream.Missed = missed
+	stream.Reset = gap
+	return stream, nil
+}
@@ -0,0 +1,473 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/filter"
+	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
+	"github.com/abdisetiakawan/go-clean-arch/internal/ical"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const calendarProductId = "-//go-clean-arch//tasks//EN"
+
+// generatedUid matches the uid exported for tasks that were not imported,
+// e.g. task-42@go-clean-arch.
+var generatedUid = regexp.MustCompile(`^task-(\d+)@go-clean-arch$`)
+
+var icalStatuses = map[string]string{
+	"pending":     "NEEDS-ACTION",
+	"in_progress": "IN-PROCESS",
+	"completed":   "COMPLETED",
+}
+
+// icalPriorities maps task priorities onto the 1 (highest) to 9 (lowest)
+// scale of RFC 5545.
+var icalPriorities = map[string]int{"urgent": 1, "high": 3, "medium": 5, "low": 9}
+
+type ICalUseCase struct {
+	DB                      *gorm.DB
+	Log                     *logrus.Logger
+	Validate                *validator.Validate
+	CalendarFeedRepository  *repository.CalendarFeedRepository
+	UserRepository          *repository.UserRepository
+	TaskRepository          *repository.TaskRepository
+	TagRepository           *repository.TagRepository
+	TaskTagRepository       *repository.TaskTagRepository
+	ProjectMemberRepository *repository.ProjectMemberRepository
+	Indexer                 *SearchUseCase
+	Cache                   *helper.CacheHelper
+	ActivityRepository      *repository.ActivityRepository
+	Events                  *EventUseCase
+}
+
+func NewICalUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, calendarFeedRepository *repository.CalendarFeedRepository, userRepository *repository.UserRepository, taskRepository *repository.TaskRepository, tagRepository *repository.TagRepository, taskTagRepository *repository.TaskTagRepository, projectMemberRepository *repository.ProjectMemberRepository, indexer *SearchUseCase, cache *helper.CacheHelper, activityRepository *repository.ActivityRepository, events *EventUseCase) *ICalUseCase {
+	return &ICalUseCase{
+		DB:                      db,
+		Log:                     log,
+		Validate:                validate,
+		CalendarFeedRepository:  calendarFeedRepository,
+		UserRepository:          userRepository,
+		TaskRepository:          taskRepository,
+		TagRepository:           tagRepository,
+		TaskTagRepository:       taskTagRepository,
+		ProjectMemberRepository: projectMemberRepository,
+		Indexer:                 indexer,
+		Cache:                   cache,
+		ActivityRepository:      activityRepository,
+		Events:                  events,
+	}
+}
+
+// Feed returns the feed token of the user, creating one on first use.
+func (c *ICalUseCase) Feed(ctx context.Context, email string) (*model.CalendarFeedResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	feed := new(entity.CalendarFeed)
+	err := c.CalendarFeedRepository.FindByEmail(tx, feed, email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		feed = &entity.CalendarFeed{Email: email, Token: newFeedToken()}
+		err = c.CalendarFeedRepository.Create(tx, feed)
+	}
+	if err != nil {
+		c.Log.WithError(err).Error("error create calendar feed")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error create calendar feed")
+		return nil, model.ErrInternalServer
+	}
+	return &model.CalendarFeedResponse{Token: feed.Token}, nil
+}
+
+// RegenerateFeed replaces the feed token, so the old URL stops working.
+func (c *ICalUseCase) RegenerateFeed(ctx context.Context, email string) (*model.CalendarFeedResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	feed := new(entity.CalendarFeed)
+	err := c.CalendarFeedRepository.FindByEmail(tx, feed, email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		feed = &entity.CalendarFeed{Email: email, Token: newFeedToken()}
+		err = c.CalendarFeedRepository.Create(tx, feed)
+	} else if err == nil {
+		feed.Token = newFeedToken()
+		err = c.CalendarFeedRepository.Update(tx, feed)
+	}
+	if err != nil {
+		c.Log.WithError(err).Error("error regenerate calendar feed")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error regenerate calendar feed")
+		return nil, model.ErrInternalServer
+	}
+	return &model.CalendarFeedResponse{Token: feed.Token}, nil
+}
+
+func (c *ICalUseCase) RevokeFeed(ctx context.Context, email string) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	feed := new(entity.CalendarFeed)
+	if err := c.CalendarFeedRepository.FindByEmail(tx, feed, email); err != nil {
+		c.Log.WithError(err).Error("error search calendar feed")
+		return model.ErrNotFound
+	}
+	if err := c.CalendarFeedRepository.Delete(tx, feed); err != nil {
+		c.Log.WithError(err).Error("error delete calendar feed")
+		return model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error delete calendar feed")
+		return model.ErrInternalServer
+	}
+	return nil
+}
+
+// Export renders the tasks with a due date the feed owner can access as an
+// iCalendar document.
+func (c *ICalUseCase) Export(ctx context.Context, request *model.CalendarFeedRequest) ([]byte, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return nil, model.ErrBadRequest
+	}
+	feed := new(entity.CalendarFeed)
+	if err := c.CalendarFeedRepository.FindByToken(tx, feed, request.Token); err != nil {
+		c.Log.WithError(err).Error("error search calendar feed")
+		return nil, model.ErrNotFound
+	}
+	user := new(entity.User)
+	if err := c.UserRepository.FindByEmail(tx, user, feed.Email); err != nil {
+		c.Log.WithError(err).Error("error search user")
+		return nil, model.ErrInternalServer
+	}
+	search := &model.SearchTaskRequest{
+		Email:     user.Email,
+		ProjectId: request.ProjectId,
+		Timezone:  user.Timezone,
+	}
+	tasks, err := c.TaskRepository.FindScheduled(tx, search, feedFilter(request))
+	if err != nil {
+		c.Log.WithError(err).Error("error search task")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error search task")
+		return nil, model.ErrInternalServer
+	}
+
+	location := helper.Location(user.Timezone)
+	calendar := ical.NewComponent("VCALENDAR")
+	calendar.Add("VERSION", "2.0")
+	calendar.Add("PRODID", calendarProductId)
+	calendar.Add("CALSCALE", "GREGORIAN")
+	calendar.AddText("X-WR-CALNAME", "Tasks")
+	calendar.AddText("X-WR-TIMEZONE", location.String())
+	for i := range tasks {
+		calendar.Components = append(calendar.Components, taskToComponent(&tasks[i], request.Component, location))
+	}
+	var buffer bytes.Buffer
+	if err := ical.Encode(&buffer, calendar); err != nil {
+		c.Log.WithError(err).Error("error encode calendar")
+		return nil, model.ErrInternalServer
+	}
+	return buffer.Bytes(), nil
+}
+
+// Import creates a task for every VTODO and VEVENT of the file. Items whose
+// UID matches a task of the project update that task instead, so exporting
+// and importing again does not duplicate anything.
+func (c *ICalUseCase) Import(ctx context.Context, request *model.ImportCalendarRequest) (*model.ImportResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	projectId, err := c.ProjectMemberRepository.ResolveWritable(tx, request.ProjectId, request.Email)
+	if err != nil {
+		c.Log.WithError(err).Error("error resolve project")
+		return nil, model.ErrForbidden
+	}
+	calendar, err := ical.Decode(bytes.NewReader(request.Data))
+	if err != nil {
+		c.Log.WithError(err).Error("error decode calendar")
+		return nil, model.NewApiError(model.ErrBadRequest.StatusCode, err.Error())
+	}
+	location := helper.Location(request.Timezone)
+	tags := newTagResolver(c.TagRepository, projectId, request.Email, true)
+	ends := newColumnEnds(c.TaskRepository, projectId)
+	response := &model.ImportResponse{Skipped: []model.ImportSkipped{}}
+	var created, updated []uint
+	var activities []*entity.Activity
+	item := 0
+	for _, component := range calendar.Components {
+		if component.Name != "VTODO" && component.Name != "VEVENT" {
+			continue
+		}
+		item++
+		uid := component.Text("UID")
+		task := new(entity.Task)
+		existing := false
+		if uid != "" {
+			var id uint
+			if match := generatedUid.FindStringSubmatch(uid); match != nil {
+				parsed, _ := strconv.ParseUint(match[1], 10, 32)
+				id = uint(parsed)
+			}
+			err := c.TaskRepository.FindByUid(tx, task, projectId, uid, id)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				c.Log.WithError(err).Error("error search task")
+				return nil, model.ErrInternalServer
+			}
+			existing = err == nil
+		}
+		before := *task
+		if reason := componentToTask(task, component, location); reason != "" {
+			response.Skipped = append(response.Skipped, model.ImportSkipped{Item: item, UID: uid, Reason: reason})
+			continue
+		}
+
+		if existing {
+			err = c.TaskRepository.Update(tx, task)
+			updated = append(updated, task.ID)
+			activities = append(activities, taskEditActivities(&before, task, request.Email)...)
+		} else {
+			task.Email = request.Email
+			task.ProjectId = projectId
+			if uid != "" {
+				task.Uid = &uid
+			}
+			task.Position, err = ends.next(tx, task.Status)
+			if err == nil {
+				err = c.TaskRepository.Create(tx, task)
+			}
+			created = append(created, task.ID)
+			activities = append(activities, taskCreatedActivity(task, request.Email))
+		}
+		if err != nil {
+			c.Log.WithError(err).Error("error import task")
+			return nil, model.ErrInternalServer
+		}
+		tagIds, err := tags.resolve(tx, component.List("CATEGORIES"))
+		if err != nil {
+			c.Log.WithError(err).Error("error import tags")
+			return nil, model.ErrInternalServer
+		}
+		if err := c.TaskTagRepository.Attach(tx, task.ID, tagIds); err != nil {
+			c.Log.WithError(err).Error("error import task tags")
+			return nil, model.ErrInternalServer
+		}
+	}
+	if err := c.ActivityRepository.Record(tx, activities...); err != nil {
+		c.Log.WithError(err).Error("error record activity")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error import calendar")
+		return nil, model.ErrInternalServer
+	}
+	c.Events.Publish(ctx, activities...)
+
+	if err := forgetCachedTasks(ctx, c.DB, c.ProjectMemberRepository, c.Cache, projectId, updated); err != nil {
+		c.Log.WithError(err).Warn("error load project members for cache invalidation")
+	}
+	c.Indexer.Refresh(ctx, append(created, updated...)...)
+	response.Created = len(created)
+	response.Updated = len(updated)
+	return response, nil
+}
+
+func newFeedToken() string {
+	data := make([]byte, 32)
+	rand.Read(data)
+	return hex.EncodeToString(data)
+}
+
+// feedFilter turns the tag and status query of a feed into a filter
+// expression, a list of values matches any of them.
+func feedFilter(request *model.CalendarFeedRequest) filter.Expr {
+	var exprs []filter.Expr
+	if len(request.Tags) > 0 {
+		exprs = append(exprs, &filter.Condition{Field: "tag", Op: ":", Value: strings.Join(request.Tags, ",")})
+	}
+	if len(request.Statuses) > 0 {
+		exprs = append(exprs, &filter.Condition{Field: "status", Op: ":", Value: strings.Join(request.Statuses, ",")})
+	}
+	if len(exprs) == 0 {
+		return nil
+	}
+	return &filter.And{Exprs: exprs}
+}
+
+func taskUid(task *entity.Task) string {
+	if task.Uid != nil {
+		return *task.Uid
+	}
+	return fmt.Sprintf("task-%d@go-clean-arch", task.ID)
+}
+
+// taskDue returns the due date as an all day value, or the due time in the
+// user's zone when the task has one.
+func taskDue(task *entity.Task, location *time.Location) (time.Time, bool) {
+	due := *task.DueDate
+	if task.DueTime == nil {
+		return due, true
+	}
+	clock, err := time.Parse("15:04", (*task.DueTime)[:min(5, len(*task.DueTime))])
+	if err != nil {
+		return due, true
+	}
+	return time.Date(due.Year(), due.Month(), due.Day(), clock.Hour(), clock.Minute(), 0, 0, location), false
+}
+
+// taskToComponent renders a task as a VTODO, or as a VEVENT on its due date
+// when kind is event. VEVENT has no task statuses, so the status travels in
+// X-TASK-STATUS.
+func taskToComponent(task *entity.Task, kind string, location *time.Location) *ical.Component {
+	due, allDay := taskDue(task, location)
+	component := ical.NewComponent("VTODO")
+	if kind == "event" {
+		component.Name = "VEVENT"
+	}
+	component.AddText("UID", taskUid(task))
+	component.AddDate("DTSTAMP", task.UpdatedAt, false)
+	component.AddDate("CREATED", task.CreatedAt, false)
+	component.AddDate("LAST-MODIFIED", task.UpdatedAt, false)
+	component.AddText("SUMMARY", task.Title)
+	if task.Description != "" {
+		component.AddText("DESCRIPTION", task.Description)
+	}
+	component.Add("PRIORITY", strconv.Itoa(icalPriorities[task.Priority]))
+	if component.Name == "VEVENT" {
+		component.AddDate("DTSTART", due, allDay)
+		component.Add("X-TASK-STATUS", task.Status)
+	} else {
+		component.Add("STATUS", icalStatuses[task.Status])
+		if task.StartDate != nil {
+			// DTSTART has to use the same value type as DUE
+			start := *task.StartDate
+			if !allDay {
+				start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, location)
+			}
+			component.AddDate("DTSTART", start, allDay)
+		}
+		component.AddDate("DUE", due, allDay)
+	}
+	if len(task.Tags) > 0 {
+		names := make([]string, len(task.Tags))
+		for i, tag := range task.Tags {
+			names[i] = tag.Name
+		}
+		component.AddList("CATEGORIES", names)
+	}
+	if task.Recurrence != nil {
+		component.Add("RRULE", *task.Recurrence)
+	}
+	return component
+}
+
+// componentToTask copies a VTODO or VEVENT onto the task. It returns the
+// reason when the item cannot be imported.
+func componentToTask(task *entity.Task, component *ical.Component, location *time.Location) string {
+	title := strings.TrimSpace(component.Text("SUMMARY"))
+	if title == "" {
+		return "missing SUMMARY"
+	}
+	task.Title = truncate(title, 150)
+	task.Description = component.Text("DESCRIPTION")
+	task.Priority = importPriority(component.Get("PRIORITY"))
+	task.Status = importStatus(component)
+
+	dueName, startName := "DUE", "DTSTART"
+	if component.Name == "VEVENT" {
+		dueName, startName = "DTSTART", ""
+	}
+	task.DueDate, task.DueTime, task.StartDate = nil, nil, nil
+	if property := component.Get(dueName); property != nil {
+		due, allDay, err := property.Date(location)
+		if err != nil {
+			return "invalid " + dueName
+		}
+		if !allDay {
+			due = due.In(location)
+			clock := due.Format("15:04")
+			task.DueTime = &clock
+		}
+		task.DueDate = helper.CalendarDate(&due)
+	}
+	if property := component.Get(startName); startName != "" && property != nil {
+		start, allDay, err := property.Date(location)
+		if err != nil {
+			return "invalid " + startName
+		}
+		if !allDay {
+			start = start.In(location)
+		}
+		task.StartDate = helper.CalendarDate(&start)
+	}
+	if checkSchedule(task.StartDate, task.DueDate) != nil {
+		return "DTSTART is after DUE"
+	}
+
+	task.Recurrence = nil
+	if rule := component.Get("RRULE"); rule != nil {
+		if len(rule.Value) > 500 {
+			return "RRULE is too long"
+		}
+		task.Recurrence = &rule.Value
+	}
+	return ""
+}
+
+func importStatus(component *ical.Component) string {
+	if status := component.Text("X-TASK-STATUS"); icalStatuses[status] != "" {
+		return status
+	}
+	switch strings.ToUpper(component.Text("STATUS")) {
+	case "IN-PROCESS":
+		return "in_progress"
+	case "COMPLETED", "CANCELLED":
+		return "completed"
+	}
+	if component.Get("COMPLETED") != nil {
+		return "completed"
+	}
+	return "pending"
+}
+
+func importPriority(property *ical.Property) string {
+	if property == nil {
+		return "medium"
+	}
+	priority, _ := strconv.Atoi(strings.TrimSpace(property.Value))
+	switch {
+	case priority >= 1 && priority <= 2:
+		return "urgent"
+	case priority >= 3 && priority <= 4:
+		return "high"
+	case priority >= 6 && priority <= 9:
+		return "low"
+	}
+	return "medium"
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/ical"
+)
+
+func date(year int, month time.Month, day int) *time.Time {
+	value := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &value
+}
+
+func text(value string) *string {
+	return &value
+}
+
+// TestICalRoundTrip exports tasks the way the feed does, decodes the
+// bytes and imports them again, which must give back the same tasks.
+func TestICalRoundTrip(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	updatedAt := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		kind string
+		task entity.Task
+		// due time as imported, without seconds
+		dueTime string
+	}{
+		{name: "all day todo", kind: "todo", task: entity.Task{
+			ID: 1, Title: "Pay rent, water; gas", Description: "Transfer to\nlandlord\\account",
+			Status: "in_progress", Priority: "high",
+			DueDate: date(2026, 11, 1), StartDate: date(2026, 10, 25), Recurrence: text("FREQ=MONTHLY;BYMONTHDAY=1"),
+			Tags: []entity.Tag{{Name: "home"}, {Name: "bills, monthly"}},
+		}},
+		{name: "timed todo", kind: "todo", task: entity.Task{
+			ID: 2, Uid: text("abc@example.com"), Title: "Call the bank", Status: "pending", Priority: "medium",
+			DueDate: date(2026, 10, 20), DueTime: text("17:30:00"), StartDate: date(2026, 10, 20),
+		}, dueTime: "17:30"},
+		{name: "timed todo crossing midnight in utc", kind: "todo", task: entity.Task{
+			ID: 3, Title: "Early standup", Status: "completed", Priority: "urgent",
+			DueDate: date(2026, 10, 20), DueTime: text("06:00"),
+		}, dueTime: "06:00"},
+		{name: "all day event", kind: "event", task: entity.Task{
+			ID: 4, Title: "Conference 日本語", Status: "pending", Priority: "low", DueDate: date(2026, 12, 24),
+		}},
+		{name: "timed event keeps its status", kind: "event", task: entity.Task{
+			ID: 5, Title: "Demo", Status: "in_progress", Priority: "urgent",
+			DueDate: date(2026, 10, 21), DueTime: text("09:15"),
+		}, dueTime: "09:15"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			want := test.task
+			want.CreatedAt, want.UpdatedAt = updatedAt, updatedAt
+			calendar := ical.NewComponent("VCALENDAR")
+			calendar.Components = append(calendar.Components, taskToComponent(&want, test.kind, jakarta))
+			var buffer bytes.Buffer
+			if err := ical.Encode(&buffer, calendar); err != nil {
+				t.Fatal(err)
+			}
+
+			decoded, err := ical.Decode(&buffer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			component := decoded.Components[0]
+			got := new(entity.Task)
+			if reason := componentToTask(got, component, jakarta); reason != "" {
+				t.Fatalf("import skipped the task: %s", reason)
+			}
+
+			if uid := component.Text("UID"); uid != taskUid(&want) {
+				t.Errorf("UID = %q, want %q", uid, taskUid(&want))
+			}
+			if got.Title != want.Title || got.Description != want.Description {
+				t.Errorf("text = %q, %q, want %q, %q", got.Title, got.Description, want.Title, want.Description)
+			}
+			if got.Status != want.Status || got.Priority != want.Priority {
+				t.Errorf("status and priority = %s, %s, want %s, %s", got.Status, got.Priority, want.Status, want.Priority)
+			}
+			if !sameDate(got.DueDate, want.DueDate) {
+				t.Errorf("due date = %v, want %v", got.DueDate, want.DueDate)
+			}
+			if dueTime := deref(got.DueTime); dueTime != test.dueTime {
+				t.Errorf("due time = %q, want %q", dueTime, test.dueTime)
+			}
+			// VEVENT carries the due date in DTSTART, so the start is lost
+			wantStart := want.StartDate
+			if test.kind == "event" {
+				wantStart = nil
+			}
+			if !sameDate(got.StartDate, wantStart) {
+				t.Errorf("start date = %v, want %v", got.StartDate, wantStart)
+			}
+			if deref(got.Recurrence) != deref(want.Recurrence) {
+				t.Errorf("recurrence = %q, want %q", deref(got.Recurrence), deref(want.Recurrence))
+			}
+			var tags []string
+			for _, tag := range want.Tags {
+				tags = append(tags, tag.Name)
+			}
+			if categories := component.List("CATEGORIES"); !slices.Equal(categories, tags) {
+				t.Errorf("categories = %q, want %q", categories, tags)
+			}
+		})
+	}
+}
+
+func TestICalImportSkips(t *testing.T) {
+	tests := []struct {
+		name   string
+		lines  string
+		reason string
+	}{
+		{name: "missing summary", lines: "SUMMARY: \r\n", reason: "missing SUMMARY"},
+		{name: "invalid due", lines: "SUMMARY:x\r\nDUE:2026-11-01\r\n", reason: "invalid DUE"},
+		{name: "invalid start", lines: "SUMMARY:x\r\nDTSTART:soon\r\n", reason: "invalid DTSTART"},
+		{name: "start after due", lines: "SUMMARY:x\r\nDTSTART;VALUE=DATE:20261102\r\nDUE;VALUE=DATE:20261101\r\n", reason: "DTSTART is after DUE"},
+		{name: "long rule", lines: "SUMMARY:x\r\nRRULE:FREQ=DAILY;" + strings.Repeat("X", 500) + "\r\n", reason: "RRULE is too long"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\n" + test.lines + "END:VTODO\r\nEND:VCALENDAR\r\n"
+			calendar, err := ical.Decode(strings.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if reason := componentToTask(new(entity.Task), calendar.Components[0], time.UTC); reason != test.reason {
+				t.Errorf("reason = %q, want %q", reason, test.reason)
+			}
+		})
+	}
+}
+
+func deref(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
+	"github.com/abdisetiakawan/go-clean-arch/internal/importer"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model/converter"
+	"github.com/abdisetiakawan/go-clean-arch/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// importJobChunkSize is the number of items committed between progress
+// updates of an import job.
+const importJobChunkSize = 100
+
+// importJobListSize is the number of recent jobs listed.
+const importJobListSize = 20
+
+const (
+	// importJobLease is how long a job stays with its worker after the
+	// worker last saved progress. A chunk takes far less.
+	importJobLease = 5 * time.Minute
+	// importJobPollInterval is the wait between looks for jobs to resume.
+	importJobPollInterval = 30 * time.Second
+)
+
+// ImportJobUseCase imports exports of other task tools in the background.
+// The rows are written by the TransferUseCase importer.
+type ImportJobUseCase struct {
+	DB                      *gorm.DB
+	Log                     *logrus.Logger
+	Validate                *validator.Validate
+	ImportJobRepository     *repository.ImportJobRepository
+	ProjectMemberRepository *repository.ProjectMemberRepository
+	Transfer                *TransferUseCase
+}
+
+func NewImportJobUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, importJobRepository *repository.ImportJobRepository, projectMemberRepository *repository.ProjectMemberRepository, transfer *TransferUseCase) *ImportJobUseCase {
+	return &ImportJobUseCase{
+		DB:                      db,
+		Log:                     log,
+		Validate:                validate,
+		ImportJobRepository:     importJobRepository,
+		ProjectMemberRepository: projectMemberRepository,
+		Transfer:                transfer,
+	}
+}
+
+// Start reads the file and queues a job importing it. A file that cannot
+// be read is rejected right away; problems with single items end up in
+// the report of the job. The rows are saved with the job, so a job cut off
+// by a restart is resumed by Run.
+func (c *ImportJobUseCase) Start(ctx context.Context, request *model.StartImportRequest) (*model.ImportJobResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	projectId, err := c.ProjectMemberRepository.ResolveWritable(tx, request.ProjectId, request.Email)
+	if err != nil {
+		c.Log.WithError(err).Error("error resolve project")
+		return nil, model.ErrForbidden
+	}
+	var rows []taskRow
+	switch request.Source {
+	case "todoist":
+		rows, err = importer.Todoist(request.Data)
+	case "trello":
+		rows, err = importer.Trello(request.Data, helper.Location(request.Timezone))
+	case "todotxt":
+		rows, err = importer.Todotxt(request.Data)
+	}
+	if err != nil {
+		c.Log.WithError(err).Error("error read import file")
+		return nil, model.NewApiError(model.ErrBadRequest.StatusCode, err.Error())
+	}
+
+	payload, err := json.Marshal(rows)
+	if err != nil {
+		c.Log.WithError(err).Error("error encode import rows")
+		return nil, model.ErrInternalServer
+	}
+	leaseUntil := time.Now().Add(importJobLease)
+	job := &entity.ImportJob{
+		Email:      request.Email,
+		ProjectId:  projectId,
+		Source:     request.Source,
+		Status:     "queued",
+		Total:      len(rows),
+		Payload:    string(payload),
+		CreateTags: request.CreateTags,
+		LeaseUntil: &leaseUntil,
+	}
+	if err := c.ImportJobRepository.Create(tx, job); err != nil {
+		c.Log.WithError(err).Error("error create import job")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error create import job")
+		return nil, model.ErrInternalServer
+	}
+
+	response := converter.ImportJobToResponse(job)
+	go c.run(job)
+	return response, nil
+}
+
+// Run resumes jobs whose worker stopped, such as the jobs running when the
+// service was restarted, until ctx is done. Jobs are claimed one at a time
+// so none waits behind another with its lease running out.
+func (c *ImportJobUseCase) Run(ctx context.Context) {
+	ticker := time.NewTicker(importJobPollInterval)
+	defer ticker.Stop()
+	for {
+		for {
+			jobs, err := c.ImportJobRepository.Claim(c.DB.WithContext(ctx), time.Now(), importJobLease, 1)
+			if err != nil {
+				c.Log.WithError(err).Error("error claim import jobs")
+			}
+			if len(jobs) == 0 {
+				break
+			}
+			c.Log.Infof("resuming import job %d at item %d", jobs[0].ID, jobs[0].Processed+1)
+			c.run(&jobs[0])
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run imports the rows of the job in chunks, from the first one not
+// processed yet. The progress of a chunk is saved in the transaction of
+// the chunk, so a resumed job neither skips nor repeats items. A chunk
+// that fails is rolled back and ends the job. A job taken over by another
+// worker is left to it, and one that cannot be started is resumed once its
+// lease runs out.
+func (c *ImportJobUseCase) run(job *entity.ImportJob) {
+	ctx := context.Background()
+	db := c.DB.WithContext(ctx)
+	left := false
+	defer func() {
+		if r := recover(); r != nil {
+			c.Log.Errorf("import job %d panicked: %v", job.ID, r)
+			job.Status = "failed"
+			job.Error = "internal error"
+		}
+		if left {
+			return
+		}
+		if job.Status == "running" {
+			job.Status = "completed"
+		}
+		now := time.Now()
+		job.Payload = ""
+		job.LeaseUntil = nil
+		job.FinishedAt = &now
+		if err := c.ImportJobRepository.UpdateRun(db, job); err != nil {
+			c.Log.WithError(err).Error("error finish import job")
+		}
+	}()
+
+	var rows []taskRow
+	if err := json.Unmarshal([]byte(job.Payload), &rows); err != nil {
+		c.Log.WithError(err).Error("error decode import rows")
+		job.Status = "failed"
+		job.Error = "internal error"
+		return
+	}
+	skipped := []model.ImportSkipped{}
+	if job.Skipped != "" {
+		json.Unmarshal([]byte(job.Skipped), &skipped)
+	}
+
+	job.Status = "running"
+	if err := c.saveProgress(db, job, skipped); err != nil {
+		c.Log.WithError(err).Error("error start import job")
+		left = true
+		return
+	}
+	writer := c.Transfer.newImporter(job.Email, job.ProjectId, job.CreateTags)
+	for start := job.Processed; start < len(rows); start += importJobChunkSize {
+		chunk := rows[start:min(start+importJobChunkSize, len(rows))]
+		result, err := writer.run(ctx, chunk)
+		if err != nil {
+			c.Log.WithError(err).Error("error import tasks")
+			job.Status = "failed"
+			job.Error = fmt.Sprintf("import stopped at item %d", chunk[0].Item)
+			return
+		}
+
+		progress := *job
+		progress.Processed += len(chunk)
+		progress.Created += len(result.Created)
+		progress.Updated += len(result.Updated)
+		progressSkipped := append(slices.Clip(skipped), result.Skipped...)
+		err = c.saveProgress(writer.tx, &progress, progressSkipped)
+		if err != nil {
+			writer.rollback()
+		} else {
+			err = writer.commit(ctx, result)
+		}
+		if errors.Is(err, repository.ErrStaleVersion) {
+			c.Log.Warnf("import job %d was taken over by another worker", job.ID)
+			left = true
+			return
+		}
+		if err != nil {
+			c.Log.WithError(err).Error("error import tasks")
+			job.Status = "failed"
+			job.Error = fmt.Sprintf("import stopped at item %d", chunk[0].Item)
+			return
+		}
+		*job, skipped = progress, progressSkipped
+	}
+}
+
+// saveProgress saves the counts and skipped items of the job and renews
+// its lease.
+func (c *ImportJobUseCase) saveProgress(db *gorm.DB, job *entity.ImportJob, skipped []model.ImportSkipped) error {
+	data, _ := json.Marshal(skipped)
+	job.Skipped = string(data)
+	leaseUntil := time.Now().Add(importJobLease)
+	job.LeaseUntil = &leaseUntil
+	return c.ImportJobRepository.UpdateRun(db, job)
+}
+
+func (c *ImportJobUseCase) Get(ctx context.Context, request *model.GetImportJobRequest) (*model.ImportJobResponse, error) {
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return nil, model.ErrBadRequest
+	}
+	job := new(entity.ImportJob)
+	if err := c.ImportJobRepository.FindByEmailAndId(c.DB.WithContext(ctx), job, request.ID, request.Email); err != nil {
+		c.Log.WithError(err).Error("error search import job")
+		return nil, model.ErrNotFound
+	}
+	return converter.ImportJobToResponse(job), nil
+}
+
+// List returns the most recent import jobs of the user.
+func (c *ImportJobUseCase) List(ctx context.Context, email string) ([]model.ImportJobResponse, error) {
+	jobs, err := c.ImportJobRepository.FindRecent(c.DB.WithContext(ctx), email, importJobListSize)
+	if err != nil {
+		c.Log.WithError(err).Error("error search import jobs")
+		return nil, model.ErrInternalServer
+	}
+	responses := make([]model.ImportJobResponse, len(jobs))
+	for i := range jobs {
+		responses[i] = *converter.ImportJobToResponse(&jobs[i])
+	}
+	return responses, nil
+}
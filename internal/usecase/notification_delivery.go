@@ -0,0 +1,321 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model/converter"
+	"github.com/abdisetiakawan/go-clean-arch/internal/webhook"
+)
+
+// notificationPolicy gives up the email or webhook of a notification after
+// 8 tries.
+var notificationPolicy = webhook.Policy{MaxAttempts: 8}
+
+// errNoWebhook fails the webhook of a notification whose user has none,
+// which no retry helps.
+var errNoWebhook = errors.New("no webhook")
+
+const (
+	// notificationPollInterval is the wait between rounds of reminders,
+	// deliveries and digests.
+	notificationPollInterval = time.Minute
+	// notificationBatchSize is the number of notifications sent per round
+	// trip to the database.
+	notificationBatchSize = 50
+	notificationLease     = 5 * time.Minute
+	// reminderLead is how long before a task with a due time it is
+	// reminded of.
+	reminderLead = time.Hour
+	// reminderHour is the hour of the due date a task without a due time
+	// is reminded of.
+	reminderHour = 9
+)
+
+// Run sends due-soon reminders, pending emails and webhooks, and daily
+// digests until ctx is done.
+func (c *NotificationUseCase) Run(ctx context.Context) {
+	ticker := time.NewTicker(notificationPollInterval)
+	defer ticker.Stop()
+	for {
+		if err := c.remind(ctx); err != nil {
+			c.Log.WithError(err).Error("error send task reminders")
+		}
+		for {
+			sent, err := c.DeliverDue(ctx)
+			if err != nil {
+				c.Log.WithError(err).Error("error send notifications")
+			}
+			if err != nil || sent < notificationBatchSize {
+				break
+			}
+		}
+		if err := c.sendDigests(ctx); err != nil {
+			c.Log.WithError(err).Error("error send notification digests")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// remind notifies the assignees of open tasks, or their owners when
+// nobody is assigned, that the task is due soon. A task is reminded of
+// once per due date and time.
+func (c *NotificationUseCase) remind(ctx context.Context) error {
+	db := c.DB.WithContext(ctx)
+	now := c.Now()
+	tasks, err := c.TaskRepository.FindOpenDueBetween(db, now.AddDate(0, 0, -1), now.AddDate(0, 0, 1))
+	if err != nil || len(tasks) == 0 {
+		return err
+	}
+	taskIds := make([]uint, len(tasks))
+	emails := make([]string, 0, len(tasks))
+	for i, task := range tasks {
+		taskIds[i] = task.ID
+		emails = append(emails, task.Email)
+	}
+	assignees, err := c.TaskAssigneeRepository.FindByTasks(db, taskIds)
+	if err != nil {
+		return err
+	}
+	assigned := map[uint][]string{}
+	for _, assignee := range assignees {
+		assigned[assignee.TaskId] = append(assigned[assignee.TaskId], assignee.Email)
+		emails = append(emails, assignee.Email)
+	}
+	users, err := c.UserRepository.FindByEmails(db, uniqueEmails(emails))
+	if err != nil {
+		return err
+	}
+	locations := make(map[string]*time.Location, len(users))
+	for _, user := range users {
+		locations[user.Email] = helper.Location(user.Timezone)
+	}
+
+	var notifications []model.Notification
+	for i := range tasks {
+		task := &tasks[i]
+		recipients := assigned[task.ID]
+		if len(recipients) == 0 {
+			recipients = []string{task.Email}
+		}
+		for _, email := range recipients {
+			location, ok := locations[email]
+			if !ok {
+				continue
+			}
+			dueAt, from, until := reminderWindow(task, location)
+			if now.Before(from) || !now.Before(until) {
+				continue
+			}
+			notifications = append(notifications, model.Notification{
+				Email: email,
+				Type:  model.NotificationTaskDueSoon,
+				Title: task.Title + " is due soon",
+				Body:  "The task " + task.Title + " is due " + dueAt.In(location).Format("Mon, 02 Jan 2006 15:04 MST"),
+				Data: map[string]any{
+					"task_id":    task.ID,
+					"project_id": task.ProjectId,
+					"due_at":     dueAt,
+				},
+				Key: fmt.Sprintf("%s:%d:%s", model.NotificationTaskDueSoon, task.ID, dueAt.UTC().Format(time.RFC3339)),
+			})
+		}
+	}
+	c.Notify(ctx, notifications...)
+	return nil
+}
+
+// reminderWindow returns when the task is due in the given zone and the
+// window its reminder is sent in: reminderLead before a due time, or from
+// reminderHour of an all day due date until its end.
+func reminderWindow(task *entity.Task, location *time.Location) (time.Time, time.Time, time.Time) {
+	due, allDay := taskDue(task, location)
+	if !allDay {
+		return due, due.Add(-reminderLead), due
+	}
+	day := time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, location)
+	return day, day.Add(reminderHour * time.Hour), day.AddDate(0, 0, 1)
+}
+
+// DeliverDue sends the emails and webhooks of one batch of due
+// notifications and returns its size.
+func (c *NotificationUseCase) DeliverDue(ctx context.Context) (int, error) {
+	db := c.DB.WithContext(ctx)
+	now := c.Now()
+	notifications, err := c.NotificationRepository.Claim(db, now, notificationLease, notificationBatchSize)
+	if err != nil || len(notifications) == 0 {
+		return 0, err
+	}
+	emails := make([]string, len(notifications))
+	for i, notification := range notifications {
+		emails[i] = notification.Email
+	}
+	settings, err := c.NotificationSettingRepository.FindByEmails(db, uniqueEmails(emails))
+	if err != nil {
+		return 0, err
+	}
+	byEmail := make(map[string]*entity.NotificationSetting, len(settings))
+	for i := range settings {
+		byEmail[settings[i].Email] = &settings[i]
+	}
+
+	for i := range notifications {
+		notification := &notifications[i]
+		notification.DeliverAfter = nil
+		if notification.EmailStatus == "pending" {
+			err := c.Mailer.Send(ctx, model.Mail{To: notification.Email, Subject: notification.Title, Body: notification.Body})
+			if err != nil {
+				c.Log.WithError(err).WithField("notification", notification.ID).Warn("error email notification")
+			}
+			notification.EmailStatus = deliveryStatus(notification, &notification.EmailAttempts, err, now)
+		}
+		if notification.WebhookStatus == "pending" {
+			err := c.post(ctx, notification, byEmail[notification.Email])
+			if err != nil {
+				c.Log.WithError(err).WithField("notification", notification.ID).Warn("error post notification")
+			}
+			notification.WebhookStatus = deliveryStatus(notification, &notification.WebhookAttempts, err, now)
+		}
+		if err := c.NotificationRepository.SaveDelivery(db, notification); err != nil {
+			c.Log.WithError(err).WithField("notification", notification.ID).Error("error record notification delivery")
+		}
+	}
+	return len(notifications), nil
+}
+
+// deliveryStatus counts a try of the email or webhook of the notification
+// and returns the status of the channel. A failed try is made again with a
+// growing wait as long as notificationPolicy allows; the notification is
+// due again at the earliest retry of its channels.
+func deliveryStatus(notification *entity.Notification, attempts *int, err error, now time.Time) string {
+	*attempts++
+	if err == nil {
+		return "sent"
+	}
+	next, ok := notificationPolicy.Retry(*attempts, now)
+	if !ok || errors.Is(err, errNoWebhook) {
+		return "failed"
+	}
+	if notification.DeliverAfter == nil || next.Before(*notification.DeliverAfter) {
+		notification.DeliverAfter = &next
+	}
+	return "pending"
+}
+
+// post sends the notification to the webhook of its user, signed with the
+// secret of the webhook.
+func (c *NotificationUseCase) post(ctx context.Context, notification *entity.Notification, setting *entity.NotificationSetting) error {
+	if setting == nil || setting.WebhookURL == nil || setting.WebhookSecret == nil {
+		return fmt.Errorf("%w for %s", errNoWebhook, notification.Email)
+	}
+	body, _ := json.Marshal(converter.NotificationToResponse(notification))
+	response, err := c.Client.Send(ctx, &webhook.Request{
+		URL:      *setting.WebhookURL,
+		Secret:   *setting.WebhookSecret,
+		Event:    notification.Type,
+		Delivery: strconv.Itoa(int(notification.ID)),
+		Body:     body,
+	})
+	if err != nil {
+		return err
+	}
+	if !response.OK() {
+		return fmt.Errorf("webhook answered %d", response.StatusCode)
+	}
+	return nil
+}
+
+// sendDigests sends the users with a digest whose digest time passed since
+// their last one the notifications collected since then in one email.
+func (c *NotificationUseCase) sendDigests(ctx context.Context) error {
+	db := c.DB.WithContext(ctx)
+	settings, err := c.NotificationSettingRepository.FindDigests(db)
+	if err != nil || len(settings) == 0 {
+		return err
+	}
+	emails := make([]string, len(settings))
+	for i, setting := range settings {
+		emails[i] = setting.Email
+	}
+	users, err := c.UserRepository.FindByEmails(db, emails)
+	if err != nil {
+		return err
+	}
+	locations := make(map[string]*time.Location, len(users))
+	for _, user := range users {
+		locations[user.Email] = helper.Location(user.Timezone)
+	}
+
+	now := c.Now()
+	for _, setting := range settings {
+		location, ok := locations[setting.Email]
+		if !ok {
+			continue
+		}
+		due := lastDigest(setting.DigestTime, location, now)
+		if setting.LastDigestAt != nil && !setting.LastDigestAt.Before(due) {
+			continue
+		}
+		if err := c.sendDigest(ctx, setting.Email, due, now); err != nil {
+			c.Log.WithError(err).WithField("email", setting.Email).Error("error send notification digest")
+		}
+	}
+	return nil
+}
+
+// lastDigest returns the last time the digest time passed in the given
+// zone.
+func lastDigest(digestTime string, location *time.Location, now time.Time) time.Time {
+	local := now.In(location)
+	minute := clock(digestTime)
+	due := time.Date(local.Year(), local.Month(), local.Day(), minute/60, minute%60, 0, 0, location)
+	if due.After(local) {
+		due = due.AddDate(0, 0, -1)
+	}
+	return due
+}
+
+// sendDigest claims the digest of the user due at due and emails it. The
+// notifications stay waiting for the next digest when the email fails.
+func (c *NotificationUseCase) sendDigest(ctx context.Context, email string, due time.Time, now time.Time) error {
+	db := c.DB.WithContext(ctx)
+	claimed, err := c.NotificationSettingRepository.ClaimDigest(db, email, due, now)
+	if err != nil || !claimed {
+		return err
+	}
+	notifications, err := c.NotificationRepository.FindDigest(db, email)
+	if err != nil || len(notifications) == 0 {
+		return err
+	}
+
+	var body strings.Builder
+	ids := make([]uint, len(notifications))
+	for i, notification := range notifications {
+		ids[i] = notification.ID
+		fmt.Fprintf(&body, "- %s\n", notification.Title)
+		if notification.Body != "" {
+			fmt.Fprintf(&body, "  %s\n", strings.ReplaceAll(notification.Body, "\n", "\n  "))
+		}
+	}
+	mail := model.Mail{
+		To:      email,
+		Subject: fmt.Sprintf("Your daily digest: %d notifications", len(notifications)),
+		Body:    body.String(),
+	}
+	if err := c.Mailer.Send(ctx, mail); err != nil {
+		return err
+	}
+	return c.NotificationRepository.MarkEmailed(db, ids, "sent")
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/webhook"
+)
+
+func TestDeliveryStatus(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	notification := new(entity.Notification)
+
+	if status := deliveryStatus(notification, &notification.EmailAttempts, nil, now); status != "sent" || notification.DeliverAfter != nil {
+		t.Errorf("sent email = %s, due %v, want sent and not due", status, notification.DeliverAfter)
+	}
+
+	// the webhook fails until its attempts run out
+	failure := errors.New("webhook answered 503")
+	for attempt := 1; attempt <= notificationPolicy.MaxAttempts; attempt++ {
+		notification.DeliverAfter = nil
+		status := deliveryStatus(notification, &notification.WebhookAttempts, failure, now)
+		if attempt == notificationPolicy.MaxAttempts {
+			if status != "failed" || notification.DeliverAfter != nil {
+				t.Errorf("try %d = %s, due %v, want failed and not due", attempt, status, notification.DeliverAfter)
+			}
+			break
+		}
+		if want := now.Add(webhook.Backoff(attempt)); status != "pending" || notification.DeliverAfter == nil || !notification.DeliverAfter.Equal(want) {
+			t.Errorf("try %d = %s, due %v, want pending and due at %v", attempt, status, notification.DeliverAfter, want)
+		}
+	}
+	if notification.EmailAttempts != 1 || notification.WebhookAttempts != notificationPolicy.MaxAttempts {
+		t.Errorf("attempts = %d, %d, want 1, %d", notification.EmailAttempts, notification.WebhookAttempts, notificationPolicy.MaxAttempts)
+	}
+}
+
+func TestDeliveryStatusEarliestRetry(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	notification := &entity.Notification{EmailAttempts: 3}
+	failure := errors.New("smtp unavailable")
+
+	deliveryStatus(notification, &notification.EmailAttempts, failure, now)
+	deliveryStatus(notification, &notification.WebhookAttempts, failure, now)
+	if want := now.Add(webhook.Backoff(1)); !notification.DeliverAfter.Equal(want) {
+		t.Errorf("due at %v, want the webhook retry at %v", notification.DeliverAfter, want)
+	}
+
+	notification = new(entity.Notification)
+	noWebhook := fmt.Errorf("%w for jane@example.com", errNoWebhook)
+	if status := deliveryStatus(notification, &notification.WebhookAttempts, noWebhook, now); status != "failed" || notification.DeliverAfter != nil {
+		t.Errorf("missing webhook = %s, due %v, want failed and not due", status, notification.DeliverAfter)
+	}
+}
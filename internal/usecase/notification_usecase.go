@@ -0,0 +1,410 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model/converter"
+	"github.com/abdisetiakawan/go-clean-arch/internal/repository"
+	"github.com/abdisetiakawan/go-clean-arch/internal/webhook"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// NotificationUseCase is the notification center. It is the Notifier of
+// the other use cases: notifications are stored for the recipient and
+// sent by email or webhook according to their preferences, see
+// notification_delivery.go.
+type NotificationUseCase struct {
+	DB                               *gorm.DB
+	Log                              *logrus.Logger
+	Validate                         *validator.Validate
+	NotificationRepository           *repository.NotificationRepository
+	NotificationSettingRepository    *repository.NotificationSettingRepository
+	NotificationPreferenceRepository *repository.NotificationPreferenceRepository
+	UserRepository                   *repository.UserRepository
+	TaskRepository                   *repository.TaskRepository
+	TaskAssigneeRepository           *repository.TaskAssigneeRepository
+	Mailer                           helper.Mailer
+	Client                           *webhook.Client
+	// Now is the clock notifications are scheduled with.
+	Now func() time.Time
+}
+
+func NewNotificationUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, notificationRepository *repository.NotificationRepository, notificationSettingRepository *repository.NotificationSettingRepository, notificationPreferenceRepository *repository.NotificationPreferenceRepository, userRepository *repository.UserRepository, taskRepository *repository.TaskRepository, taskAssigneeRepository *repository.TaskAssigneeRepository, mailer helper.Mailer) *NotificationUseCase {
+	return &NotificationUseCase{
+		DB:                               db,
+		Log:                              log,
+		Validate:                         validate,
+		NotificationRepository:           notificationRepository,
+		NotificationSettingRepository:    notificationSettingRepository,
+		NotificationPreferenceRepository: notificationPreferenceRepository,
+		UserRepository:                   userRepository,
+		TaskRepository:                   taskRepository,
+		TaskAssigneeRepository:           taskAssigneeRepository,
+		Mailer:                           mailer,
+		Client:                           webhook.NewClient(webhookTimeout),
+		Now:                              time.Now,
+	}
+}
+
+// recipient is how a user receives notifications.
+type recipient struct {
+	location    *time.Location
+	setting     entity.NotificationSetting
+	preferences map[string]model.NotificationChannels
+}
+
+func (r *recipient) channels(notificationType string) model.NotificationChannels {
+	if channels, ok := r.preferences[notificationType]; ok {
+		return channels
+	}
+	return model.DefaultNotificationChannels
+}
+
+func defaultNotificationSetting(email string) entity.NotificationSetting {
+	return entity.NotificationSetting{Email: email, DigestTime: "08:00"}
+}
+
+// loadRecipients loads the users among emails with their settings. Emails
+// of people without an account are left out.
+func (c *NotificationUseCase) loadRecipients(db *gorm.DB, emails []string) (map[string]*recipient, error) {
+	users, err := c.UserRepository.FindByEmails(db, emails)
+	if err != nil {
+		return nil, err
+	}
+	recipients := make(map[string]*recipient, len(users))
+	for _, user := range users {
+		recipients[user.Email] = &recipient{
+			location:    helper.Location(user.Timezone),
+			setting:     defaultNotificationSetting(user.Email),
+			preferences: map[string]model.NotificationChannels{},
+		}
+	}
+	settings, err := c.NotificationSettingRepository.FindByEmails(db, emails)
+	if err != nil {
+		return nil, err
+	}
+	for _, setting := range settings {
+		if r, ok := recipients[setting.Email]; ok {
+			r.setting = setting
+		}
+	}
+	preferences, err := c.NotificationPreferenceRepository.FindByEmails(db, emails)
+	if err != nil {
+		return nil, err
+	}
+	for _, preference := range preferences {
+		if r, ok := recipients[preference.Email]; ok {
+			r.preferences[preference.Type] = model.NotificationChannels{
+				InApp:   preference.InApp,
+				Email:   preference.ByEmail,
+				Webhook: preference.ByWebhook,
+			}
+		}
+	}
+	return recipients, nil
+}
+
+// Notify stores the notifications on the channels their recipients chose.
+// Emails and webhooks wait for the end of the quiet hours of the
+// recipient, emails of users with a digest wait for the digest.
+func (c *NotificationUseCase) Notify(ctx context.Context, notifications ...model.Notification) {
+	if len(notifications) == 0 {
+		return
+	}
+	db := c.DB.WithContext(ctx)
+	emails := make([]string, len(notifications))
+	for i, notification := range notifications {
+		emails[i] = notification.Email
+	}
+	recipients, err := c.loadRecipients(db, uniqueEmails(emails))
+	if err != nil {
+		c.Log.WithError(err).Warn("error load notification recipients")
+		return
+	}
+
+	now := c.Now()
+	var rows []entity.Notification
+	for _, notification := range notifications {
+		r, ok := recipients[notification.Email]
+		if !ok {
+			continue
+		}
+		channels := r.channels(notification.Type)
+		row := entity.Notification{
+			Email:         notification.Email,
+			Type:          notification.Type,
+			Title:         truncate(notification.Title, 255),
+			Body:          notification.Body,
+			InApp:         channels.InApp,
+			EmailStatus:   "none",
+			WebhookStatus: "none",
+		}
+		if len(notification.Data) > 0 {
+			data, _ := json.Marshal(notification.Data)
+			row.Data = string(data)
+		}
+		if notification.Key != "" {
+			key := notification.Key
+			row.DedupeKey = &key
+		}
+		if channels.Email {
+			row.EmailStatus = "pending"
+			if r.setting.Digest {
+				row.EmailStatus = "digest"
+			}
+		}
+		if channels.Webhook && r.setting.WebhookURL != nil {
+			row.WebhookStatus = "pending"
+		}
+		if !row.InApp && row.EmailStatus == "none" && row.WebhookStatus == "none" {
+			continue
+		}
+		if row.EmailStatus == "pending" || row.WebhookStatus == "pending" {
+			deliverAfter := quietUntil(&r.setting, r.location, now)
+			row.DeliverAfter = &deliverAfter
+		}
+		rows = append(rows, row)
+	}
+	if err := c.NotificationRepository.Insert(db, rows); err != nil {
+		c.Log.WithError(err).Warn("error store notifications")
+	}
+}
+
+// clock reads an HH:MM wall clock time as minutes after midnight.
+func clock(value string) int {
+	t, _ := time.Parse("15:04", value)
+	return t.Hour()*60 + t.Minute()
+}
+
+// quietUntil returns the end of the quiet hours of the user when now falls
+// in them, and now otherwise.
+func quietUntil(setting *entity.NotificationSetting, location *time.Location, now time.Time) time.Time {
+	if setting.QuietStart == nil || setting.QuietEnd == nil {
+		return now
+	}
+	local := now.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	start, end := clock(*setting.QuietStart), clock(*setting.QuietEnd)
+	quiet := start <= minute && minute < end
+	if start > end {
+		quiet = minute >= start || minute < end
+	}
+	if !quiet {
+		return now
+	}
+	until := time.Date(local.Year(), local.Month(), local.Day(), end/60, end%60, 0, 0, location)
+	if !until.After(local) {
+		until = until.AddDate(0, 0, 1)
+	}
+	return until
+}
+
+func (c *NotificationUseCase) Search(ctx context.Context, request *model.SearchNotificationRequest) ([]model.NotificationResponse, *model.PageMetadata, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return nil, nil, model.ErrBadRequest
+	}
+	notifications, paging, err := c.NotificationRepository.Search(tx, request)
+	if err != nil {
+		c.Log.WithError(err).Error("error search notifications")
+		return nil, nil, pageError(err, model.ErrInternalServer)
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error search notifications")
+		return nil, nil, model.ErrInternalServer
+	}
+
+	responses := make([]model.NotificationResponse, len(notifications))
+	for i, notification := range notifications {
+		responses[i] = *converter.NotificationToResponse(&notification)
+	}
+	return responses, paging, nil
+}
+
+func (c *NotificationUseCase) Unread(ctx context.Context, email string) (*model.UnreadNotificationsResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	counts, err := c.NotificationRepository.CountUnread(tx, email)
+	if err != nil {
+		c.Log.WithError(err).Error("error count unread notifications")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error count unread notifications")
+		return nil, model.ErrInternalServer
+	}
+
+	response := &model.UnreadNotificationsResponse{ByType: counts}
+	for _, count := range counts {
+		response.Unread += count
+	}
+	return response, nil
+}
+
+func (c *NotificationUseCase) Read(ctx context.Context, request *model.ReadNotificationRequest) (*model.NotificationResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return nil, model.ErrBadRequest
+	}
+	notification := new(entity.Notification)
+	if err := c.NotificationRepository.FindByEmailAndId(tx, notification, request.ID, request.Email); err != nil {
+		c.Log.WithError(err).Error("error search notification")
+		return nil, model.ErrNotFound
+	}
+	if err := c.NotificationRepository.MarkRead(tx, notification, c.Now()); err != nil {
+		c.Log.WithError(err).Error("error read notification")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error read notification")
+		return nil, model.ErrInternalServer
+	}
+
+	return converter.NotificationToResponse(notification), nil
+}
+
+func (c *NotificationUseCase) ReadAll(ctx context.Context, request *model.ReadAllNotificationsRequest) (*model.ReadAllNotificationsResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	updated, err := c.NotificationRepository.MarkAllRead(tx, request.Email, request.Type, c.Now())
+	if err != nil {
+		c.Log.WithError(err).Error("error read notifications")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error read notifications")
+		return nil, model.ErrInternalServer
+	}
+
+	return &model.ReadAllNotificationsResponse{Updated: updated}, nil
+}
+
+func (c *NotificationUseCase) Settings(ctx context.Context, email string) (*model.NotificationSettingsResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	recipients, err := c.loadRecipients(tx, []string{email})
+	if err != nil {
+		c.Log.WithError(err).Error("error search notification settings")
+		return nil, model.ErrInternalServer
+	}
+	r, ok := recipients[email]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	preferences, err := c.NotificationPreferenceRepository.FindByEmails(tx, []string{email})
+	if err != nil {
+		c.Log.WithError(err).Error("error search notification settings")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error search notification settings")
+		return nil, model.ErrInternalServer
+	}
+
+	return converter.NotificationSettingsToResponse(&r.setting, preferences), nil
+}
+
+// UpdateSettings replaces the notification settings of the user. A secret
+// for signing webhooks is generated with the first webhook URL and only
+// returned then. Emails waiting for a digest that is turned off are sent
+// right away.
+func (c *NotificationUseCase) UpdateSettings(ctx context.Context, request *model.UpdateNotificationSettingsRequest) (*model.NotificationSettingsResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	recipients, err := c.loadRecipients(tx, []string{request.Email})
+	if err != nil {
+		c.Log.WithError(err).Error("error search notification settings")
+		return nil, model.ErrInternalServer
+	}
+	r, ok := recipients[request.Email]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+
+	now := c.Now()
+	setting := &r.setting
+	if setting.CreatedAt.IsZero() {
+		setting.CreatedAt = now
+	}
+	wasDigest := setting.Digest
+	setting.Digest = request.Digest
+	setting.DigestTime = request.DigestTime
+	if setting.DigestTime == "" {
+		setting.DigestTime = "08:00"
+	}
+	setting.QuietStart, setting.QuietEnd = nil, nil
+	if request.QuietHours != nil {
+		setting.QuietStart, setting.QuietEnd = &request.QuietHours.Start, &request.QuietHours.End
+	}
+	var secret string
+	setting.WebhookURL = request.WebhookURL
+	if setting.WebhookURL == nil {
+		setting.WebhookSecret = nil
+	} else if setting.WebhookSecret == nil {
+		if secret, err = webhook.NewSecret(); err != nil {
+			c.Log.WithError(err).Error("error generate webhook secret")
+			return nil, model.ErrInternalServer
+		}
+		setting.WebhookSecret = &secret
+	}
+	if err := c.NotificationSettingRepository.Update(tx, setting); err != nil {
+		c.Log.WithError(err).Error("error update notification settings")
+		return nil, model.ErrInternalServer
+	}
+	if wasDigest && !setting.Digest {
+		if err := c.NotificationRepository.SetEmailStatus(tx, request.Email, "digest", "pending", &now); err != nil {
+			c.Log.WithError(err).Error("error update notification settings")
+			return nil, model.ErrInternalServer
+		}
+	}
+
+	preferences := make([]entity.NotificationPreference, 0, len(request.Preferences))
+	for _, notificationType := range model.NotificationTypes {
+		if channels, ok := request.Preferences[notificationType]; ok {
+			preferences = append(preferences, entity.NotificationPreference{
+				Email:     request.Email,
+				Type:      notificationType,
+				InApp:     channels.InApp,
+				ByEmail:   channels.Email,
+				ByWebhook: channels.Webhook,
+			})
+		}
+	}
+	if err := c.NotificationPreferenceRepository.Replace(tx, request.Email, preferences); err != nil {
+		c.Log.WithError(err).Error("error update notification settings")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error update notification settings")
+		return nil, model.ErrInternalServer
+	}
+
+	response := converter.NotificationSettingsToResponse(setting, preferences)
+	response.WebhookSecret = secret
+	return response, nil
+}
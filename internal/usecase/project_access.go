@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/repository"
+	"gorm.io/gorm"
+)
+
+// checkWriteAccess makes sure the email may change content of the project.
+// Callers are expected to have already verified that the email can read it.
+func checkWriteAccess(tx *gorm.DB, members *repository.ProjectMemberRepository, projectId uint, email string) error {
+	ok, err := members.HasRole(tx, projectId, email, model.ProjectWriteRoles...)
+	if err != nil {
+		return model.ErrInternalServer
+	}
+	if !ok {
+		return model.ErrForbidden
+	}
+	return nil
+}
+
+// checkStatusAccess lets assignees without write access change the status
+// of their task.
+func checkStatusAccess(tx *gorm.DB, members *repository.ProjectMemberRepository, assignees *repository.TaskAssigneeRepository, task *entity.Task, email string) error {
+	err := checkWriteAccess(tx, members, task.ProjectId, email)
+	if err != model.ErrForbidden {
+		return err
+	}
+	assigned, assigneeErr := assignees.IsAssignee(tx, task.ID, email)
+	if assigneeErr != nil {
+		return model.ErrInternalServer
+	}
+	if !assigned {
+		return err
+	}
+	return nil
+}
+
+// memberCacheKeys expands a cache key prefix for every member of a project,
+// since cached tasks and tags are stored per reader.
+func memberCacheKeys(emails []string, prefixes ...string) []string {
+	keys := make([]string, 0, len(emails)*len(prefixes))
+	for _, prefix := range prefixes {
+		for _, email := range emails {
+			keys = append(keys, prefix+"email:"+email)
+		}
+	}
+	return keys
+}
+
+// forgetProjectCache drops the keys under the prefixes for every member of
+// the projects with a single delete.
+func forgetProjectCache(ctx context.Context, db *gorm.DB, members *repository.ProjectMemberRepository, cache *helper.CacheHelper, projectIds []uint, prefixes ...string) error {
+	if len(projectIds) == 0 || len(prefixes) == 0 {
+		return nil
+	}
+	emails, err := members.EmailsByProjects(db.WithContext(ctx), projectIds)
+	if err != nil {
+		return err
+	}
+	return cache.Delete(ctx, memberCacheKeys(emails, prefixes...)...)
+}
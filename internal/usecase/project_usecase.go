@@ -0,0 +1,460 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model/converter"
+	"github.com/abdisetiakawan/go-clean-arch/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ProjectUseCase struct {
+	DB                          *gorm.DB
+	Log                         *logrus.Logger
+	Validate                    *validator.Validate
+	ProjectRepository           *repository.ProjectRepository
+	ProjectMemberRepository     *repository.ProjectMemberRepository
+	ProjectInvitationRepository *repository.ProjectInvitationRepository
+	Cache                       *helper.CacheHelper
+	Notifier                    helper.Notifier
+}
+
+func NewProjectUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, projectRepository *repository.ProjectRepository, projectMemberRepository *repository.ProjectMemberRepository, projectInvitationRepository *repository.ProjectInvitationRepository, cache *helper.CacheHelper, notifier helper.Notifier) *ProjectUseCase {
+	return &ProjectUseCase{
+		DB:                          db,
+		Log:                         log,
+		Validate:                    validate,
+		ProjectRepository:           projectRepository,
+		ProjectMemberRepository:     projectMemberRepository,
+		ProjectInvitationRepository: projectInvitationRepository,
+		Cache:                       cache,
+		Notifier:                    notifier,
+	}
+}
+
+func (c *ProjectUseCase) Create(ctx context.Context, request *model.CreateProjectRequest) (*model.ProjectResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	project := &entity.Project{
+		OwnerEmail: request.Email,
+		Name:       request.Name,
+	}
+	if err := c.ProjectRepository.Create(tx, project); err != nil {
+		c.Log.WithError(err).Error("error create project")
+		return nil, model.ErrInternalServer
+	}
+	member := &entity.ProjectMember{
+		ProjectId: project.ID,
+		Email:     request.Email,
+		Role:      model.ProjectRoleOwner,
+	}
+	if err := c.ProjectMemberRepository.Create(tx, member); err != nil {
+		c.Log.WithError(err).Error("error create project owner")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error create project")
+		return nil, model.ErrInternalServer
+	}
+
+	return converter.ProjectToResponse(project, member.Role), nil
+}
+
+func (c *ProjectUseCase) Search(ctx context.Context, request *model.SearchProjectRequest) ([]model.ProjectResponse, *model.PageMetadata, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return nil, nil, model.ErrBadRequest
+	}
+	members, paging, err := c.ProjectMemberRepository.SearchByEmail(tx, request)
+	if err != nil {
+		c.Log.WithError(err).Error("error search project")
+		return nil, nil, pageError(err, model.ErrInternalServer)
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error search project")
+		return nil, nil, model.ErrInternalServer
+	}
+
+	responses := make([]model.ProjectResponse, len(members))
+	for i, member := range members {
+		responses[i] = *converter.ProjectToResponse(&member.Project, member.Role)
+	}
+	return responses, paging, nil
+}
+
+func (c *ProjectUseCase) Get(ctx context.Context, request *model.GetProjectRequest) (*model.ProjectResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return nil, model.ErrBadRequest
+	}
+	project, member, err := c.find(tx, request.ID, request.Email)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error get project")
+		return nil, model.ErrInternalServer
+	}
+
+	return converter.ProjectToResponse(project, member.Role), nil
+}
+
+func (c *ProjectUseCase) Update(ctx context.Context, request *model.UpdateProjectRequest) (*model.ProjectResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	project, member, err := c.findOwned(tx, request.ID, request.Email)
+	if err != nil {
+		return nil, err
+	}
+	project.Name = request.Name
+	if err := c.ProjectRepository.Update(tx, project); err != nil {
+		c.Log.WithError(err).Error("error update project")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error update project")
+		return nil, model.ErrInternalServer
+	}
+
+	return converter.ProjectToResponse(project, member.Role), nil
+}
+
+func (c *ProjectUseCase) Delete(ctx context.Context, request *model.GetProjectRequest) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return model.ErrBadRequest
+	}
+	project, _, err := c.findOwned(tx, request.ID, request.Email)
+	if err != nil {
+		return err
+	}
+	if project.IsPersonal {
+		return model.ErrPersonalProject
+	}
+	emails, err := c.ProjectMemberRepository.Emails(tx, project.ID)
+	if err != nil {
+		c.Log.WithError(err).Error("error load project members")
+		return model.ErrInternalServer
+	}
+	if err := c.ProjectRepository.Delete(tx, project); err != nil {
+		c.Log.WithError(err).Error("error delete project")
+		return model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error delete project")
+		return model.ErrInternalServer
+	}
+
+	for _, email := range emails {
+		c.forgetMember(ctx, email)
+	}
+	return nil
+}
+
+func (c *ProjectUseCase) Members(ctx context.Context, request *model.GetProjectRequest) ([]model.ProjectMemberResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return nil, model.ErrBadRequest
+	}
+	project, _, err := c.find(tx, request.ID, request.Email)
+	if err != nil {
+		return nil, err
+	}
+	members, err := c.ProjectMemberRepository.FindByProject(tx, project.ID)
+	if err != nil {
+		c.Log.WithError(err).Error("error list project members")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error list project members")
+		return nil, model.ErrInternalServer
+	}
+
+	responses := make([]model.ProjectMemberResponse, len(members))
+	for i, member := range members {
+		responses[i] = *converter.ProjectMemberToResponse(&member)
+	}
+	return responses, nil
+}
+
+func (c *ProjectUseCase) UpdateMember(ctx context.Context, request *model.UpdateProjectMemberRequest) (*model.ProjectMemberResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	project, _, err := c.findOwned(tx, request.ProjectId, request.Email)
+	if err != nil {
+		return nil, err
+	}
+	member := new(entity.ProjectMember)
+	if err := c.ProjectMemberRepository.FindByProjectAndEmail(tx, member, project.ID, request.MemberEmail); err != nil {
+		c.Log.WithError(err).Error("error search project member")
+		return nil, model.ErrNotFound
+	}
+	if member.Role == model.ProjectRoleOwner {
+		return nil, model.ErrForbidden
+	}
+	member.Role = request.Role
+	if err := c.ProjectMemberRepository.Update(tx, member); err != nil {
+		c.Log.WithError(err).Error("error update project member")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error update project member")
+		return nil, model.ErrInternalServer
+	}
+
+	return converter.ProjectMemberToResponse(member), nil
+}
+
+// RemoveMember lets the owner remove anyone but themselves, and lets any
+// other member leave the project.
+func (c *ProjectUseCase) RemoveMember(ctx context.Context, request *model.GetProjectMemberRequest) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return model.ErrBadRequest
+	}
+	project, actor, err := c.find(tx, request.ProjectId, request.Email)
+	if err != nil {
+		return err
+	}
+	if actor.Role != model.ProjectRoleOwner && request.MemberEmail != request.Email {
+		return model.ErrForbidden
+	}
+	member := new(entity.ProjectMember)
+	if err := c.ProjectMemberRepository.FindByProjectAndEmail(tx, member, project.ID, request.MemberEmail); err != nil {
+		c.Log.WithError(err).Error("error search project member")
+		return model.ErrNotFound
+	}
+	if member.Role == model.ProjectRoleOwner {
+		return model.ErrForbidden
+	}
+	if err := c.ProjectMemberRepository.Delete(tx, member); err != nil {
+		c.Log.WithError(err).Error("error delete project member")
+		return model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error delete project member")
+		return model.ErrInternalServer
+	}
+
+	c.forgetMember(ctx, member.Email)
+	return nil
+}
+
+func (c *ProjectUseCase) Invite(ctx context.Context, request *model.CreateInvitationRequest) (*model.InvitationResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	project, _, err := c.findOwned(tx, request.ProjectId, request.Email)
+	if err != nil {
+		return nil, err
+	}
+	if project.IsPersonal {
+		return nil, model.ErrPersonalProject
+	}
+	if ok, err := c.ProjectMemberRepository.HasRole(tx, project.ID, request.InviteeEmail, model.ProjectRoleOwner, model.ProjectRoleEditor, model.ProjectRoleViewer); err != nil {
+		c.Log.WithError(err).Error("error check project member")
+		return nil, model.ErrInternalServer
+	} else if ok {
+		return nil, model.ErrConflict
+	}
+	if total, err := c.ProjectInvitationRepository.CountPending(tx, project.ID, request.InviteeEmail); err != nil {
+		c.Log.WithError(err).Error("error count invitation")
+		return nil, model.ErrInternalServer
+	} else if total > 0 {
+		return nil, model.ErrConflict
+	}
+
+	invitation := &entity.ProjectInvitation{
+		ProjectId: project.ID,
+		Email:     request.InviteeEmail,
+		InvitedBy: request.Email,
+		Role:      request.Role,
+		Status:    "pending",
+		Project:   *project,
+	}
+	if err := c.ProjectInvitationRepository.Create(tx, invitation); err != nil {
+		c.Log.WithError(err).Error("error create invitation")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error create invitation")
+		return nil, model.ErrInternalServer
+	}
+
+	c.Notifier.Notify(ctx, model.Notification{
+		Email: invitation.Email,
+		Type:  model.NotificationProjectInvited,
+		Title: "You were invited to " + project.Name,
+		Body:  invitation.InvitedBy + " invited you to the project " + project.Name + " as " + invitation.Role,
+		Data:  map[string]any{"invitation_id": invitation.ID, "project_id": project.ID, "actor": invitation.InvitedBy},
+	})
+	return converter.InvitationToResponse(invitation), nil
+}
+
+func (c *ProjectUseCase) Invitations(ctx context.Context, request *model.SearchInvitationRequest) ([]model.InvitationResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return nil, model.ErrBadRequest
+	}
+	invitations, err := c.ProjectInvitationRepository.FindPendingByEmail(tx, request.Email)
+	if err != nil {
+		c.Log.WithError(err).Error("error search invitation")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error search invitation")
+		return nil, model.ErrInternalServer
+	}
+
+	responses := make([]model.InvitationResponse, len(invitations))
+	for i, invitation := range invitations {
+		responses[i] = *converter.InvitationToResponse(&invitation)
+	}
+	return responses, nil
+}
+
+func (c *ProjectUseCase) RespondInvitation(ctx context.Context, request *model.RespondInvitationRequest) (*model.InvitationResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return nil, model.ErrBadRequest
+	}
+	invitation := new(entity.ProjectInvitation)
+	if err := c.ProjectInvitationRepository.FindPendingByEmailAndId(tx, invitation, request.ID, request.Email); err != nil {
+		c.Log.WithError(err).Error("error search invitation")
+		return nil, model.ErrNotFound
+	}
+
+	invitation.Status = "declined"
+	if request.Accept {
+		invitation.Status = "accepted"
+		member := &entity.ProjectMember{
+			ProjectId: invitation.ProjectId,
+			Email:     invitation.Email,
+			Role:      invitation.Role,
+		}
+		if err := c.ProjectMemberRepository.Create(tx, member); err != nil {
+			c.Log.WithError(err).Error("error create project member")
+			return nil, model.ErrInternalServer
+		}
+	}
+	if err := c.ProjectInvitationRepository.Update(tx, invitation); err != nil {
+		c.Log.WithError(err).Error("error update invitation")
+		return nil, model.ErrInternalServer
+	}
+	project := new(entity.Project)
+	if err := c.ProjectRepository.FindById(tx, project, int(invitation.ProjectId)); err != nil {
+		c.Log.WithError(err).Error("error search project")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error update invitation")
+		return nil, model.ErrInternalServer
+	}
+
+	if request.Accept {
+		// the tasks of the project now count towards the stats of the user
+		if err := c.Cache.Delete(ctx, memberCacheKeys([]string{invitation.Email}, "stats:")...); err != nil {
+			c.Log.WithError(err).Warn("error invalidate member cache")
+		}
+	}
+	c.Notifier.Notify(ctx, invitationResponseNotification(invitation, project))
+	return converter.InvitationToResponse(invitation), nil
+}
+
+// invitationResponseNotification tells the inviter whether the invitation
+// was accepted or declined.
+func invitationResponseNotification(invitation *entity.ProjectInvitation, project *entity.Project) model.Notification {
+	notification := model.Notification{
+		Email: invitation.InvitedBy,
+		Type:  model.NotificationInvitationDeclined,
+		Title: invitation.Email + " declined to join " + project.Name,
+		Body:  invitation.Email + " declined your invitation to the project " + project.Name,
+		Data:  map[string]any{"invitation_id": invitation.ID, "project_id": project.ID, "actor": invitation.Email},
+	}
+	if invitation.Status == "accepted" {
+		notification.Type = model.NotificationInvitationAccepted
+		notification.Title = invitation.Email + " joined " + project.Name
+		notification.Body = invitation.Email + " accepted your invitation to the project " + project.Name
+	}
+	return notification
+}
+
+func (c *ProjectUseCase) find(tx *gorm.DB, id string, email string) (*entity.Project, *entity.ProjectMember, error) {
+	project := new(entity.Project)
+	if err := c.ProjectRepository.FindByMember(tx, project, id, email); err != nil {
+		c.Log.WithError(err).Error("error search project")
+		return nil, nil, model.ErrNotFound
+	}
+	member := new(entity.ProjectMember)
+	if err := c.ProjectMemberRepository.FindByProjectAndEmail(tx, member, project.ID, email); err != nil {
+		c.Log.WithError(err).Error("error search project member")
+		return nil, nil, model.ErrNotFound
+	}
+	return project, member, nil
+}
+
+func (c *ProjectUseCase) findOwned(tx *gorm.DB, id string, email string) (*entity.Project, *entity.ProjectMember, error) {
+	project, member, err := c.find(tx, id, email)
+	if err != nil {
+		return nil, nil, err
+	}
+	if member.Role != model.ProjectRoleOwner {
+		return nil, nil, model.ErrForbidden
+	}
+	return project, member, nil
+}
+
+// forgetMember drops every cached task and tag of a user who lost access to
+// a project, because those entries are not tied to a single project key.
+func (c *ProjectUseCase) forgetMember(ctx context.Context, email string) {
+	for _, prefix := range []string{"task:", "tags:", "task_tags:", "stats:"} {
+		if err := c.Cache.DeleteByPattern(ctx, prefix+"*email:"+email); err != nil {
+			c.Log.WithError(err).Warn("error invalidate member cache")
+		}
+	}
+}
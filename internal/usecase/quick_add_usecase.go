@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model/converter"
+	"github.com/abdisetiakawan/go-clean-arch/internal/quickadd"
+	"github.com/abdisetiakawan/go-clean-arch/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// QuickAddUseCase creates tasks from a line of text read by the quickadd
+// package.
+type QuickAddUseCase struct {
+	DB                      *gorm.DB
+	Log                     *logrus.Logger
+	Validate                *validator.Validate
+	TaskRepository          *repository.TaskRepository
+	TagRepository           *repository.TagRepository
+	TaskTagRepository       *repository.TaskTagRepository
+	ProjectMemberRepository *repository.ProjectMemberRepository
+	Indexer                 *SearchUseCase
+	Cache                   *helper.CacheHelper
+	ActivityRepository      *repository.ActivityRepository
+	Events                  *EventUseCase
+	// Now is the clock the text is read against.
+	Now func() time.Time
+}
+
+func NewQuickAddUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, taskRepository *repository.TaskRepository, tagRepository *repository.TagRepository, taskTagRepository *repository.TaskTagRepository, projectMemberRepository *repository.ProjectMemberRepository, indexer *SearchUseCase, cache *helper.CacheHelper, activityRepository *repository.ActivityRepository, events *EventUseCase) *QuickAddUseCase {
+	return &QuickAddUseCase{
+		DB:                      db,
+		Log:                     log,
+		Validate:                validate,
+		TaskRepository:          taskRepository,
+		TagRepository:           tagRepository,
+		TaskTagRepository:       taskTagRepository,
+		ProjectMemberRepository: projectMemberRepository,
+		Indexer:                 indexer,
+		Cache:                   cache,
+		ActivityRepository:      activityRepository,
+		Events:                  events,
+		Now:                     time.Now,
+	}
+}
+
+// Create reads the text in the timezone of the user and creates the task
+// with its tags, creating missing tags in the project. A project named
+// with @ is used unless the request names one.
+func (c *QuickAddUseCase) Create(ctx context.Context, request *model.QuickAddTaskRequest) (*model.QuickAddTaskResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	parsed, err := quickadd.NewParser(helper.Location(request.Timezone), c.Now).Parse(request.Text)
+	if err != nil {
+		c.Log.WithError(err).Error("error parse quick add text")
+		return nil, model.NewApiError(model.ErrBadRequest.StatusCode, "text must contain a title")
+	}
+	if len(parsed.Title) > 150 {
+		return nil, model.NewApiError(model.ErrBadRequest.StatusCode, "title must be at most 150 characters")
+	}
+	response := &model.QuickAddTaskResponse{
+		Parsed: model.QuickAddParsed{
+			Title:      parsed.Title,
+			DueDate:    parsed.DueDate,
+			DueTime:    parsed.DueTime,
+			Tags:       parsed.Tags,
+			Project:    parsed.Project,
+			Priority:   parsed.Priority,
+			Recurrence: parsed.Recurrence,
+		},
+	}
+	if request.DryRun {
+		return response, nil
+	}
+
+	projectId := request.ProjectId
+	if projectId == 0 && parsed.Project != "" {
+		if projectId, err = c.ProjectMemberRepository.FindWritableByName(tx, parsed.Project, request.Email); err != nil {
+			c.Log.WithError(err).Error("error search project by name")
+			return nil, model.NewApiError(model.ErrBadRequest.StatusCode, "no project named "+parsed.Project+" to add tasks to")
+		}
+	}
+	projectId, err = c.ProjectMemberRepository.ResolveWritable(tx, projectId, request.Email)
+	if err != nil {
+		c.Log.WithError(err).Error("error resolve project")
+		return nil, model.ErrForbidden
+	}
+	lastPosition, err := c.TaskRepository.MaxPosition(tx, projectId, "pending")
+	if err != nil {
+		c.Log.WithError(err).Error("error search task position")
+		return nil, model.ErrInternalServer
+	}
+	task := &entity.Task{
+		Email:     request.Email,
+		ProjectId: projectId,
+		Title:     parsed.Title,
+		Status:    "pending",
+		Priority:  parsed.Priority,
+		Position:  helper.RankBetween(lastPosition, ""),
+		DueDate:   helper.CalendarDate(parsed.DueDate),
+		DueTime:   parsed.DueTime,
+	}
+	if task.Priority == "" {
+		task.Priority = "medium"
+	}
+	if parsed.Recurrence != "" {
+		task.Recurrence = &parsed.Recurrence
+	}
+	if err := c.TaskRepository.Create(tx, task); err != nil {
+		c.Log.WithError(err).Error("error create task")
+		return nil, model.ErrInternalServer
+	}
+	tagIds, err := newTagResolver(c.TagRepository, projectId, request.Email, true).resolve(tx, parsed.Tags)
+	if err != nil {
+		c.Log.WithError(err).Error("error resolve quick add tags")
+		return nil, model.ErrInternalServer
+	}
+	if err := c.TaskTagRepository.Attach(tx, task.ID, tagIds); err != nil {
+		c.Log.WithError(err).Error("error attach quick add tags")
+		return nil, model.ErrInternalServer
+	}
+	activity := taskCreatedActivity(task, request.Email)
+	if err := c.ActivityRepository.Record(tx, activity); err != nil {
+		c.Log.WithError(err).Error("error record activity")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error create task")
+		return nil, model.ErrInternalServer
+	}
+	c.Events.Publish(ctx, activity)
+
+	if err := forgetProjectCache(ctx, c.DB, c.ProjectMemberRepository, c.Cache, []uint{projectId}, "stats:"); err != nil {
+		c.Log.WithError(err).Warn("error load project members for cache invalidation")
+	}
+	c.Indexer.Refresh(ctx, task.ID)
+	response.Task = converter.TaskToResponse(task)
+	return response, nil
+}
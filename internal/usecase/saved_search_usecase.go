@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model/converter"
+	"github.com/abdisetiakawan/go-clean-arch/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// builtInLists are the smart lists every user gets out of the box.
+var builtInLists = []model.SavedSearchResponse{
+	{ID: "today", Name: "Today", Query: "due:today -status:completed", Sort: "-priority", BuiltIn: true},
+	{ID: "overdue", Name: "Overdue", Query: "due<today -status:completed", Sort: "due_date", BuiltIn: true},
+	{ID: "upcoming", Name: "Upcoming 7 days", Query: "due>today due<=+7d -status:completed", Sort: "due_date", BuiltIn: true},
+	{ID: "no_due_date", Name: "No due date", Query: "due:none -status:completed", Sort: "-priority", BuiltIn: true},
+}
+
+type SavedSearchUseCase struct {
+	DB                    *gorm.DB
+	Log                   *logrus.Logger
+	Validate              *validator.Validate
+	SavedSearchRepository *repository.SavedSearchRepository
+	TaskRepository        *repository.TaskRepository
+}
+
+func NewSavedSearchUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, savedSearchRepository *repository.SavedSearchRepository, taskRepository *repository.TaskRepository) *SavedSearchUseCase {
+	return &SavedSearchUseCase{
+		DB:                    db,
+		Log:                   log,
+		Validate:              validate,
+		SavedSearchRepository: savedSearchRepository,
+		TaskRepository:        taskRepository,
+	}
+}
+
+func (c *SavedSearchUseCase) Create(ctx context.Context, request *model.CreateSavedSearchRequest) (*model.SavedSearchResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	if _, err := parseFilter(request.Query); err != nil {
+		c.Log.WithError(err).Error("error parse saved search")
+		return nil, err
+	}
+	if err := c.checkName(tx, request.Email, request.Name, 0); err != nil {
+		return nil, err
+	}
+	search := &entity.SavedSearch{
+		Email:  request.Email,
+		Name:   request.Name,
+		Query:  request.Query,
+		Sort:   request.Sort,
+		Pinned: request.Pinned,
+	}
+	if err := c.SavedSearchRepository.Create(tx, search); err != nil {
+		c.Log.WithError(err).Error("error create saved search")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error create saved search")
+		return nil, model.ErrInternalServer
+	}
+
+	return converter.SavedSearchToResponse(search), nil
+}
+
+// List returns the built-in lists followed by the user's own lists.
+func (c *SavedSearchUseCase) List(ctx context.Context, email string) ([]model.SavedSearchResponse, error) {
+	searches, err := c.SavedSearchRepository.FindByEmail(c.DB.WithContext(ctx), email)
+	if err != nil {
+		c.Log.WithError(err).Error("error search saved searches")
+		return nil, model.ErrInternalServer
+	}
+	responses := append([]model.SavedSearchResponse{}, builtInLists...)
+	for _, search := range searches {
+		responses = append(responses, *converter.SavedSearchToResponse(&search))
+	}
+	return responses, nil
+}
+
+func (c *SavedSearchUseCase) Get(ctx context.Context, request *model.GetSavedSearchRequest) (*model.SavedSearchResponse, error) {
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return nil, model.ErrBadRequest
+	}
+	if list := findBuiltInList(request.ID); list != nil {
+		return list, nil
+	}
+	search := new(entity.SavedSearch)
+	if err := c.SavedSearchRepository.FindByEmailAndId(c.DB.WithContext(ctx), search, request.ID, request.Email); err != nil {
+		c.Log.WithError(err).Error("error search saved search")
+		return nil, model.ErrNotFound
+	}
+	return converter.SavedSearchToResponse(search), nil
+}
+
+func (c *SavedSearchUseCase) Update(ctx context.Context, request *model.UpdateSavedSearchRequest) (*model.SavedSearchResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	search, err := c.find(tx, request.ID, request.Email)
+	if err != nil {
+		return nil, err
+	}
+	if request.Name != "" && request.Name != search.Name {
+		if err := c.checkName(tx, request.Email, request.Name, search.ID); err != nil {
+			return nil, err
+		}
+		search.Name = request.Name
+	}
+	if request.Query != nil {
+		if _, err := parseFilter(*request.Query); err != nil {
+			c.Log.WithError(err).Error("error parse saved search")
+			return nil, err
+		}
+		search.Query = *request.Query
+	}
+	if request.Sort != nil {
+		search.Sort = *request.Sort
+	}
+	if request.Pinned != nil {
+		search.Pinned = *request.Pinned
+	}
+	if err := c.SavedSearchRepository.Update(tx, search); err != nil {
+		c.Log.WithError(err).Error("error update saved search")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error update saved search")
+		return nil, model.ErrInternalServer
+	}
+
+	return converter.SavedSearchToResponse(search), nil
+}
+
+func (c *SavedSearchUseCase) Pin(ctx context.Context, request *model.PinSavedSearchRequest) (*model.SavedSearchResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return nil, model.ErrBadRequest
+	}
+	search, err := c.find(tx, request.ID, request.Email)
+	if err != nil {
+		return nil, err
+	}
+	search.Pinned = request.Pinned
+	if err := c.SavedSearchRepository.Update(tx, search); err != nil {
+		c.Log.WithError(err).Error("error pin saved search")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error pin saved search")
+		return nil, model.ErrInternalServer
+	}
+
+	return converter.SavedSearchToResponse(search), nil
+}
+
+func (c *SavedSearchUseCase) Delete(ctx context.Context, request *model.GetSavedSearchRequest) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return model.ErrBadRequest
+	}
+	search, err := c.find(tx, request.ID, request.Email)
+	if err != nil {
+		return err
+	}
+	if err := c.SavedSearchRepository.Delete(tx, search); err != nil {
+		c.Log.WithError(err).Error("error delete saved search")
+		return model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error delete saved search")
+		return model.ErrInternalServer
+	}
+	return nil
+}
+
+// Tasks evaluates a built-in or saved list against the current tasks.
+func (c *SavedSearchUseCase) Tasks(ctx context.Context, request *model.SavedSearchTasksRequest) ([]model.TaskResponse, *model.PageMetadata, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return nil, nil, model.ErrBadRequest
+	}
+	list := findBuiltInList(request.ID)
+	if list == nil {
+		search := new(entity.SavedSearch)
+		if err := c.SavedSearchRepository.FindByEmailAndId(tx, search, request.ID, request.Email); err != nil {
+			c.Log.WithError(err).Error("error search saved search")
+			return nil, nil, model.ErrNotFound
+		}
+		list = converter.SavedSearchToResponse(search)
+	}
+	expr, err := parseFilter(list.Query)
+	if err != nil {
+		c.Log.WithError(err).Error("error parse saved search")
+		return nil, nil, err
+	}
+	search := &model.SearchTaskRequest{
+		Email:    request.Email,
+		Sort:     list.Sort,
+		Page:     request.Page,
+		Size:     request.Size,
+		Cursor:   request.Cursor,
+		Total:    request.Total,
+		Timezone: request.Timezone,
+	}
+	tasks, paging, err := c.TaskRepository.Search(tx, search, expr)
+	if err != nil {
+		c.Log.WithError(err).Error("error search task")
+		return nil, nil, pageError(err, model.ErrInternalServer)
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error search task")
+		return nil, nil, model.ErrInternalServer
+	}
+
+	responses := make([]model.TaskResponse, len(tasks))
+	for i, task := range tasks {
+		task.Email = ""
+		responses[i] = *converter.TaskToResponse(&task)
+	}
+	return responses, paging, nil
+}
+
+// find loads one of the user's own lists. Built-in lists are reported as
+// forbidden since they cannot be changed.
+func (c *SavedSearchUseCase) find(tx *gorm.DB, id string, email string) (*entity.SavedSearch, error) {
+	if findBuiltInList(id) != nil {
+		return nil, model.ErrForbidden
+	}
+	search := new(entity.SavedSearch)
+	if err := c.SavedSearchRepository.FindByEmailAndId(tx, search, id, email); err != nil {
+		c.Log.WithError(err).Error("error search saved search")
+		return nil, model.ErrNotFound
+	}
+	return search, nil
+}
+
+func (c *SavedSearchUseCase) checkName(tx *gorm.DB, email string, name string, exceptId uint) error {
+	total, err := c.SavedSearchRepository.CountByName(tx, email, name, exceptId)
+	if err != nil {
+		c.Log.WithError(err).Error("error count saved search")
+		return model.ErrInternalServer
+	}
+	if total > 0 {
+		return model.ErrConflict
+	}
+	return nil
+}
+
+func findBuiltInList(id string) *model.SavedSearchResponse {
+	for _, list := range builtInLists {
+		if list.ID == id {
+			return &list
+		}
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model/converter"
+	"github.com/abdisetiakawan/go-clean-arch/internal/repository"
+	"github.com/abdisetiakawan/go-clean-arch/internal/search"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const reindexBatchSize = 500
+
+type SearchUseCase struct {
+	DB                      *gorm.DB
+	Log                     *logrus.Logger
+	Validate                *validator.Validate
+	Index                   search.Index
+	TaskRepository          *repository.TaskRepository
+	TaskTagRepository       *repository.TaskTagRepository
+	CommentRepository       *repository.CommentRepository
+	ProjectMemberRepository *repository.ProjectMemberRepository
+}
+
+func NewSearchUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, index search.Index, taskRepository *repository.TaskRepository, taskTagRepository *repository.TaskTagRepository, commentRepository *repository.CommentRepository, projectMemberRepository *repository.ProjectMemberRepository) *SearchUseCase {
+	return &SearchUseCase{
+		DB:                      db,
+		Log:                     log,
+		Validate:                validate,
+		Index:                   index,
+		TaskRepository:          taskRepository,
+		TaskTagRepository:       taskTagRepository,
+		CommentRepository:       commentRepository,
+		ProjectMemberRepository: projectMemberRepository,
+	}
+}
+
+func (c *SearchUseCase) Search(ctx context.Context, request *model.FullTextSearchRequest) ([]model.TaskSearchResult, int64, error) {
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return nil, 0, model.ErrBadRequest
+	}
+	terms, err := search.ParseQuery(request.Query)
+	if err != nil {
+		c.Log.WithError(err).Error("error parse search query")
+		return nil, 0, model.ErrBadRequest
+	}
+
+	db := c.DB.WithContext(ctx)
+	projectIds, err := c.ProjectMemberRepository.ProjectIds(db, request.Email)
+	if err != nil {
+		c.Log.WithError(err).Error("error search projects")
+		return nil, 0, model.ErrInternalServer
+	}
+	if request.ProjectId != 0 {
+		if !slices.Contains(projectIds, request.ProjectId) {
+			return nil, 0, model.ErrNotFound
+		}
+		projectIds = []uint{request.ProjectId}
+	}
+
+	hits, total, err := c.Index.Search(ctx, search.Query{
+		Text:       request.Query,
+		ProjectIds: projectIds,
+		Page:       request.Page,
+		Size:       request.Size,
+	})
+	if errors.Is(err, search.ErrEmptyQuery) {
+		return nil, 0, model.ErrBadRequest
+	} else if err != nil {
+		c.Log.WithError(err).Error("error search task index")
+		return nil, 0, model.ErrInternalServer
+	}
+
+	taskIds := make([]uint, len(hits))
+	for i, hit := range hits {
+		taskIds[i] = hit.TaskId
+	}
+	tasks, documents, err := c.load(db, taskIds)
+	if err != nil {
+		c.Log.WithError(err).Error("error load search results")
+		return nil, 0, model.ErrInternalServer
+	}
+
+	results := make([]model.TaskSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		index := slices.IndexFunc(tasks, func(task entity.Task) bool { return task.ID == hit.TaskId })
+		if index < 0 {
+			continue
+		}
+		task := tasks[index]
+		task.Email = ""
+		results = append(results, model.TaskSearchResult{
+			Task:       *converter.TaskToResponse(&task),
+			Score:      hit.Score,
+			Highlights: highlights(documents[hit.TaskId], terms),
+		})
+	}
+	return results, total, nil
+}
+
+// Refresh re-indexes the given tasks. Indexing is best effort: a failure is
+// logged and never fails the request that changed the task.
+func (c *SearchUseCase) Refresh(ctx context.Context, taskIds ...uint) {
+	if !c.Index.NeedsSync() || len(taskIds) == 0 {
+		return
+	}
+	_, documents, err := c.load(c.DB.WithContext(ctx), taskIds)
+	if err != nil {
+		c.Log.WithError(err).Warn("error load search documents")
+		return
+	}
+	var missing []uint
+	for _, taskId := range taskIds {
+		if _, ok := documents[taskId]; !ok {
+			missing = append(missing, taskId)
+		}
+	}
+	if err := c.Index.Sync(ctx, documentList(documents)...); err != nil {
+		c.Log.WithError(err).Warn("error sync search index")
+	}
+	c.Forget(ctx, missing...)
+}
+
+func (c *SearchUseCase) Forget(ctx context.Context, taskIds ...uint) {
+	if !c.Index.NeedsSync() || len(taskIds) == 0 {
+		return
+	}
+	if err := c.Index.Remove(ctx, taskIds...); err != nil {
+		c.Log.WithError(err).Warn("error remove from search index")
+	}
+}
+
+// Reindex loads every task into the index. It is used on start up by
+// backends that keep their own copy of the documents.
+func (c *SearchUseCase) Reindex(ctx context.Context) error {
+	if !c.Index.NeedsSync() {
+		return nil
+	}
+	db := c.DB.WithContext(ctx)
+	var lastId uint
+	for {
+		tasks, err := c.TaskRepository.FindBatch(db, lastId, reindexBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(tasks) == 0 {
+			return nil
+		}
+		taskIds := make([]uint, len(tasks))
+		for i, task := range tasks {
+			taskIds[i] = task.ID
+		}
+		_, documents, err := c.load(db, taskIds)
+		if err != nil {
+			return err
+		}
+		if err := c.Index.Sync(ctx, documentList(documents)...); err != nil {
+			return err
+		}
+		lastId = tasks[len(tasks)-1].ID
+	}
+}
+
+// load reads the tasks together with the documents the index keeps for them.
+func (c *SearchUseCase) load(db *gorm.DB, taskIds []uint) ([]entity.Task, map[uint]search.Document, error) {
+	documents := map[uint]search.Document{}
+	if len(taskIds) == 0 {
+		return nil, documents, nil
+	}
+	tasks, err := c.TaskRepository.FindByIds(db, taskIds)
+	if err != nil {
+		return nil, nil, err
+	}
+	comments, err := c.CommentRepository.FindByTaskIds(db, taskIds)
+	if err != nil {
+		return nil, nil, err
+	}
+	tags, err := c.TaskTagRepository.FindTagsByTaskIds(db, taskIds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, task := range tasks {
+		documents[task.ID] = search.Document{
+			TaskId:      task.ID,
+			ProjectId:   task.ProjectId,
+			Title:       task.Title,
+			Description: task.Description,
+		}
+	}
+	for _, comment := range comments {
+		if document, ok := documents[comment.TaskId]; ok {
+			document.Comments = append(document.Comments, comment.Body)
+			documents[comment.TaskId] = document
+		}
+	}
+	for _, tag := range tags {
+		if document, ok := documents[tag.TaskId]; ok {
+			document.Tags = append(document.Tags, tag.Name)
+			documents[tag.TaskId] = document
+		}
+	}
+	return tasks, documents, nil
+}
+
+func documentList(documents map[uint]search.Document) []search.Document {
+	list := make([]search.Document, 0, len(documents))
+	for _, document := range documents {
+		list = append(list, document)
+	}
+	return list
+}
+
+func highlights(document search.Document, terms []search.Term) map[string][]string {
+	result := map[string][]string{}
+	if snippets := search.Highlight(document.Title, terms, 1); len(snippets) > 0 {
+		result["title"] = snippets
+	}
+	if snippets := search.Highlight(document.Description, terms, 3); len(snippets) > 0 {
+		result["description"] = snippets
+	}
+	for _, comment := range document.Comments {
+		if len(result["comments"]) == 3 {
+			break
+		}
+		result["comments"] = append(result["comments"], search.Highlight(comment, terms, 1)...)
+	}
+	if len(result["comments"]) == 0 {
+		delete(result, "comments")
+	}
+	for _, tag := range document.Tags {
+		result["tags"] = append(result["tags"], search.Highlight(tag, terms, 1)...)
+	}
+	if len(result["tags"]) == 0 {
+		delete(result, "tags")
+	}
+	return result
+}
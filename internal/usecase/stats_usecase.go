@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// statsRangeDays is the default number of days of completion history.
+	statsRangeDays = 30
+	// statsMaxRangeDays bounds the completion history of one request.
+	statsMaxRangeDays = 366
+	// statsTagLimit bounds the tags counted in by_tag.
+	statsTagLimit = 50
+	// statsCacheEntries bounds the ranges cached for one user.
+	statsCacheEntries = 20
+)
+
+// statsCacheTTL also bounds how long overdue counts lag behind the clock.
+const statsCacheTTL = 5 * time.Minute
+
+// StatsUseCase aggregates the tasks of a user. Results are cached in one
+// key per user, "stats:email:<email>", holding every range asked for, so
+// task changes drop them with the other per member keys.
+type StatsUseCase struct {
+	DB             *gorm.DB
+	Log            *logrus.Logger
+	Validate       *validator.Validate
+	TaskRepository *repository.TaskRepository
+	Cache          *helper.CacheHelper
+}
+
+func NewStatsUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, taskRepository *repository.TaskRepository, cache *helper.CacheHelper) *StatsUseCase {
+	return &StatsUseCase{
+		DB:             db,
+		Log:            log,
+		Validate:       validate,
+		TaskRepository: taskRepository,
+		Cache:          cache,
+	}
+}
+
+func (c *StatsUseCase) Get(ctx context.Context, request *model.GetStatsRequest) (*model.StatsResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	location := helper.Location(request.Timezone)
+	now := time.Now().In(location)
+	from, to, err := statsRange(request, now)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := "stats:email:" + request.Email
+	entry := fmt.Sprintf("%d|%s|%s|%s", request.ProjectId, from.Format(time.DateOnly), to.Format(time.DateOnly), location)
+	cached := map[string]*model.StatsResponse{}
+	if err := c.Cache.GetAndUnmarshal(ctx, cacheKey, &cached); err == nil && cached[entry] != nil {
+		return cached[entry], nil
+	}
+
+	response, err := c.aggregate(tx, request, now, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(cached) >= statsCacheEntries {
+		cached = map[string]*model.StatsResponse{}
+	}
+	cached[entry] = response
+	if cachedJSON, err := json.Marshal(cached); err == nil {
+		if err := c.Cache.Set(ctx, cacheKey, cachedJSON, statsCacheTTL); err != nil {
+			c.Log.WithError(err).Warn("error cache stats")
+		}
+	}
+	return response, nil
+}
+
+// statsRange resolves the days of the request to midnight in the user's
+// time zone. Both days are included.
+func statsRange(request *model.GetStatsRequest, now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	to := today
+	if request.To != "" {
+		to, _ = time.ParseInLocation(time.DateOnly, request.To, now.Location())
+	}
+	from := to.AddDate(0, 0, 1-statsRangeDays)
+	if request.From != "" {
+		from, _ = time.ParseInLocation(time.DateOnly, request.From, now.Location())
+	}
+	if from.After(to) {
+		return from, to, model.NewApiError(model.ErrBadRequest.StatusCode, "from must not be after to")
+	}
+	if from.AddDate(0, 0, statsMaxRangeDays).Before(to.AddDate(0, 0, 1)) {
+		return from, to, model.NewApiError(model.ErrBadRequest.StatusCode, fmt.Sprintf("range must not exceed %d days", statsMaxRangeDays))
+	}
+	return from, to, nil
+}
+
+func (c *StatsUseCase) aggregate(tx *gorm.DB, request *model.GetStatsRequest, now time.Time, from time.Time, to time.Time) (*model.StatsResponse, error) {
+	response := &model.StatsResponse{
+		From:       from.Format(time.DateOnly),
+		To:         to.Format(time.DateOnly),
+		ByStatus:   map[string]int64{},
+		ByPriority: map[string]int64{},
+	}
+	for _, status := range model.TaskStatuses {
+		response.ByStatus[status] = 0
+	}
+	for _, priority := range model.TaskPriorities {
+		response.ByPriority[priority] = 0
+	}
+
+	counts, err := c.TaskRepository.CountByStatusAndPriority(tx, request.Email, request.ProjectId)
+	if err != nil {
+		c.Log.WithError(err).Error("error count tasks")
+		return nil, model.ErrInternalServer
+	}
+	for _, count := range counts {
+		response.Total += count.Count
+		response.ByStatus[count.Status] += count.Count
+		response.ByPriority[count.Priority] += count.Count
+	}
+	if response.Overdue, err = c.TaskRepository.CountOverdue(tx, request.Email, request.ProjectId, now); err != nil {
+		c.Log.WithError(err).Error("error count overdue tasks")
+		return nil, model.ErrInternalServer
+	}
+	if response.ByTag, err = c.TaskRepository.CountByTag(tx, request.Email, request.ProjectId, statsTagLimit); err != nil {
+		c.Log.WithError(err).Error("error count tasks by tag")
+		return nil, model.ErrInternalServer
+	}
+	if response.ByTag == nil {
+		response.ByTag = []model.TagStats{}
+	}
+
+	end := to.AddDate(0, 0, 1)
+	created, createdCompleted, err := c.TaskRepository.CountCreated(tx, request.Email, request.ProjectId, from, end)
+	if err != nil {
+		c.Log.WithError(err).Error("error count created tasks")
+		return nil, model.ErrInternalServer
+	}
+	response.Created = created
+	if created > 0 {
+		response.CompletionRate = math.Round(float64(createdCompleted)/float64(created)*1000) / 1000
+	}
+	completed, err := c.TaskRepository.FindCompleted(tx, request.Email, request.ProjectId, from, end)
+	if err != nil {
+		c.Log.WithError(err).Error("error search completed tasks")
+		return nil, model.ErrInternalServer
+	}
+	response.Completed = int64(len(completed))
+
+	perDay := map[string]int{}
+	var leadTime time.Duration
+	for _, task := range completed {
+		perDay[task.CompletedAt.In(from.Location()).Format(time.DateOnly)]++
+		if lead := task.CompletedAt.Sub(task.CreatedAt); lead > 0 {
+			leadTime += lead
+		}
+	}
+	if len(completed) > 0 {
+		hours := math.Round(leadTime.Hours()/float64(len(completed))*100) / 100
+		response.AverageLeadTimeHours = &hours
+	}
+
+	run := 0
+	weeks := map[string]int{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		response.Heatmap = append(response.Heatmap, model.DailyCompletions{Date: date, Completed: perDay[date]})
+
+		// weeks start on Monday
+		week := day.AddDate(0, 0, -(int(day.Weekday())+6)%7).Format(time.DateOnly)
+		if _, ok := weeks[week]; !ok {
+			response.Throughput = append(response.Throughput, model.WeeklyThroughput{Week: week})
+		}
+		weeks[week] += perDay[date]
+
+		if perDay[date] > 0 {
+			run++
+		} else {
+			run = 0
+		}
+		response.Streak.Longest = max(response.Streak.Longest, run)
+	}
+	for i := range response.Throughput {
+		response.Throughput[i].Completed = weeks[response.Throughput[i].Week]
+	}
+	response.Streak.Current = run
+	if run == 0 && len(response.Heatmap) > 1 {
+		// the last day may still see completions, so the run up to the day
+		// before is still going
+		for i := len(response.Heatmap) - 2; i >= 0 && response.Heatmap[i].Completed > 0; i-- {
+			response.Streak.Current++
+		}
+	}
+	return response, nil
+}
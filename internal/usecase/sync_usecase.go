@@ -0,0 +1,477 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model/converter"
+	"github.com/abdisetiakawan/go-clean-arch/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// changeRetention is how long the change log keeps a change. A sync
+	// token older than that gets everything again.
+	changeRetention = 30 * 24 * time.Hour
+	// changePruneInterval is how often Run prunes the change log.
+	changePruneInterval = time.Hour
+)
+
+// SyncUseCase lets offline clients sync tasks, tags and the links between
+// them. Pulls read the change log the repositories write; pushes go
+// through the use cases of the entities, so they are checked and recorded
+// like any other request.
+type SyncUseCase struct {
+	DB                      *gorm.DB
+	Log                     *logrus.Logger
+	Validate                *validator.Validate
+	ChangeRepository        *repository.ChangeRepository
+	ProjectMemberRepository *repository.ProjectMemberRepository
+	TaskRepository          *repository.TaskRepository
+	TagRepository           *repository.TagRepository
+	TaskTagRepository       *repository.TaskTagRepository
+	TaskUseCase             *TaskUseCase
+	TagUseCase              *TagUseCase
+	TaskTagUseCase          *TaskTagUseCase
+}
+
+func NewSyncUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, changeRepository *repository.ChangeRepository, projectMemberRepository *repository.ProjectMemberRepository, taskRepository *repository.TaskRepository, tagRepository *repository.TagRepository, taskTagRepository *repository.TaskTagRepository, taskUseCase *TaskUseCase, tagUseCase *TagUseCase, taskTagUseCase *TaskTagUseCase) *SyncUseCase {
+	return &SyncUseCase{
+		DB:                      db,
+		Log:                     log,
+		Validate:                validate,
+		ChangeRepository:        changeRepository,
+		ProjectMemberRepository: projectMemberRepository,
+		TaskRepository:          taskRepository,
+		TagRepository:           tagRepository,
+		TaskTagRepository:       taskTagRepository,
+		TaskUseCase:             taskUseCase,
+		TagUseCase:              tagUseCase,
+		TaskTagUseCase:          taskTagUseCase,
+	}
+}
+
+// Pull returns what changed since the token of the request. Without a
+// token, when the user joined a project since or when the changes after
+// the token were pruned, it returns everything with Reset set. Everything
+// is read from one snapshot of the database.
+func (c *SyncUseCase) Pull(ctx context.Context, request *model.SyncPullRequest) (*model.SyncPullResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return nil, model.ErrBadRequest
+	}
+	var token *helper.SyncToken
+	if request.Since != "" {
+		decoded, err := helper.DecodeSyncToken(request.Since)
+		if err != nil {
+			c.Log.WithError(err).Error("error decode sync token")
+			return nil, model.ErrInvalidSyncToken
+		}
+		token = decoded
+	}
+	// the snapshot of tx starts with its first read, after this
+	if err := c.ChangeRepository.Sequence(c.DB.WithContext(ctx)); err != nil {
+		c.Log.WithError(err).Error("error sequence changes")
+		return nil, model.ErrInternalServer
+	}
+	sequence := new(entity.ChangeSequence)
+	if err := c.ChangeRepository.FindSequence(tx, sequence); err != nil {
+		c.Log.WithError(err).Error("error sequence changes")
+		return nil, model.ErrInternalServer
+	}
+	projectIds, err := c.ProjectMemberRepository.ProjectIds(tx, request.Email)
+	if err != nil {
+		c.Log.WithError(err).Error("error search projects")
+		return nil, model.ErrInternalServer
+	}
+	slices.Sort(projectIds)
+
+	response := &model.SyncPullResponse{
+		Projects: projectIds,
+		Tasks:    []model.TaskResponse{},
+		Tags:     []model.TagResponse{},
+		TaskTags: []model.TaskTagLink{},
+		Deleted:  model.SyncDeleted{Tasks: []uint{}, Tags: []uint{}, TaskTags: []model.TaskTagLink{}},
+	}
+	var next uint
+	if token == nil || joinedProject(projectIds, token.Projects) || token.Change < sequence.Pruned {
+		next, err = sequence.Value, c.snapshot(tx, projectIds, response)
+	} else {
+		next, err = c.delta(tx, projectIds, token.Change, request.Limit, response)
+	}
+	if err != nil {
+		c.Log.WithError(err).Error("error sync changes")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error sync changes")
+		return nil, model.ErrInternalServer
+	}
+
+	response.Token = helper.EncodeSyncToken(&helper.SyncToken{Change: next, Projects: projectIds})
+	return response, nil
+}
+
+// joinedProject reports whether projectIds holds a project the token did
+// not cover.
+func joinedProject(projectIds []uint, known []uint) bool {
+	for _, id := range projectIds {
+		if !slices.Contains(known, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshot fills the response with every task, tag and link of the
+// projects. The next pull starts after the last change numbered in the
+// snapshot; changes committed but not numbered yet are part of the
+// snapshot and sent again then.
+func (c *SyncUseCase) snapshot(tx *gorm.DB, projectIds []uint, response *model.SyncPullResponse) error {
+	response.Reset = true
+	return c.load(tx, projectIds, nil, nil, nil, response)
+}
+
+// delta fills the response with the state of up to limit entities changed
+// after the change with seq after, and returns the change the next pull
+// starts after.
+func (c *SyncUseCase) delta(tx *gorm.DB, projectIds []uint, after uint, limit int, response *model.SyncPullResponse) (uint, error) {
+	changed, err := c.ChangeRepository.Since(tx, projectIds, after, limit)
+	if err != nil {
+		return 0, err
+	}
+	next := after
+	if len(changed) > 0 {
+		next = changed[len(changed)-1].Seq
+	}
+	response.HasMore = len(changed) == limit
+
+	taskIds, tagIds, links := []uint{}, []uint{}, []model.TaskTagLink{}
+	for _, change := range changed {
+		switch change.EntityType {
+		case "task":
+			taskIds = append(taskIds, *change.TaskId)
+		case "tag":
+			tagIds = append(tagIds, *change.TagId)
+		case "task_tag":
+			links = append(links, model.TaskTagLink{TaskId: *change.TaskId, TagId: *change.TagId})
+		}
+	}
+	if err := c.load(tx, projectIds, taskIds, tagIds, links, response); err != nil {
+		return 0, err
+	}
+
+	// what changed and cannot be found was deleted, or moved out of reach
+	for _, id := range taskIds {
+		if !slices.ContainsFunc(response.Tasks, func(task model.TaskResponse) bool { return task.ID == id }) {
+			response.Deleted.Tasks = append(response.Deleted.Tasks, id)
+		}
+	}
+	for _, id := range tagIds {
+		if !slices.ContainsFunc(response.Tags, func(tag model.TagResponse) bool { return tag.ID == id }) {
+			response.Deleted.Tags = append(response.Deleted.Tags, id)
+		}
+	}
+	for _, link := range links {
+		if !slices.Contains(response.TaskTags, link) {
+			response.Deleted.TaskTags = append(response.Deleted.TaskTags, link)
+		}
+	}
+	return next, nil
+}
+
+// load fills the response with the tasks, tags and links of the projects,
+// or only the given ones when they are not nil.
+func (c *SyncUseCase) load(tx *gorm.DB, projectIds []uint, taskIds []uint, tagIds []uint, links []model.TaskTagLink, response *model.SyncPullResponse) error {
+	tasks, err := c.TaskRepository.FindInProjects(tx, projectIds, taskIds)
+	if err != nil {
+		return err
+	}
+	for i := range tasks {
+		response.Tasks = append(response.Tasks, *converter.TaskToResponse(&tasks[i]))
+	}
+	tags, err := c.TagRepository.FindInProjects(tx, projectIds, tagIds)
+	if err != nil {
+		return err
+	}
+	for i := range tags {
+		response.Tags = append(response.Tags, *converter.TagToResponse(&tags[i]))
+	}
+	found, err := c.TaskTagRepository.FindLinks(tx, projectIds, links)
+	if err != nil {
+		return err
+	}
+	response.TaskTags = append(response.TaskTags, found...)
+	return nil
+}
+
+// Run numbers the changes and prunes the ones older than changeRetention
+// every changePruneInterval, until ctx is done.
+func (c *SyncUseCase) Run(ctx context.Context) {
+	ticker := time.NewTicker(changePruneInterval)
+	defer ticker.Stop()
+	for {
+		db := c.DB.WithContext(ctx)
+		if err := c.ChangeRepository.Sequence(db); err != nil {
+			c.Log.WithError(err).Error("error sequence changes")
+		} else if deleted, err := c.ChangeRepository.Prune(db, time.Now().Add(-changeRetention)); err != nil {
+			c.Log.WithError(err).Error("error prune changes")
+		} else if deleted > 0 {
+			c.Log.Infof("pruned %d changes", deleted)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Push applies the changes of the client in order, each on its own. A
+// change that cannot be applied is reported in its result and does not
+// stop the ones after it.
+func (c *SyncUseCase) Push(ctx context.Context, request *model.SyncPushRequest) (*model.SyncPushResponse, error) {
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+
+	refs := map[string]uint{}
+	results := make([]model.SyncResult, len(request.Changes))
+	for i := range request.Changes {
+		change := &request.Changes[i]
+		result := c.apply(ctx, request.Email, change, refs)
+		result.Index, result.Ref = i, change.Ref
+		if change.Op == "create" && change.Ref != "" && result.Status == model.SyncApplied {
+			refs[change.Entity+":"+change.Ref] = result.ID
+		}
+		results[i] = *result
+	}
+	return &model.SyncPushResponse{Results: results}, nil
+}
+
+// syncTarget returns the id of an entity, named by its id or by the ref of
+// its create earlier in the push.
+func syncTarget(refs map[string]uint, entityType string, id uint, ref string) (uint, bool) {
+	if id != 0 {
+		return id, true
+	}
+	id, ok := refs[entityType+":"+ref]
+	return id, ok
+}
+
+// syncIfMatch turns the version a change was based on into an If-Match
+// header. Without a version the change is applied whatever the version.
+func syncIfMatch(version *uint) string {
+	if version == nil {
+		return ""
+	}
+	return helper.ETag(*version)
+}
+
+func syncInvalid(message string) *model.SyncResult {
+	return &model.SyncResult{Status: model.SyncInvalid, Error: message}
+}
+
+// syncFailure reports the error of a change by its status code.
+func syncFailure(err error) *model.SyncResult {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &model.SyncResult{Status: model.SyncNotFound, Error: model.ErrNotFound.Message}
+	}
+	var apiError *model.ApiError
+	if !errors.As(err, &apiError) {
+		return &model.SyncResult{Status: model.SyncFailed, Error: model.ErrInternalServer.Message}
+	}
+	result := &model.SyncResult{Status: model.SyncFailed, Error: apiError.Message}
+	switch apiError.StatusCode {
+	case model.ErrBadRequest.StatusCode:
+		result.Status = model.SyncInvalid
+	case model.ErrForbidden.StatusCode:
+		result.Status = model.SyncForbidden
+	case model.ErrNotFound.StatusCode:
+		result.Status = model.SyncNotFound
+	case model.ErrConflict.StatusCode, model.ErrPreconditionFailed.StatusCode:
+		result.Status = model.SyncConflict
+	}
+	return result
+}
+
+func (c *SyncUseCase) apply(ctx context.Context, email string, change *model.SyncChange, refs map[string]uint) *model.SyncResult {
+	if change.Entity == "task_tag" {
+		return c.applyLink(ctx, email, change, refs)
+	}
+	if change.Op == "create" {
+		if len(change.Data) == 0 {
+			return syncInvalid("data is required")
+		}
+		return c.create(ctx, email, change)
+	}
+	id, ok := syncTarget(refs, change.Entity, change.ID, change.Ref)
+	if !ok {
+		return syncInvalid("id or ref of an entity created earlier is required")
+	}
+	switch change.Op {
+	case "update":
+		if len(change.Data) == 0 {
+			return syncInvalid("data is required")
+		}
+		return c.update(ctx, email, change, id)
+	case "delete":
+		return c.delete(ctx, email, change, id)
+	}
+	return syncInvalid(change.Op + " is not an operation on " + change.Entity)
+}
+
+func (c *SyncUseCase) create(ctx context.Context, email string, change *model.SyncChange) *model.SyncResult {
+	switch change.Entity {
+	case "task":
+		request := new(model.CreateTaskRequest)
+		if err := json.Unmarshal(change.Data, request); err != nil {
+			return syncInvalid(err.Error())
+		}
+		request.Email = email
+		response, err := c.TaskUseCase.Create(ctx, request)
+		if err != nil {
+			return syncFailure(err)
+		}
+		return &model.SyncResult{Status: model.SyncApplied, ID: response.ID, Version: response.Version, Data: response}
+	default:
+		request := new(model.CreateTagRequest)
+		if err := json.Unmarshal(change.Data, request); err != nil {
+			return syncInvalid(err.Error())
+		}
+		request.Email = email
+		response, err := c.TagUseCase.Create(ctx, request)
+		if err != nil {
+			return syncFailure(err)
+		}
+		return &model.SyncResult{Status: model.SyncApplied, ID: response.ID, Version: response.Version, Data: response}
+	}
+}
+
+// update applies the change as a merge patch. When it was based on an
+// older version the server keeps its own and returns it.
+func (c *SyncUseCase) update(ctx context.Context, email string, change *model.SyncChange, id uint) *model.SyncResult {
+	switch change.Entity {
+	case "task":
+		request := new(model.PatchTaskRequest)
+		if err := json.Unmarshal(change.Data, request); err != nil {
+			return syncInvalid(err.Error())
+		}
+		request.ID, request.Email, request.IfMatch = strconv.Itoa(int(id)), email, syncIfMatch(change.Version)
+		response, err := c.TaskUseCase.Patch(ctx, request)
+		if err != nil {
+			return c.conflict(ctx, email, change, id, err)
+		}
+		return &model.SyncResult{Status: model.SyncApplied, ID: response.ID, Version: response.Version, Data: response}
+	default:
+		request := new(model.PatchTagRequest)
+		if err := json.Unmarshal(change.Data, request); err != nil {
+			return syncInvalid(err.Error())
+		}
+		request.ID, request.Email, request.IfMatch = strconv.Itoa(int(id)), email, syncIfMatch(change.Version)
+		response, err := c.TagUseCase.Patch(ctx, request)
+		if err != nil {
+			return c.conflict(ctx, email, change, id, err)
+		}
+		return &model.SyncResult{Status: model.SyncApplied, ID: response.ID, Version: response.Version, Data: response}
+	}
+}
+
+// delete removes the entity. Deleting what was already deleted from one of
+// the projects of the user succeeds, so a client can send its deletes
+// again; an entity the user cannot reach is refused, whether or not it
+// exists.
+func (c *SyncUseCase) delete(ctx context.Context, email string, change *model.SyncChange, id uint) *model.SyncResult {
+	var err error
+	switch change.Entity {
+	case "task":
+		err = c.TaskUseCase.Delete(ctx, &model.GetTaskRequest{ID: strconv.Itoa(int(id)), Email: email, IfMatch: syncIfMatch(change.Version)})
+	default:
+		err = c.TagUseCase.Delete(ctx, &model.GetTagRequest{ID: strconv.Itoa(int(id)), Email: email, IfMatch: syncIfMatch(change.Version)})
+	}
+	if errors.Is(err, model.ErrNotFound) {
+		deleted, deletedErr := c.ChangeRepository.Deleted(c.DB.WithContext(ctx), change.Entity, id, email)
+		if deletedErr != nil {
+			c.Log.WithError(deletedErr).Error("error search changes")
+			return syncFailure(model.ErrInternalServer)
+		}
+		if !deleted {
+			return syncFailure(model.ErrForbidden)
+		}
+		err = nil
+	}
+	if err != nil {
+		return c.conflict(ctx, email, change, id, err)
+	}
+	return &model.SyncResult{Status: model.SyncApplied, ID: id}
+}
+
+// conflict reports a failed change. A change based on an older version
+// comes back with the entity as it is on the server.
+func (c *SyncUseCase) conflict(ctx context.Context, email string, change *model.SyncChange, id uint, err error) *model.SyncResult {
+	result := syncFailure(err)
+	if !errors.Is(err, model.ErrPreconditionFailed) {
+		return result
+	}
+	db := c.DB.WithContext(ctx)
+	switch change.Entity {
+	case "task":
+		task := new(entity.Task)
+		if err := c.TaskRepository.FindByEmailAndId(db, task, strconv.Itoa(int(id)), email); err == nil {
+			result.ID, result.Version, result.Data = task.ID, task.Version, converter.TaskToResponse(task)
+		}
+	default:
+		tag := new(entity.Tag)
+		if err := c.TagRepository.FindByEmailAndId(db, tag, strconv.Itoa(int(id)), email); err == nil {
+			result.ID, result.Version, result.Data = tag.ID, tag.Version, converter.TagToResponse(tag)
+		}
+	}
+	return result
+}
+
+// applyLink adds or removes the link between a task and a tag. Adding a
+// link that exists and removing one that does not both succeed.
+func (c *SyncUseCase) applyLink(ctx context.Context, email string, change *model.SyncChange, refs map[string]uint) *model.SyncResult {
+	taskId, ok := syncTarget(refs, "task", change.TaskId, change.TaskRef)
+	if !ok {
+		return syncInvalid("task_id or task_ref of a task created earlier is required")
+	}
+	tagId, ok := syncTarget(refs, "tag", change.TagId, change.TagRef)
+	if !ok {
+		return syncInvalid("tag_id or tag_ref of a tag created earlier is required")
+	}
+	link := model.TaskTagLink{TaskId: taskId, TagId: tagId}
+
+	var err error
+	switch change.Op {
+	case "add":
+		_, err = c.TaskTagUseCase.Create(ctx, &model.CreateTaskTagRequest{TaskId: taskId, TagId: tagId}, email)
+		if errors.Is(err, model.ErrConflict) {
+			err = nil
+		}
+	case "remove":
+		err = c.TaskTagUseCase.Delete(ctx, &model.GetTaskTagForDelete{Email: email, TaskId: taskId, TagId: tagId})
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = nil
+		}
+	default:
+		return syncInvalid(change.Op + " is not an operation on " + change.Entity)
+	}
+	if err != nil {
+		return syncFailure(err)
+	}
+	return &model.SyncResult{Status: model.SyncApplied, Data: link}
+}
@@ -20,16 +20,24 @@ type TagUseCase struct {
 	Log *logrus.Logger
 	Validate *validator.Validate
 	TagRepository *repository.TagRepository
+	ProjectMemberRepository *repository.ProjectMemberRepository
+	Indexer *SearchUseCase
 	Cache *helper.CacheHelper
+	ActivityRepository *repository.ActivityRepository
+	Events *EventUseCase
 }
 
-func NewTagUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, tagRepository *repository.TagRepository, cache *helper.CacheHelper) *TagUseCase {
+func NewTagUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, tagRepository *repository.TagRepository, projectMemberRepository *repository.ProjectMemberRepository, indexer *SearchUseCase, cache *helper.CacheHelper, activityRepository *repository.ActivityRepository, events *EventUseCase) *TagUseCase {
 	return &TagUseCase{
 		DB: db,
 		Log: log,
 		Validate: validate,
 		TagRepository: tagRepository,
+		ProjectMemberRepository: projectMemberRepository,
+		Indexer: indexer,
 		Cache: cache,
+		ActivityRepository: activityRepository,
+		Events: events,
 	}
 }
 
@@ -41,45 +49,57 @@ func (c *TagUseCase) Create(ctx context.Context, request *model.CreateTagRequest
 		c.Log.WithError(err).Error("error validate request body")
 		return nil, model.ErrBadRequest
 	}
+	projectId, err := c.ProjectMemberRepository.ResolveWritable(tx, request.ProjectId, request.Email)
+	if err != nil {
+		c.Log.WithError(err).Error("error resolve project")
+		return nil, model.ErrForbidden
+	}
 	tag := &entity.Tag{
 		Email: request.Email,
+		ProjectId: projectId,
 		Name: request.Name,
 	}
 	if err := c.TagRepository.Create(tx, tag); err != nil {
 		c.Log.WithError(err).Error("error create tag")
 		return nil, model.ErrInternalServer
 	}
+	activity := tagCreatedActivity(tag, request.Email)
+	if err := c.ActivityRepository.Record(tx, activity); err != nil {
+		c.Log.WithError(err).Error("error record activity")
+		return nil, model.ErrInternalServer
+	}
 	if err := tx.Commit().Error; err != nil {
 		c.Log.WithError(err).Error("error create tag")
 		return nil, model.ErrInternalServer
 	}
+	c.Events.Publish(ctx, activity)
 
 	return converter.TagToResponse(tag), nil
 }
 
-func (c *TagUseCase) Search(ctx context.Context, request *model.SearchTagRequest) ([]model.TagResponse, int64, error) {
+func (c *TagUseCase) Search(ctx context.Context, request *model.SearchTagRequest) ([]model.TagResponse, *model.PageMetadata, error) {
 	tx := c.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	if err := c.Validate.Struct(request); err != nil {
 		c.Log.WithError(err).Error("error validate request body")
-		return nil, 0, model.ErrBadRequest
+		return nil, nil, model.ErrBadRequest
 	}
-	tags, total, err := c.TagRepository.Search(tx, request)
+	tags, paging, err := c.TagRepository.Search(tx, request)
 	if err != nil {
 		c.Log.WithError(err).Error("error search tag")
-		return nil, 0, model.ErrNotFound
+		return nil, nil, pageError(err, model.ErrNotFound)
 	}
 	if err := tx.Commit().Error; err != nil {
 		c.Log.WithError(err).Error("error search tag")
-		return nil, 0, model.ErrInternalServer
+		return nil, nil, model.ErrInternalServer
 	}
 	responses := make([]model.TagResponse, len(tags))
 	for i, tag := range tags {
 		tag.Email = ""
 		responses[i] = *converter.TagToResponse(&tag)
 	}	
-	return responses, total, nil
+	return responses, paging, nil
 }
 
 func (c *TagUseCase) Get(ctx context.Context, request *model.GetTagRequest) (*model.TagResponse, error) {
@@ -113,36 +133,86 @@ func (c *TagUseCase) Get(ctx context.Context, request *model.GetTagRequest) (*mo
 	return &tagResponse, nil
 }
 
+// Update replaces the tag with the request.
 func (c *TagUseCase) Update(ctx context.Context, request *model.UpdateTagRequest) (*model.TagResponse, error) {
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+
+	return c.edit(ctx, request.ID, request.Email, request.IfMatch, func(tag *entity.Tag) {
+		tag.Name = request.Name
+	})
+}
+
+// Patch applies a JSON merge patch to the tag.
+func (c *TagUseCase) Patch(ctx context.Context, request *model.PatchTagRequest) (*model.TagResponse, error) {
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	if request.Name.Null {
+		return nil, model.NewApiError(model.ErrBadRequest.StatusCode, "name cannot be null")
+	}
+
+	return c.edit(ctx, request.ID, request.Email, request.IfMatch, func(tag *entity.Tag) {
+		if request.Name.Set {
+			tag.Name = request.Name.Value
+		}
+	})
+}
+
+// edit loads the tag, lets change modify it and stores the result. A
+// non-empty ifMatch must name the version of the tag.
+func (c *TagUseCase) edit(ctx context.Context, id string, email string, ifMatch string, change func(tag *entity.Tag)) (*model.TagResponse, error) {
 	tx := c.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	tag := new(entity.Tag)
-	if err := c.TagRepository.FindByEmailAndId(tx, tag, request.ID, request.Email); err != nil {
+	if err := c.TagRepository.FindByEmailAndId(tx, tag, id, email); err != nil {
 		c.Log.WithError(err).Error("error search tag")
 		return nil, model.ErrNotFound
 	}
-	if err := c.Validate.Struct(request); err != nil {
-		c.Log.WithError(err).Error("error validate request query")
-		return nil, model.ErrBadRequest
+	if err := checkWriteAccess(tx, c.ProjectMemberRepository, tag.ProjectId, email); err != nil {
+		c.Log.WithError(err).Error("error update tag")
+		return nil, err
 	}
-	if request.Name != "" {
-		tag.Name = request.Name
+	if err := checkVersion(ifMatch, tag.Version); err != nil {
+		c.Log.WithError(err).Error("error update tag")
+		return nil, err
 	}
-	
+	before := *tag
+	change(tag)
+
 	if err := c.TagRepository.Update(tx, tag); err != nil {
 		c.Log.WithError(err).Error("error update tag")
+		return nil, writeError(err)
+	}
+	var activities []*entity.Activity
+	if before.Name != tag.Name {
+		activities = append(activities, tagRenamedActivity(&before, tag, email))
+	}
+	if err := c.ActivityRepository.Record(tx, activities...); err != nil {
+		c.Log.WithError(err).Error("error record activity")
+		return nil, model.ErrInternalServer
+	}
+	taskIds, err := c.TagRepository.TaskIds(tx, tag.ID)
+	if err != nil {
+		c.Log.WithError(err).Error("error search tag tasks")
 		return nil, model.ErrInternalServer
 	}
 	if err := tx.Commit().Error; err != nil {
 		c.Log.WithError(err).Error("error update tag")
 		return nil, model.ErrInternalServer
 	}
+	c.Events.Publish(ctx, activities...)
 
+	c.invalidateCache(ctx, tag.ProjectId, id)
+	c.Indexer.Refresh(ctx, taskIds...)
 	tagResponse := converter.TagToResponse(tag)
 	tagResponseJSON, _ := json.Marshal(tagResponse)
-	c.Cache.Set(ctx, "tags:"+request.ID+"email:"+request.Email, tagResponseJSON, 30*time.Minute)
-	
+	c.Cache.Set(ctx, "tags:"+id+"email:"+email, tagResponseJSON, 30*time.Minute)
+
 	return tagResponse, nil
 }
 
@@ -160,9 +230,27 @@ func (c *TagUseCase) Delete(ctx context.Context, request *model.GetTagRequest) e
 		c.Log.WithError(err).Error("error search tag")
 		return model.ErrNotFound
 	}
+	if err := checkWriteAccess(tx, c.ProjectMemberRepository, tag.ProjectId, request.Email); err != nil {
+		c.Log.WithError(err).Error("error delete tag")
+		return err
+	}
+	if err := checkVersion(request.IfMatch, tag.Version); err != nil {
+		c.Log.WithError(err).Error("error delete tag")
+		return err
+	}
+	taskIds, err := c.TagRepository.TaskIds(tx, tag.ID)
+	if err != nil {
+		c.Log.WithError(err).Error("error search tag tasks")
+		return model.ErrInternalServer
+	}
 
 	if err := c.TagRepository.Delete(tx, tag); err != nil {
 		c.Log.WithError(err).Error("error delete tag")
+		return writeError(err)
+	}
+	activity := tagDeletedActivity(tag, request.Email)
+	if err := c.ActivityRepository.Record(tx, activity); err != nil {
+		c.Log.WithError(err).Error("error record activity")
 		return model.ErrInternalServer
 	}
 
@@ -170,8 +258,19 @@ func (c *TagUseCase) Delete(ctx context.Context, request *model.GetTagRequest) e
 		c.Log.WithError(err).Error("error delete tag")
 		return model.ErrInternalServer
 	}
+	c.Events.Publish(ctx, activity)
 
-	c.Cache.Delete(ctx, "tags:"+request.ID+"email:"+request.Email)
+	c.invalidateCache(ctx, tag.ProjectId, request.ID)
+	c.Indexer.Refresh(ctx, taskIds...)
 
 	return nil
+}
+
+func (c *TagUseCase) invalidateCache(ctx context.Context, projectId uint, tagId string) {
+	emails, err := c.ProjectMemberRepository.Emails(c.DB.WithContext(ctx), projectId)
+	if err != nil {
+		c.Log.WithError(err).Warn("error load project members for cache invalidation")
+		return
+	}
+	c.Cache.Delete(ctx, memberCacheKeys(emails, "tags:"+tagId, "task_tags:"+tagId, "stats:")...)
 }
\ No newline at end of file
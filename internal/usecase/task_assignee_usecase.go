@@ -0,0 +1,306 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"strconv"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type TaskAssigneeUseCase struct {
+	DB                      *gorm.DB
+	Log                     *logrus.Logger
+	Validate                *validator.Validate
+	TaskRepository          *repository.TaskRepository
+	TaskAssigneeRepository  *repository.TaskAssigneeRepository
+	TaskWatcherRepository   *repository.TaskWatcherRepository
+	ProjectMemberRepository *repository.ProjectMemberRepository
+	Notifier                helper.Notifier
+	Cache                   *helper.CacheHelper
+	ActivityRepository      *repository.ActivityRepository
+	Events                  *EventUseCase
+}
+
+func NewTaskAssigneeUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, taskRepository *repository.TaskRepository, taskAssigneeRepository *repository.TaskAssigneeRepository, taskWatcherRepository *repository.TaskWatcherRepository, projectMemberRepository *repository.ProjectMemberRepository, notifier helper.Notifier, cache *helper.CacheHelper, activityRepository *repository.ActivityRepository, events *EventUseCase) *TaskAssigneeUseCase {
+	return &TaskAssigneeUseCase{
+		DB:                      db,
+		Log:                     log,
+		Validate:                validate,
+		TaskRepository:          taskRepository,
+		TaskAssigneeRepository:  taskAssigneeRepository,
+		TaskWatcherRepository:   taskWatcherRepository,
+		ProjectMemberRepository: projectMemberRepository,
+		Notifier:                notifier,
+		Cache:                   cache,
+		ActivityRepository:      activityRepository,
+		Events:                  events,
+	}
+}
+
+func (c *TaskAssigneeUseCase) Update(ctx context.Context, request *model.UpdateTaskAssigneesRequest) ([]string, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	task := new(entity.Task)
+	if err := c.TaskRepository.FindByEmailAndId(tx, task, request.ID, request.Email); err != nil {
+		c.Log.WithError(err).Error("error search task")
+		return nil, model.ErrNotFound
+	}
+	if err := checkWriteAccess(tx, c.ProjectMemberRepository, task.ProjectId, request.Email); err != nil {
+		c.Log.WithError(err).Error("error update task assignees")
+		return nil, err
+	}
+
+	assignees := uniqueEmails(request.Assignees)
+	if err := checkMembers(tx, c.ProjectMemberRepository, task.ProjectId, assignees); err != nil {
+		c.Log.WithError(err).Error("error validate task assignees")
+		return nil, err
+	}
+	before, err := c.TaskAssigneeRepository.FindEmailsByTask(tx, task.ID)
+	if err != nil {
+		c.Log.WithError(err).Error("error search task assignees")
+		return nil, model.ErrInternalServer
+	}
+	if err := c.TaskAssigneeRepository.Replace(tx, task.ID, assignees); err != nil {
+		c.Log.WithError(err).Error("error update task assignees")
+		return nil, model.ErrInternalServer
+	}
+	if err := c.TaskRepository.Touch(tx, task); err != nil {
+		c.Log.WithError(err).Error("error update task assignees")
+		return nil, model.ErrInternalServer
+	}
+	var activities []*entity.Activity
+	if !sameEmails(before, assignees) {
+		activities = taskEditActivities(task, task, request.Email, "assignees")
+		if err := c.ActivityRepository.Record(tx, activities...); err != nil {
+			c.Log.WithError(err).Error("error record activity")
+			return nil, model.ErrInternalServer
+		}
+	}
+	watchers, err := c.TaskWatcherRepository.FindEmailsByTask(tx, task.ID)
+	if err != nil {
+		c.Log.WithError(err).Error("error search task watchers")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error update task assignees")
+		return nil, model.ErrInternalServer
+	}
+
+	c.invalidateCache(ctx, task)
+	c.Events.Publish(ctx, activities...)
+	c.Notifier.Notify(ctx, assignmentNotifications(task, request.Email, before, assignees, watchers)...)
+	return assignees, nil
+}
+
+func (c *TaskAssigneeUseCase) Watch(ctx context.Context, request *model.TaskWatcherRequest) ([]string, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	task, err := c.findForWatcher(tx, request)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMembers(tx, c.ProjectMemberRepository, task.ProjectId, []string{request.WatcherEmail}); err != nil {
+		c.Log.WithError(err).Error("error validate task watcher")
+		return nil, err
+	}
+	watcher := new(entity.TaskWatcher)
+	if err := c.TaskWatcherRepository.FindByTaskAndEmail(tx, watcher, task.ID, request.WatcherEmail); err == nil {
+		return nil, model.ErrConflict
+	}
+	watcher = &entity.TaskWatcher{TaskId: task.ID, Email: request.WatcherEmail}
+	if err := c.TaskWatcherRepository.Create(tx, watcher); err != nil {
+		c.Log.WithError(err).Error("error create task watcher")
+		return nil, model.ErrInternalServer
+	}
+	if err := c.TaskRepository.Touch(tx, task); err != nil {
+		c.Log.WithError(err).Error("error create task watcher")
+		return nil, model.ErrInternalServer
+	}
+	activities, err := c.recordWatchers(tx, task, request.Email)
+	if err != nil {
+		return nil, err
+	}
+	watchers, err := c.TaskWatcherRepository.FindEmailsByTask(tx, task.ID)
+	if err != nil {
+		c.Log.WithError(err).Error("error search task watchers")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error create task watcher")
+		return nil, model.ErrInternalServer
+	}
+
+	c.invalidateCache(ctx, task)
+	c.Events.Publish(ctx, activities...)
+	return watchers, nil
+}
+
+func (c *TaskAssigneeUseCase) Unwatch(ctx context.Context, request *model.TaskWatcherRequest) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return model.ErrBadRequest
+	}
+	task, err := c.findForWatcher(tx, request)
+	if err != nil {
+		return err
+	}
+	watcher := new(entity.TaskWatcher)
+	if err := c.TaskWatcherRepository.FindByTaskAndEmail(tx, watcher, task.ID, request.WatcherEmail); err != nil {
+		c.Log.WithError(err).Error("error search task watcher")
+		return model.ErrNotFound
+	}
+	if err := c.TaskWatcherRepository.Delete(tx, watcher); err != nil {
+		c.Log.WithError(err).Error("error delete task watcher")
+		return model.ErrInternalServer
+	}
+	if err := c.TaskRepository.Touch(tx, task); err != nil {
+		c.Log.WithError(err).Error("error delete task watcher")
+		return model.ErrInternalServer
+	}
+	activities, err := c.recordWatchers(tx, task, request.Email)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error delete task watcher")
+		return model.ErrInternalServer
+	}
+
+	c.invalidateCache(ctx, task)
+	c.Events.Publish(ctx, activities...)
+	return nil
+}
+
+// recordWatchers records a change of the watchers of the task as an update
+// of it, which is how they show up in its response.
+func (c *TaskAssigneeUseCase) recordWatchers(tx *gorm.DB, task *entity.Task, actor string) ([]*entity.Activity, error) {
+	activities := taskEditActivities(task, task, actor, "watchers")
+	if err := c.ActivityRepository.Record(tx, activities...); err != nil {
+		c.Log.WithError(err).Error("error record activity")
+		return nil, model.ErrInternalServer
+	}
+	return activities, nil
+}
+
+// findForWatcher loads the task and lets members manage their own watch,
+// while changing somebody else's requires write access.
+func (c *TaskAssigneeUseCase) findForWatcher(tx *gorm.DB, request *model.TaskWatcherRequest) (*entity.Task, error) {
+	task := new(entity.Task)
+	if err := c.TaskRepository.FindByEmailAndId(tx, task, request.ID, request.Email); err != nil {
+		c.Log.WithError(err).Error("error search task")
+		return nil, model.ErrNotFound
+	}
+	if request.WatcherEmail != request.Email {
+		if err := checkWriteAccess(tx, c.ProjectMemberRepository, task.ProjectId, request.Email); err != nil {
+			return nil, err
+		}
+	}
+	return task, nil
+}
+
+func (c *TaskAssigneeUseCase) invalidateCache(ctx context.Context, task *entity.Task) {
+	emails, err := c.ProjectMemberRepository.Emails(c.DB.WithContext(ctx), task.ProjectId)
+	if err != nil {
+		c.Log.WithError(err).Warn("error load project members for cache invalidation")
+		return
+	}
+	c.Cache.Delete(ctx, memberCacheKeys(emails, "task:"+strconv.Itoa(int(task.ID)))...)
+}
+
+// checkMembers makes sure every email belongs to the project.
+func checkMembers(tx *gorm.DB, members *repository.ProjectMemberRepository, projectId uint, emails []string) error {
+	if len(emails) == 0 {
+		return nil
+	}
+	count, err := members.CountMembers(tx, projectId, emails)
+	if err != nil {
+		return model.ErrInternalServer
+	}
+	if count != int64(len(emails)) {
+		return model.ErrBadRequest
+	}
+	return nil
+}
+
+// sameEmails reports whether both lists hold the same emails, in any
+// order.
+func sameEmails(a []string, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
+
+func uniqueEmails(emails []string) []string {
+	result := make([]string, 0, len(emails))
+	for _, email := range emails {
+		if !slices.Contains(result, email) {
+			result = append(result, email)
+		}
+	}
+	return result
+}
+
+// assignmentNotifications tells new assignees about the task, tells removed
+// assignees they were taken off it and lets watchers know it changed hands.
+// The actor is never notified about their own change.
+func assignmentNotifications(task *entity.Task, actor string, before []string, after []string, watchers []string) []model.Notification {
+	data := map[string]any{"task_id": task.ID, "project_id": task.ProjectId, "actor": actor, "assignees": after}
+	var notifications []model.Notification
+	for _, email := range after {
+		if email != actor && !slices.Contains(before, email) {
+			notifications = append(notifications, model.Notification{
+				Email: email,
+				Type:  model.NotificationTaskAssigned,
+				Title: "You were assigned to " + task.Title,
+				Body:  actor + " assigned you to the task " + task.Title,
+				Data:  data,
+			})
+		}
+	}
+	for _, email := range before {
+		if email != actor && !slices.Contains(after, email) {
+			notifications = append(notifications, model.Notification{
+				Email: email,
+				Type:  model.NotificationTaskUnassigned,
+				Title: "You were unassigned from " + task.Title,
+				Body:  actor + " removed you from the task " + task.Title,
+				Data:  data,
+			})
+		}
+	}
+	if len(before) > 0 && !sameEmails(before, after) {
+		for _, email := range watchers {
+			if email != actor && !slices.Contains(after, email) && !slices.Contains(before, email) {
+				notifications = append(notifications, model.Notification{
+					Email: email,
+					Type:  model.NotificationTaskReassigned,
+					Title: task.Title + " was reassigned",
+					Body:  actor + " changed the assignees of " + task.Title,
+					Data:  data,
+				})
+			}
+		}
+	}
+	return notifications
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+)
+
+func TestSameEmails(t *testing.T) {
+	tests := []struct {
+		a    []string
+		b    []string
+		want bool
+	}{
+		{a: nil, b: []string{}, want: true},
+		{a: []string{"ann@example.com", "bob@example.com"}, b: []string{"bob@example.com", "ann@example.com"}, want: true},
+		{a: []string{"ann@example.com"}, b: []string{"ann@example.com", "ann@example.com"}, want: true},
+		{a: []string{"ann@example.com"}, b: []string{"bob@example.com"}, want: false},
+		{a: []string{"ann@example.com"}, b: []string{"ann@example.com", "bob@example.com"}, want: false},
+	}
+	for _, test := range tests {
+		if got := sameEmails(test.a, test.b); got != test.want {
+			t.Errorf("sameEmails(%v, %v) = %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestAssignmentNotificationsReordered(t *testing.T) {
+	task := &entity.Task{ID: 1, ProjectId: 2, Title: "Ship it"}
+	before := []string{"ann@example.com", "bob@example.com"}
+	after := []string{"bob@example.com", "ann@example.com"}
+
+	notifications := assignmentNotifications(task, "ann@example.com", before, after, []string{"cid@example.com"})
+	if len(notifications) != 0 {
+		t.Errorf("reordering the assignees sent %+v, want nothing", notifications)
+	}
+
+	notifications = assignmentNotifications(task, "ann@example.com", before, []string{"bob@example.com"}, []string{"cid@example.com"})
+	var types []string
+	for _, notification := range notifications {
+		types = append(types, notification.Email+" "+notification.Type)
+	}
+	want := []string{"cid@example.com " + model.NotificationTaskReassigned}
+	if len(types) != len(want) || types[0] != want[0] {
+		t.Errorf("notifications = %v, want %v", types, want)
+	}
+}
+
+func TestWatcherActivity(t *testing.T) {
+	task := &entity.Task{ID: 1, ProjectId: 2, Title: "Ship it", Status: "pending"}
+	activities := taskEditActivities(task, task, "ann@example.com", "watchers")
+	if len(activities) != 1 || activities[0].Type != model.ActivityTaskUpdated || activities[0].Data != `{"fields":["watchers"]}` {
+		t.Errorf("activities = %+v, want one update of the watchers", activities)
+	}
+}
@@ -0,0 +1,320 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// batchTaskLimit is the largest number of tasks a batch may change.
+const batchTaskLimit = 500
+
+// TaskBatchUseCase changes many tasks in one transaction.
+type TaskBatchUseCase struct {
+	DB                      *gorm.DB
+	Log                     *logrus.Logger
+	Validate                *validator.Validate
+	TaskRepository          *repository.TaskRepository
+	TagRepository           *repository.TagRepository
+	TaskTagRepository       *repository.TaskTagRepository
+	TaskAssigneeRepository  *repository.TaskAssigneeRepository
+	ProjectMemberRepository *repository.ProjectMemberRepository
+	Indexer                 *SearchUseCase
+	Cache                   *helper.CacheHelper
+	ActivityRepository      *repository.ActivityRepository
+	Events                  *EventUseCase
+}
+
+func NewTaskBatchUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, taskRepository *repository.TaskRepository, tagRepository *repository.TagRepository, taskTagRepository *repository.TaskTagRepository, taskAssigneeRepository *repository.TaskAssigneeRepository, projectMemberRepository *repository.ProjectMemberRepository, indexer *SearchUseCase, cache *helper.CacheHelper, activityRepository *repository.ActivityRepository, events *EventUseCase) *TaskBatchUseCase {
+	return &TaskBatchUseCase{
+		DB:                      db,
+		Log:                     log,
+		Validate:                validate,
+		TaskRepository:          taskRepository,
+		TagRepository:           tagRepository,
+		TaskTagRepository:       taskTagRepository,
+		TaskAssigneeRepository:  taskAssigneeRepository,
+		ProjectMemberRepository: projectMemberRepository,
+		Indexer:                 indexer,
+		Cache:                   cache,
+		ActivityRepository:      activityRepository,
+		Events:                  events,
+	}
+}
+
+// Apply changes every selected task. Tasks that cannot be changed, because
+// they are missing, not writable or the change is invalid for them, are
+// reported as failed and left alone; the others are committed together.
+func (c *TaskBatchUseCase) Apply(ctx context.Context, request *model.BatchTaskRequest) (*model.BatchTaskResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	changes := request.Status != "" || request.Priority != "" || request.DueDate != "" || len(request.AddTags) > 0 || len(request.RemoveTags) > 0
+	if request.Delete && changes {
+		return nil, model.NewApiError(model.ErrBadRequest.StatusCode, "delete cannot be combined with other changes")
+	}
+	if !request.Delete && !changes {
+		return nil, model.NewApiError(model.ErrBadRequest.StatusCode, "nothing to change")
+	}
+
+	ids, tasks, err := c.selectTasks(tx, request)
+	if err != nil {
+		return nil, err
+	}
+	tags := map[uint]entity.Tag{}
+	if tagIds := append(append([]uint{}, request.AddTags...), request.RemoveTags...); len(tagIds) > 0 {
+		found, err := c.TagRepository.FindByIds(tx, tagIds)
+		if err != nil {
+			c.Log.WithError(err).Error("error search tags")
+			return nil, model.ErrInternalServer
+		}
+		for _, tag := range found {
+			tags[tag.ID] = tag
+		}
+	}
+
+	batch := &taskBatch{useCase: c, tx: tx, request: request, tags: tags, access: map[uint]error{}}
+	response := &model.BatchTaskResponse{Results: make([]model.BatchTaskResult, 0, len(ids))}
+	for _, id := range ids {
+		result := model.BatchTaskResult{ID: id, Status: "updated"}
+		if request.Delete {
+			result.Status = "deleted"
+		}
+		task, ok := tasks[id]
+		if !ok {
+			result.Status, result.Error = "failed", "task not found"
+		} else if err := batch.apply(task); err != nil {
+			if errors.Is(err, repository.ErrStaleVersion) {
+				err = model.ErrPreconditionFailed
+			}
+			var apiErr *model.ApiError
+			if !errors.As(err, &apiErr) || apiErr == model.ErrInternalServer {
+				c.Log.WithError(err).Error("error batch update task")
+				return nil, model.ErrInternalServer
+			}
+			result.Status, result.Error = "failed", apiErr.Message
+		}
+		if result.Status == "failed" {
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error batch update task")
+		return nil, model.ErrInternalServer
+	}
+	c.Events.Publish(ctx, batch.activities...)
+
+	prefixes := append(idKeys("task:", batch.taskIds), idKeys("task_tags:", batch.tagIds())...)
+	prefixes = append(prefixes, "stats:")
+	if err := forgetProjectCache(ctx, c.DB, c.ProjectMemberRepository, c.Cache, batch.projectIds(), prefixes...); err != nil {
+		c.Log.WithError(err).Warn("error load project members for cache invalidation")
+	}
+	if request.Delete {
+		c.Indexer.Forget(ctx, batch.taskIds...)
+	} else {
+		c.Indexer.Refresh(ctx, batch.taskIds...)
+	}
+	return response, nil
+}
+
+// selectTasks loads the tasks picked by the request. It returns the ids in
+// the order results are reported together with the tasks found by id.
+func (c *TaskBatchUseCase) selectTasks(tx *gorm.DB, request *model.BatchTaskRequest) ([]uint, map[uint]*entity.Task, error) {
+	var found []entity.Task
+	var ids []uint
+	if len(request.Ids) > 0 {
+		seen := map[uint]bool{}
+		for _, id := range request.Ids {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		tasks, err := c.TaskRepository.FindAccessible(tx, ids, request.Email)
+		if err != nil {
+			c.Log.WithError(err).Error("error search task")
+			return nil, nil, model.ErrInternalServer
+		}
+		found = tasks
+	} else {
+		expr, err := parseFilter(request.Query)
+		if err != nil {
+			c.Log.WithError(err).Error("error parse task filter")
+			return nil, nil, err
+		}
+		search := &model.SearchTaskRequest{Email: request.Email, ProjectId: request.ProjectId, Timezone: request.Timezone}
+		tasks, err := c.TaskRepository.FindMatching(tx, search, expr, batchTaskLimit+1)
+		if err != nil {
+			c.Log.WithError(err).Error("error search task")
+			return nil, nil, model.ErrInternalServer
+		}
+		if len(tasks) > batchTaskLimit {
+			return nil, nil, model.NewApiError(model.ErrBadRequest.StatusCode, fmt.Sprintf("q matches more than %d tasks", batchTaskLimit))
+		}
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+		found = tasks
+	}
+
+	tasks := make(map[uint]*entity.Task, len(found))
+	for i := range found {
+		tasks[found[i].ID] = &found[i]
+	}
+	return ids, tasks, nil
+}
+
+// taskBatch applies the changes of a request to one task at a time and
+// remembers what it touched for cache invalidation and events.
+type taskBatch struct {
+	useCase    *TaskBatchUseCase
+	tx         *gorm.DB
+	request    *model.BatchTaskRequest
+	tags       map[uint]entity.Tag
+	access     map[uint]error
+	taskIds    []uint
+	projects   []uint
+	touched    map[uint]bool
+	activities []*entity.Activity
+}
+
+func (b *taskBatch) apply(task *entity.Task) error {
+	c, request := b.useCase, b.request
+	if err := b.checkAccess(task); err != nil {
+		return err
+	}
+
+	if request.Delete {
+		if err := c.TaskRepository.Delete(b.tx, task); err != nil {
+			return err
+		}
+		activity := taskDeletedActivity(task, request.Email)
+		if err := c.ActivityRepository.Record(b.tx, activity); err != nil {
+			return err
+		}
+		b.activities = append(b.activities, activity)
+		b.record(task)
+		return nil
+	}
+
+	before := *task
+
+	if request.Status != "" {
+		task.Status = request.Status
+	}
+	if request.Priority != "" {
+		task.Priority = request.Priority
+	}
+	if request.DueDate == "none" {
+		task.DueDate, task.DueTime = nil, nil
+	} else if request.DueDate != "" {
+		due, _ := time.Parse(time.DateOnly, request.DueDate)
+		task.DueDate = &due
+	}
+	if err := checkSchedule(task.StartDate, task.DueDate); err != nil {
+		return err
+	}
+	for _, id := range append(append([]uint{}, request.AddTags...), request.RemoveTags...) {
+		if tag, ok := b.tags[id]; !ok || tag.ProjectId != task.ProjectId {
+			return model.NewApiError(model.ErrBadRequest.StatusCode, fmt.Sprintf("tag %d does not belong to the project of the task", id))
+		}
+	}
+
+	// the loaded tags must not be written back over the ones detached below
+	if err := c.TaskRepository.Update(b.tx.Omit(clause.Associations), task); err != nil {
+		return err
+	}
+	if err := c.TaskTagRepository.Attach(b.tx, task.ID, request.AddTags); err != nil {
+		return err
+	}
+	if len(request.RemoveTags) > 0 {
+		if err := c.TaskTagRepository.Detach(b.tx, task.ID, request.RemoveTags); err != nil {
+			return err
+		}
+	}
+	var extra []string
+	if len(request.AddTags) > 0 || len(request.RemoveTags) > 0 {
+		extra = append(extra, "tags")
+	}
+	activities := taskEditActivities(&before, task, request.Email, extra...)
+	if err := c.ActivityRepository.Record(b.tx, activities...); err != nil {
+		return err
+	}
+	b.activities = append(b.activities, activities...)
+	b.record(task)
+	return nil
+}
+
+// checkAccess requires write access to the project, except for status only
+// changes which assignees may make too. Write access is checked once per
+// project.
+func (b *taskBatch) checkAccess(task *entity.Task) error {
+	c, request := b.useCase, b.request
+	statusOnly := request.Status != "" && request.Priority == "" && request.DueDate == "" && len(request.AddTags) == 0 && len(request.RemoveTags) == 0
+	err, checked := b.access[task.ProjectId]
+	if !checked {
+		err = checkWriteAccess(b.tx, c.ProjectMemberRepository, task.ProjectId, request.Email)
+		b.access[task.ProjectId] = err
+	}
+	if err == model.ErrForbidden && statusOnly {
+		return checkStatusAccess(b.tx, c.ProjectMemberRepository, c.TaskAssigneeRepository, task, request.Email)
+	}
+	return err
+}
+
+func (b *taskBatch) record(task *entity.Task) {
+	b.taskIds = append(b.taskIds, task.ID)
+	b.projects = append(b.projects, task.ProjectId)
+	if b.touched == nil {
+		b.touched = map[uint]bool{}
+	}
+	for _, tag := range task.Tags {
+		b.touched[tag.ID] = true
+	}
+	for _, id := range b.request.AddTags {
+		b.touched[id] = true
+	}
+	for _, id := range b.request.RemoveTags {
+		b.touched[id] = true
+	}
+}
+
+// projectIds returns the distinct projects of the changed tasks.
+func (b *taskBatch) projectIds() []uint {
+	seen := map[uint]bool{}
+	var ids []uint
+	for _, id := range b.projects {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// tagIds returns the tags whose task lists changed.
+func (b *taskBatch) tagIds() []uint {
+	ids := make([]uint, 0, len(b.touched))
+	for id := range b.touched {
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
+	"github.com/abdisetiakawan/go-clean-arch/internal/repository"
+	"gorm.io/gorm"
+)
+
+// errUnknownTag is returned by tagResolver when a tag is missing and may
+// not be created.
+var errUnknownTag = errors.New("unknown tag")
+
+// tagResolver finds tags of a project by name and, when create is set,
+// creates the missing ones. It remembers what it already looked up.
+type tagResolver struct {
+	repository *repository.TagRepository
+	projectId  uint
+	email      string
+	create     bool
+	ids        map[string]uint
+}
+
+func newTagResolver(tagRepository *repository.TagRepository, projectId uint, email string, create bool) *tagResolver {
+	return &tagResolver{repository: tagRepository, projectId: projectId, email: email, create: create, ids: map[string]uint{}}
+}
+
+func (r *tagResolver) resolve(tx *gorm.DB, names []string) ([]uint, error) {
+	ids := make([]uint, 0, len(names))
+	for _, name := range names {
+		name = truncate(strings.TrimSpace(name), 50)
+		key := strings.ToLower(name)
+		if name == "" {
+			continue
+		}
+		if id, ok := r.ids[key]; ok {
+			ids = append(ids, id)
+			continue
+		}
+		tag := new(entity.Tag)
+		err := r.repository.FindByName(tx, tag, r.projectId, name)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if !r.create {
+				return nil, fmt.Errorf("%w %q", errUnknownTag, name)
+			}
+			tag = &entity.Tag{Email: r.email, ProjectId: r.projectId, Name: name}
+			err = r.repository.Create(tx, tag)
+		}
+		if err != nil {
+			return nil, err
+		}
+		r.ids[key] = tag.ID
+		ids = append(ids, tag.ID)
+	}
+	return ids, nil
+}
+
+// columnEnds hands out positions at the end of the status columns of a
+// project for tasks created in bulk. It loads the last position of a
+// column the first time it is needed.
+type columnEnds struct {
+	repository *repository.TaskRepository
+	projectId  uint
+	last       map[string]string
+}
+
+func newColumnEnds(taskRepository *repository.TaskRepository, projectId uint) *columnEnds {
+	return &columnEnds{repository: taskRepository, projectId: projectId, last: map[string]string{}}
+}
+
+func (e *columnEnds) next(tx *gorm.DB, status string) (string, error) {
+	last, ok := e.last[status]
+	if !ok {
+		var err error
+		if last, err = e.repository.MaxPosition(tx, e.projectId, status); err != nil {
+			return "", err
+		}
+	}
+	e.last[status] = helper.RankBetween(last, "")
+	return e.last[status], nil
+}
+
+// forgetCachedTasks drops the cached copies of tasks changed in bulk, and
+// the stats they count towards, for every member of the project.
+func forgetCachedTasks(ctx context.Context, db *gorm.DB, members *repository.ProjectMemberRepository, cache *helper.CacheHelper, projectId uint, taskIds []uint) error {
+	return forgetProjectCache(ctx, db, members, cache, []uint{projectId}, append(idKeys("task:", taskIds), "stats:")...)
+}
+
+// idKeys builds a cache key prefix such as "task:42" for every id.
+func idKeys(prefix string, ids []uint) []string {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = prefix + strconv.FormatUint(uint64(id), 10)
+	}
+	return keys
+}
+
+func truncate(value string, length int) string {
+	runes := []rune(value)
+	if len(runes) <= length {
+		return value
+	}
+	return string(runes[:length])
+}
@@ -21,16 +21,24 @@ type TaskTagUseCase struct {
 	Log           *logrus.Logger
 	Validate      *validator.Validate
 	TaskTagRepository *repository.TaskTagRepository
+	ProjectMemberRepository *repository.ProjectMemberRepository
+	Indexer *SearchUseCase
 	Cache *helper.CacheHelper
+	ActivityRepository *repository.ActivityRepository
+	Events *EventUseCase
 }
 
-func NewTaskTagUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, taskTagRepository *repository.TaskTagRepository, cache *helper.CacheHelper) *TaskTagUseCase {
+func NewTaskTagUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, taskTagRepository *repository.TaskTagRepository, projectMemberRepository *repository.ProjectMemberRepository, indexer *SearchUseCase, cache *helper.CacheHelper, activityRepository *repository.ActivityRepository, events *EventUseCase) *TaskTagUseCase {
 	return &TaskTagUseCase{
 		DB:            db,
 		Log:           log,
 		Validate:      validate,
 		TaskTagRepository: taskTagRepository,
+		ProjectMemberRepository: projectMemberRepository,
+		Indexer: indexer,
 		Cache: cache,
+		ActivityRepository: activityRepository,
+		Events: events,
 	}
 }
 
@@ -58,68 +66,104 @@ func (c *TaskTagUseCase) Create(ctx context.Context, request *model.CreateTaskTa
         c.Log.WithError(err).Error("error create task tag")
         return nil, err
     }
+    activity, err := c.recordActivity(tx, taskTag, email, true)
+    if err != nil {
+        return nil, err
+    }
     
     if err := tx.Commit().Error; err != nil {
         c.Log.WithError(err).Error("error create task tag")
         return nil, model.ErrInternalServer
     }
+    c.Events.Publish(ctx, activity)
 
+    c.invalidateCache(ctx, taskTag.TaskId, taskTag.TagId)
+    c.Indexer.Refresh(ctx, taskTag.TaskId)
     return converter.TaskTagToResponse(taskTag), nil
 }
 
 
-func (c *TaskTagUseCase) Search(ctx context.Context, request *model.SearchTaskTagRequest) ([]model.TaskTagResult, int64, error) {
+// Search lists the tasks that have tags, each once with all its tags.
+func (c *TaskTagUseCase) Search(ctx context.Context, request *model.SearchTaskTagRequest) ([]model.TaskWithTagsResponse, *model.PageMetadata, error) {
 	tx := c.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	if err := c.Validate.Struct(request); err != nil {
 		c.Log.WithError(err).Error("error validate request query")
-		return nil, 0, model.ErrBadRequest
+		return nil, nil, model.ErrBadRequest
 	}
 
-	taskTags, total, err := c.TaskTagRepository.SearchTaskTag(tx, request)
+	tasks, paging, err := c.TaskTagRepository.SearchTaskTag(tx, request)
 	if err != nil {
 		c.Log.WithError(err).Error("error search task tag")
-		return nil, 0, err
+		return nil, nil, pageError(err, model.ErrInternalServer)
+	}
+	taskIds := make([]uint, len(tasks))
+	for i, task := range tasks {
+		taskIds[i] = task.ID
+	}
+	tags, err := tagsByTask(tx, c.TaskTagRepository, taskIds)
+	if err != nil {
+		c.Log.WithError(err).Error("error search task tags")
+		return nil, nil, model.ErrInternalServer
 	}
 
 	if err := tx.Commit().Error; err != nil {
 		c.Log.WithError(err).Error("error search task tag")
-		return nil, 0, model.ErrInternalServer
+		return nil, nil, model.ErrInternalServer
 	}
 
-	responses := make([]model.TaskTagResult, len(taskTags))
-	for i, taskTag := range taskTags {
-		responses[i] = *converter.TaskWithTagsToResponse(&taskTag)
+	responses := make([]model.TaskWithTagsResponse, len(tasks))
+	for i, task := range tasks {
+		task.Tags = tags[task.ID]
+		responses[i] = task
 	}
 
-	return responses, total, nil
+	return responses, paging, nil
+}
+
+// tagsByTask loads the tags of the tasks in one query, keyed by task.
+func tagsByTask(db *gorm.DB, taskTagRepository *repository.TaskTagRepository, taskIds []uint) (map[uint][]model.TaskTagSummary, error) {
+	byTask := make(map[uint][]model.TaskTagSummary, len(taskIds))
+	if len(taskIds) == 0 {
+		return byTask, nil
+	}
+	tags, err := taskTagRepository.FindTagsByTaskIds(db, taskIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		byTask[tag.TaskId] = append(byTask[tag.TaskId], tag)
+	}
+	return byTask, nil
 }
 
-func (c *TaskTagUseCase) SearchTaskTagRequestWithTagId(ctx context.Context, request *model.SearchTaskTagRequestWithTagId) ([]model.TaskTagResult, int64, error) {
+func (c *TaskTagUseCase) SearchTaskTagRequestWithTagId(ctx context.Context, request *model.SearchTaskTagRequestWithTagId) ([]model.TaskTagResult, *model.PageMetadata, error) {
 	cacheKey := "task_tags:" + strconv.Itoa(int(request.TagId)) + "email:" + request.Email
 	var cachedData struct {
 		Responses []model.TaskTagResult
-		Total     int64
+		Paging    *model.PageMetadata
 	}
-	if err := c.Cache.GetAndUnmarshal(ctx, cacheKey, &cachedData); err == nil {
-		return cachedData.Responses, cachedData.Total, nil
+	// Only the first page is cached, later pages are read from the database.
+	cacheable := request.Page == 1 && request.Cursor == "" && request.Total == ""
+	if err := c.Cache.GetAndUnmarshal(ctx, cacheKey, &cachedData); err == nil && cacheable && cachedData.Paging != nil && cachedData.Paging.Size == request.Size {
+		return cachedData.Responses, cachedData.Paging, nil
 	}
 	tx := c.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 	
 	if err := c.Validate.Struct(request); err != nil {
 		c.Log.WithError(err).Error("error validate request query")
-		return nil, 0, model.ErrBadRequest
+		return nil, nil, model.ErrBadRequest
 	}
-	taskTags, total, err := c.TaskTagRepository.SearchTaskTagRequestWithTagId(tx, request)
+	taskTags, paging, err := c.TaskTagRepository.SearchTaskTagRequestWithTagId(tx, request)
 	if err != nil {
 		c.Log.WithError(err).Error("error search task tag request with tag id")
-		return nil, 0, err
+		return nil, nil, err
 	}
 	if err := tx.Commit().Error; err != nil {
 	c.Log.WithError(err).Error("error search task tag request with tag id")
-	return nil, 0, model.ErrInternalServer
+	return nil, nil, model.ErrInternalServer
 	}
 
 	responses := make([]model.TaskTagResult, len(taskTags))
@@ -127,12 +171,14 @@ func (c *TaskTagUseCase) SearchTaskTagRequestWithTagId(ctx context.Context, requ
 		responses[i] = *converter.TaskWithTagsToResponse(&taskTag)
 	}
 
-	cachedData.Responses = responses
-	cachedData.Total = total
-	cachedDataJSON, _ := json.Marshal(cachedData)
-	c.Cache.Set(ctx, cacheKey, cachedDataJSON, 30 * time.Minute)
+	if cacheable {
+		cachedData.Responses = responses
+		cachedData.Paging = paging
+		cachedDataJSON, _ := json.Marshal(cachedData)
+		c.Cache.Set(ctx, cacheKey, cachedDataJSON, 30 * time.Minute)
+	}
 
-	return responses, total, nil
+	return responses, paging, nil
 }
 
 func (c *TaskTagUseCase) Delete(ctx context.Context, request *model.GetTaskTagForDelete) error {
@@ -148,6 +194,10 @@ func (c *TaskTagUseCase) Delete(ctx context.Context, request *model.GetTaskTagFo
         c.Log.WithError(err).Error("error check is added task tag")
         return err
     }
+    activity, err := c.recordActivity(tx, taskTag, request.Email, false)
+    if err != nil {
+        return err
+    }
     if err := c.TaskTagRepository.Delete(tx, taskTag); err != nil {
         c.Log.WithError(err).Error("error delete task tag")
         return err
@@ -156,8 +206,34 @@ func (c *TaskTagUseCase) Delete(ctx context.Context, request *model.GetTaskTagFo
         c.Log.WithError(err).Error("error delete task tag")
         return model.ErrInternalServer
     }
+    c.Events.Publish(ctx, activity)
 
-	c.Cache.Delete(ctx, "task_tags:"+strconv.Itoa(int(request.TaskId))+"email:"+request.Email)
+    c.invalidateCache(ctx, request.TaskId, request.TagId)
+    c.Indexer.Refresh(ctx, request.TaskId)
 
     return nil
-}
\ No newline at end of file
+}
+
+func (c *TaskTagUseCase) invalidateCache(ctx context.Context, taskId uint, tagId uint) {
+	emails, err := c.ProjectMemberRepository.EmailsByTask(c.DB.WithContext(ctx), taskId)
+	if err != nil {
+		c.Log.WithError(err).Warn("error load project members for cache invalidation")
+		return
+	}
+	c.Cache.Delete(ctx, memberCacheKeys(emails, "task:"+strconv.Itoa(int(taskId)), "task_tags:"+strconv.Itoa(int(tagId)), "stats:")...)
+}
+
+// recordActivity records that the tag was added to or removed from the
+// task.
+func (c *TaskTagUseCase) recordActivity(tx *gorm.DB, taskTag *entity.TaskTag, email string, added bool) (*entity.Activity, error) {
+	if err := c.TaskTagRepository.LoadTaskAndTag(tx, taskTag); err != nil {
+		c.Log.WithError(err).Error("error search task tag")
+		return nil, model.ErrInternalServer
+	}
+	activity := taskTagActivity(&taskTag.Task, &taskTag.Tag, email, added)
+	if err := c.ActivityRepository.Record(tx, activity); err != nil {
+		c.Log.WithError(err).Error("error record activity")
+		return nil, model.ErrInternalServer
+	}
+	return activity, nil
+}
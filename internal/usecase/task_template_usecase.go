@@ -0,0 +1,338 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/filter"
+	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model/converter"
+	"github.com/abdisetiakawan/go-clean-arch/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// placeholder matches a {{name}} in the text of a template.
+var placeholder = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// TaskTemplateUseCase manages the task templates of a user and creates
+// tasks from them.
+type TaskTemplateUseCase struct {
+	DB                      *gorm.DB
+	Log                     *logrus.Logger
+	Validate                *validator.Validate
+	TaskTemplateRepository  *repository.TaskTemplateRepository
+	TaskRepository          *repository.TaskRepository
+	TagRepository           *repository.TagRepository
+	TaskTagRepository       *repository.TaskTagRepository
+	ProjectMemberRepository *repository.ProjectMemberRepository
+	Indexer                 *SearchUseCase
+	Cache                   *helper.CacheHelper
+	ActivityRepository      *repository.ActivityRepository
+	Events                  *EventUseCase
+}
+
+func NewTaskTemplateUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, taskTemplateRepository *repository.TaskTemplateRepository, taskRepository *repository.TaskRepository, tagRepository *repository.TagRepository, taskTagRepository *repository.TaskTagRepository, projectMemberRepository *repository.ProjectMemberRepository, indexer *SearchUseCase, cache *helper.CacheHelper, activityRepository *repository.ActivityRepository, events *EventUseCase) *TaskTemplateUseCase {
+	return &TaskTemplateUseCase{
+		DB:                      db,
+		Log:                     log,
+		Validate:                validate,
+		TaskTemplateRepository:  taskTemplateRepository,
+		TaskRepository:          taskRepository,
+		TagRepository:           tagRepository,
+		TaskTagRepository:       taskTagRepository,
+		ProjectMemberRepository: projectMemberRepository,
+		Indexer:                 indexer,
+		Cache:                   cache,
+		ActivityRepository:      activityRepository,
+		Events:                  events,
+	}
+}
+
+func (c *TaskTemplateUseCase) Create(ctx context.Context, request *model.SaveTaskTemplateRequest) (*model.TaskTemplateResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.validate(request); err != nil {
+		return nil, err
+	}
+	if err := c.checkName(tx, request.Email, request.Name, 0); err != nil {
+		return nil, err
+	}
+	template := &entity.TaskTemplate{Email: request.Email}
+	fillTemplate(template, request)
+	if err := c.TaskTemplateRepository.Create(tx, template); err != nil {
+		c.Log.WithError(err).Error("error create task template")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error create task template")
+		return nil, model.ErrInternalServer
+	}
+
+	return converter.TaskTemplateToResponse(template), nil
+}
+
+func (c *TaskTemplateUseCase) List(ctx context.Context, email string) ([]model.TaskTemplateResponse, error) {
+	templates, err := c.TaskTemplateRepository.FindByEmail(c.DB.WithContext(ctx), email)
+	if err != nil {
+		c.Log.WithError(err).Error("error search task templates")
+		return nil, model.ErrInternalServer
+	}
+	responses := make([]model.TaskTemplateResponse, len(templates))
+	for i := range templates {
+		responses[i] = *converter.TaskTemplateToResponse(&templates[i])
+	}
+	return responses, nil
+}
+
+func (c *TaskTemplateUseCase) Get(ctx context.Context, request *model.GetTaskTemplateRequest) (*model.TaskTemplateResponse, error) {
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return nil, model.ErrBadRequest
+	}
+	template, err := c.find(c.DB.WithContext(ctx), request.ID, request.Email)
+	if err != nil {
+		return nil, err
+	}
+	return converter.TaskTemplateToResponse(template), nil
+}
+
+// Update replaces the template with the request.
+func (c *TaskTemplateUseCase) Update(ctx context.Context, request *model.SaveTaskTemplateRequest) (*model.TaskTemplateResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.validate(request); err != nil {
+		return nil, err
+	}
+	template, err := c.find(tx, request.ID, request.Email)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkName(tx, request.Email, request.Name, template.ID); err != nil {
+		return nil, err
+	}
+	fillTemplate(template, request)
+	if err := c.TaskTemplateRepository.Update(tx, template); err != nil {
+		c.Log.WithError(err).Error("error update task template")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error update task template")
+		return nil, model.ErrInternalServer
+	}
+
+	return converter.TaskTemplateToResponse(template), nil
+}
+
+func (c *TaskTemplateUseCase) Delete(ctx context.Context, request *model.GetTaskTemplateRequest) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return model.ErrBadRequest
+	}
+	template, err := c.find(tx, request.ID, request.Email)
+	if err != nil {
+		return err
+	}
+	if err := c.TaskTemplateRepository.Delete(tx, template); err != nil {
+		c.Log.WithError(err).Error("error delete task template")
+		return model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error delete task template")
+		return model.ErrInternalServer
+	}
+	return nil
+}
+
+// Instantiate creates the task of a template together with its tags and
+// subtasks. Placeholders are filled in from the variables of the request;
+// {{today}} defaults to the current date of the user. Missing tags are
+// created in the project.
+func (c *TaskTemplateUseCase) Instantiate(ctx context.Context, request *model.InstantiateTaskTemplateRequest) (*model.TaskResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	found, err := c.find(tx, request.ID, request.Email)
+	if err != nil {
+		return nil, err
+	}
+	template := converter.TaskTemplateToResponse(found)
+	projectId, err := c.ProjectMemberRepository.ResolveWritable(tx, request.ProjectId, request.Email)
+	if err != nil {
+		c.Log.WithError(err).Error("error resolve project")
+		return nil, model.ErrForbidden
+	}
+	ends := newColumnEnds(c.TaskRepository, projectId)
+
+	now := time.Now().In(helper.Location(request.Timezone))
+	variables := map[string]string{"today": now.Format(time.DateOnly)}
+	for name, value := range request.Variables {
+		variables[name] = value
+	}
+	newTask := func(title string, description string, dueIn string, status string) (*entity.Task, error) {
+		task := &entity.Task{
+			Email:     request.Email,
+			ProjectId: projectId,
+			Status:    status,
+			Priority:  template.Priority,
+		}
+		var err error
+		if task.Title, err = fillPlaceholders(title, variables); err != nil {
+			return nil, err
+		}
+		if task.Description, err = fillPlaceholders(description, variables); err != nil {
+			return nil, err
+		}
+		task.Title = truncate(task.Title, 150)
+		if dueIn != "" {
+			due, _ := filter.ParseDate(dueIn, now)
+			task.DueDate = helper.CalendarDate(&due)
+		}
+		if task.Position, err = ends.next(tx, status); err != nil {
+			c.Log.WithError(err).Error("error search task position")
+			return nil, model.ErrInternalServer
+		}
+		return task, nil
+	}
+
+	root, err := newTask(template.Title, template.Description, template.DueIn, template.Status)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.TaskRepository.Create(tx, root); err != nil {
+		c.Log.WithError(err).Error("error create task")
+		return nil, model.ErrInternalServer
+	}
+	tagIds, err := newTagResolver(c.TagRepository, projectId, request.Email, true).resolve(tx, template.Tags)
+	if err != nil {
+		c.Log.WithError(err).Error("error resolve template tags")
+		return nil, model.ErrInternalServer
+	}
+	if err := c.TaskTagRepository.Attach(tx, root.ID, tagIds); err != nil {
+		c.Log.WithError(err).Error("error attach template tags")
+		return nil, model.ErrInternalServer
+	}
+	taskIds := []uint{root.ID}
+	activities := []*entity.Activity{taskCreatedActivity(root, request.Email)}
+	response := converter.TaskToResponse(root)
+	for _, subtask := range template.Subtasks {
+		task, err := newTask(subtask.Title, subtask.Description, subtask.DueIn, "pending")
+		if err != nil {
+			return nil, err
+		}
+		task.ParentId = &root.ID
+		if err := c.TaskRepository.Create(tx, task); err != nil {
+			c.Log.WithError(err).Error("error create subtask")
+			return nil, model.ErrInternalServer
+		}
+		taskIds = append(taskIds, task.ID)
+		activities = append(activities, taskCreatedActivity(task, request.Email))
+		response.Subtasks = append(response.Subtasks, *converter.TaskToResponse(task))
+	}
+	if err := c.ActivityRepository.Record(tx, activities...); err != nil {
+		c.Log.WithError(err).Error("error record activity")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error instantiate task template")
+		return nil, model.ErrInternalServer
+	}
+	c.Events.Publish(ctx, activities...)
+
+	if err := forgetProjectCache(ctx, c.DB, c.ProjectMemberRepository, c.Cache, []uint{projectId}, "stats:"); err != nil {
+		c.Log.WithError(err).Warn("error load project members for cache invalidation")
+	}
+	c.Indexer.Refresh(ctx, taskIds...)
+	return response, nil
+}
+
+// validate checks the request and the relative due dates it holds.
+func (c *TaskTemplateUseCase) validate(request *model.SaveTaskTemplateRequest) error {
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return model.ErrBadRequest
+	}
+	dueIns := []string{request.DueIn}
+	for _, subtask := range request.Subtasks {
+		dueIns = append(dueIns, subtask.DueIn)
+	}
+	for _, dueIn := range dueIns {
+		if _, err := filter.ParseDate(dueIn, time.Now()); dueIn != "" && err != nil {
+			return model.NewApiError(model.ErrBadRequest.StatusCode, fmt.Sprintf("due_in %q must be today, tomorrow or an offset such as +3d", dueIn))
+		}
+	}
+	return nil
+}
+
+func (c *TaskTemplateUseCase) find(tx *gorm.DB, id string, email string) (*entity.TaskTemplate, error) {
+	template := new(entity.TaskTemplate)
+	if err := c.TaskTemplateRepository.FindByEmailAndId(tx, template, id, email); err != nil {
+		c.Log.WithError(err).Error("error search task template")
+		return nil, model.ErrNotFound
+	}
+	return template, nil
+}
+
+func (c *TaskTemplateUseCase) checkName(tx *gorm.DB, email string, name string, exceptId uint) error {
+	total, err := c.TaskTemplateRepository.CountByName(tx, email, name, exceptId)
+	if err != nil {
+		c.Log.WithError(err).Error("error count task template")
+		return model.ErrInternalServer
+	}
+	if total > 0 {
+		return model.ErrConflict
+	}
+	return nil
+}
+
+func fillTemplate(template *entity.TaskTemplate, request *model.SaveTaskTemplateRequest) {
+	template.Name = request.Name
+	template.Title = request.Title
+	template.Description = request.Description
+	template.Status = request.Status
+	if template.Status == "" {
+		template.Status = "pending"
+	}
+	template.Priority = request.Priority
+	if template.Priority == "" {
+		template.Priority = "medium"
+	}
+	template.DueIn = request.DueIn
+	tags, _ := json.Marshal(append([]string{}, request.Tags...))
+	template.Tags = string(tags)
+	subtasks, _ := json.Marshal(append([]model.TemplateSubtask{}, request.Subtasks...))
+	template.Subtasks = string(subtasks)
+}
+
+// fillPlaceholders replaces the {{name}} placeholders of text. Every
+// placeholder needs a value.
+func fillPlaceholders(text string, variables map[string]string) (string, error) {
+	missing := ""
+	filled := placeholder.ReplaceAllStringFunc(text, func(match string) string {
+		name := placeholder.FindStringSubmatch(match)[1]
+		value, ok := variables[name]
+		if !ok && missing == "" {
+			missing = name
+		}
+		return value
+	})
+	if missing != "" {
+		return "", model.NewApiError(model.ErrBadRequest.StatusCode, fmt.Sprintf("missing variable %q", missing))
+	}
+	return filled, nil
+}
@@ -3,9 +3,11 @@ package usecase
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/filter"
 	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
 	"github.com/abdisetiakawan/go-clean-arch/internal/model"
 	"github.com/abdisetiakawan/go-clean-arch/internal/model/converter"
@@ -20,16 +22,32 @@ type TaskUseCase struct {
 	Log            *logrus.Logger
 	Validate       *validator.Validate
 	TaskRepository *repository.TaskRepository
+	ProjectMemberRepository *repository.ProjectMemberRepository
+	TaskAssigneeRepository *repository.TaskAssigneeRepository
+	TaskWatcherRepository *repository.TaskWatcherRepository
+	Notifier	   helper.Notifier
+	Indexer         *SearchUseCase
 	Cache 		   *helper.CacheHelper
+	ActivityRepository *repository.ActivityRepository
+	Events *EventUseCase
+	TaskTagRepository *repository.TaskTagRepository
 }
 
-func NewTaskUseCase(db *gorm.DB, logger *logrus.Logger, validate *validator.Validate, taskRepository *repository.TaskRepository, cache *helper.CacheHelper) *TaskUseCase {
+func NewTaskUseCase(db *gorm.DB, logger *logrus.Logger, validate *validator.Validate, taskRepository *repository.TaskRepository, projectMemberRepository *repository.ProjectMemberRepository, taskAssigneeRepository *repository.TaskAssigneeRepository, taskWatcherRepository *repository.TaskWatcherRepository, notifier helper.Notifier, indexer *SearchUseCase, cache *helper.CacheHelper, activityRepository *repository.ActivityRepository, events *EventUseCase, taskTagRepository *repository.TaskTagRepository) *TaskUseCase {
 	return &TaskUseCase{
 		DB: db,
 		Log: logger,
 		Validate: validate,
 		TaskRepository: taskRepository,
+		ProjectMemberRepository: projectMemberRepository,
+		TaskAssigneeRepository: taskAssigneeRepository,
+		TaskWatcherRepository: taskWatcherRepository,
+		Notifier: notifier,
+		Indexer: indexer,
 		Cache: cache,
+		ActivityRepository: activityRepository,
+		Events: events,
+		TaskTagRepository: taskTagRepository,
 	}
 }
 
@@ -41,52 +59,238 @@ func (c *TaskUseCase) Create(ctx context.Context, request *model.CreateTaskReque
 		c.Log.WithError(err).Error("error validate request body")
 		return nil, model.ErrBadRequest
 	}
+	if err := checkSchedule(request.StartDate, request.DueDate); err != nil {
+		c.Log.WithError(err).Error("error validate task schedule")
+		return nil, err
+	}
+	projectId, err := c.ProjectMemberRepository.ResolveWritable(tx, request.ProjectId, request.Email)
+	if err != nil {
+		c.Log.WithError(err).Error("error resolve project")
+		return nil, model.ErrForbidden
+	}
+	lastPosition, err := c.TaskRepository.MaxPosition(tx, projectId, request.Status)
+	if err != nil {
+		c.Log.WithError(err).Error("error search task position")
+		return nil, model.ErrInternalServer
+	}
 	task := &entity.Task{
 		Email: request.Email,
+		ProjectId: projectId,
 		Title: request.Title,
 		Description: request.Description,
 		Status: request.Status,
-		DueDate: request.DueDate,
+		Priority: request.Priority,
+		Position: helper.RankBetween(lastPosition, ""),
+		DueDate: helper.CalendarDate(request.DueDate),
+		DueTime: request.DueTime,
+		StartDate: helper.CalendarDate(request.StartDate),
 	}
 	if err := c.TaskRepository.Create(tx, task); err != nil {
 		c.Log.WithError(err).Error("error create task")
 		return nil, model.ErrInternalServer
 	}
+	assignees := uniqueEmails(request.Assignees)
+	if err := checkMembers(tx, c.ProjectMemberRepository, projectId, assignees); err != nil {
+		c.Log.WithError(err).Error("error validate task assignees")
+		return nil, err
+	}
+	if err := c.TaskAssigneeRepository.Replace(tx, task.ID, assignees); err != nil {
+		c.Log.WithError(err).Error("error create task assignees")
+		return nil, model.ErrInternalServer
+	}
+	activity := taskCreatedActivity(task, request.Email)
+	if err := c.ActivityRepository.Record(tx, activity); err != nil {
+		c.Log.WithError(err).Error("error record activity")
+		return nil, model.ErrInternalServer
+	}
 	if err := tx.Commit().Error; err != nil {
 		c.Log.WithError(err).Error("error create task")
 		return nil, model.ErrInternalServer
 	}
+	c.Events.Publish(ctx, activity)
 
-	return converter.TaskToResponse(task), nil
+	if err := forgetProjectCache(ctx, c.DB, c.ProjectMemberRepository, c.Cache, []uint{projectId}, "stats:"); err != nil {
+		c.Log.WithError(err).Warn("error load project members for cache invalidation")
+	}
+	c.Indexer.Refresh(ctx, task.ID)
+	c.Notifier.Notify(ctx, assignmentNotifications(task, request.Email, nil, assignees, nil)...)
+	response := converter.TaskToResponse(task)
+	response.Assignees = assignees
+	return response, nil
 }
 
-func (c *TaskUseCase) Search(ctx context.Context, request *model.SearchTaskRequest) ([]model.TaskResponse, int64, error) {
+func (c *TaskUseCase) Search(ctx context.Context, request *model.SearchTaskRequest) ([]model.TaskResponse, *model.PageMetadata, error) {
 	tx := c.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 	
 	if err := c.Validate.Struct(request); err != nil {
 		c.Log.WithError(err).Error("error validate request body")
-		return nil, 0, model.ErrBadRequest
+		return nil, nil, model.ErrBadRequest
+	}
+	if request.DueFrom != "" && request.DueTo != "" && request.DueFrom > request.DueTo {
+		c.Log.Error("error due_from is after due_to")
+		return nil, nil, model.ErrBadRequest
+	}
+	expr, err := parseFilter(request.Query)
+	if err != nil {
+		c.Log.WithError(err).Error("error parse task filter")
+		return nil, nil, err
 	}
-	tasks, total, err := c.TaskRepository.Search(tx, request)
+	tasks, paging, err := c.TaskRepository.Search(tx, request, expr)
 	if err != nil {
 		c.Log.WithError(err).Error("error search task")
-		return nil, 0, model.ErrNotFound
+		return nil, nil, pageError(err, model.ErrNotFound)
+	}
+	var tags map[uint][]model.TaskTagSummary
+	if request.Include == "tags" {
+		taskIds := make([]uint, len(tasks))
+		for i, task := range tasks {
+			taskIds[i] = task.ID
+		}
+		if tags, err = tagsByTask(tx, c.TaskTagRepository, taskIds); err != nil {
+			c.Log.WithError(err).Error("error search task tags")
+			return nil, nil, model.ErrInternalServer
+		}
 	}
 	if err := tx.Commit().Error; err != nil {
 		c.Log.WithError(err).Error("error search task")
-		return nil, 0, model.ErrInternalServer
+		return nil, nil, model.ErrInternalServer
 	}
 	responses := make([]model.TaskResponse, len(tasks))
 	for i, task := range tasks {
 		task.Email = ""
 		responses[i] = *converter.TaskToResponse(&task)
+		responses[i].Tags = tags[task.ID]
+	}
+
+	return responses, paging, nil
+}
+
+// Calendar groups the tasks due in a month or a week by day. Days without
+// tasks are included so clients can render the grid as is.
+func (c *TaskUseCase) Calendar(ctx context.Context, request *model.TaskCalendarRequest) (*model.TaskCalendarResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return nil, model.ErrBadRequest
+	}
+	expr, err := parseFilter(request.Query)
+	if err != nil {
+		c.Log.WithError(err).Error("error parse task filter")
+		return nil, err
+	}
+	location := helper.Location(request.Timezone)
+	today := time.Now().In(location)
+	from, to := calendarRange(request, today)
+
+	search := &model.SearchTaskRequest{
+		Email:     request.Email,
+		ProjectId: request.ProjectId,
+		DueFrom:   from.Format(time.DateOnly),
+		DueTo:     to.Format(time.DateOnly),
+		Timezone:  request.Timezone,
+	}
+	tasks, err := c.TaskRepository.FindDue(tx, search, expr)
+	if err != nil {
+		c.Log.WithError(err).Error("error search task")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error search task")
+		return nil, model.ErrInternalServer
+	}
+
+	response := &model.TaskCalendarResponse{
+		From:     search.DueFrom,
+		To:       search.DueTo,
+		Today:    today.Format(time.DateOnly),
+		Timezone: location.String(),
+	}
+	index := map[string]int{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		index[date] = len(response.Days)
+		response.Days = append(response.Days, model.CalendarDayResponse{Date: date, Tasks: []model.TaskSummaryResponse{}})
+	}
+	for _, task := range tasks {
+		i, ok := index[task.DueDate.Format(time.DateOnly)]
+		if !ok {
+			continue
+		}
+		day := &response.Days[i]
+		day.Count++
+		if len(day.Tasks) < request.Limit {
+			day.Tasks = append(day.Tasks, *converter.TaskToSummary(&task))
+		}
+	}
+	return response, nil
+}
+
+// calendarRange returns the first and last day of the requested week
+// (Monday to Sunday) or month, defaulting to the current month.
+func calendarRange(request *model.TaskCalendarRequest, today time.Time) (time.Time, time.Time) {
+	if request.Week != "" {
+		day, _ := time.Parse(time.DateOnly, request.Week)
+		monday := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+		return monday, monday.AddDate(0, 0, 6)
+	}
+	first := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if request.Month != "" {
+		first, _ = time.Parse("2006-01", request.Month)
 	}
+	return first, first.AddDate(0, 1, -1)
+}
+
+// checkSchedule rejects a start date after the due date.
+func checkSchedule(start *time.Time, due *time.Time) error {
+	if start != nil && due != nil && helper.CalendarDate(start).After(*helper.CalendarDate(due)) {
+		return model.NewApiError(model.ErrBadRequest.StatusCode, "start_date must not be after due_date")
+	}
+	return nil
+}
+
+// parseFilter parses a filter expression and reports syntax errors with
+// their position to the client.
+func parseFilter(query string) (filter.Expr, error) {
+	expr, err := filter.Parse(query)
+	if err != nil {
+		return nil, model.NewApiError(model.ErrBadRequest.StatusCode, err.Error())
+	}
+	return expr, nil
+}
 
-	return responses, total, nil
+// pageError keeps an invalid cursor a client error and reports any other
+// paging failure as fallback.
+func pageError(err error, fallback error) error {
+	if errors.Is(err, model.ErrInvalidCursor) {
+		return err
+	}
+	return fallback
 }
 
+// Get returns the task, with its tags when they are included. The tags
+// are loaded apart from the cached task, since they change without it.
 func (c *TaskUseCase) Get(ctx context.Context, request *model.GetTaskRequest) (*model.TaskResponse, error) {
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return nil, model.ErrBadRequest
+	}
+	response, err := c.get(ctx, request)
+	if err != nil || request.Include != "tags" {
+		return response, err
+	}
+	tags, err := tagsByTask(c.DB.WithContext(ctx), c.TaskTagRepository, []uint{response.ID})
+	if err != nil {
+		c.Log.WithError(err).Error("error search task tags")
+		return nil, model.ErrInternalServer
+	}
+	response.Tags = tags[response.ID]
+	return response, nil
+}
+
+func (c *TaskUseCase) get(ctx context.Context, request *model.GetTaskRequest) (*model.TaskResponse, error) {
 	var taskResponse model.TaskResponse
     cacheKey := "task:" + request.ID + "email:" + request.Email
     if err := c.Cache.GetAndUnmarshal(ctx, cacheKey, &taskResponse); err == nil {
@@ -104,11 +308,23 @@ func (c *TaskUseCase) Get(ctx context.Context, request *model.GetTaskRequest) (*
 		c.Log.WithError(err).Error("error search task")
 		return nil, model.ErrNotFound
 	}
+	assignees, err := c.TaskAssigneeRepository.FindEmailsByTask(tx, task.ID)
+	if err != nil {
+		c.Log.WithError(err).Error("error search task assignees")
+		return nil, model.ErrInternalServer
+	}
+	watchers, err := c.TaskWatcherRepository.FindEmailsByTask(tx, task.ID)
+	if err != nil {
+		c.Log.WithError(err).Error("error search task watchers")
+		return nil, model.ErrInternalServer
+	}
 	if err := tx.Commit().Error; err != nil {
 		c.Log.WithError(err).Error("error search task")
 		return nil, model.ErrInternalServer
 	}
 	taskResponse = *converter.TaskToResponse(task)
+	taskResponse.Assignees = assignees
+	taskResponse.Watchers = watchers
 	taskResponseJSON, _ := json.Marshal(taskResponse)
 	c.Cache.Set(ctx, cacheKey, taskResponseJSON, 30*time.Minute)
 	return &taskResponse, nil
@@ -128,9 +344,28 @@ func (c *TaskUseCase) Delete(ctx context.Context, request *model.GetTaskRequest)
 		c.Log.WithError(err).Error("error search task")
 		return model.ErrNotFound
 	}
+	if err := checkWriteAccess(tx, c.ProjectMemberRepository, task.ProjectId, request.Email); err != nil {
+		c.Log.WithError(err).Error("error delete task")
+		return err
+	}
+	if err := checkVersion(request.IfMatch, task.Version); err != nil {
+		c.Log.WithError(err).Error("error delete task")
+		return err
+	}
+	// subtasks go with their task
+	subtaskIds, err := c.TaskRepository.SubtaskIds(tx, task.ID)
+	if err != nil {
+		c.Log.WithError(err).Error("error search subtasks")
+		return model.ErrInternalServer
+	}
 
 	if err := c.TaskRepository.Delete(tx, task); err != nil {
 		c.Log.WithError(err).Error("error delete task")
+		return writeError(err)
+	}
+	activity := taskDeletedActivity(task, request.Email)
+	if err := c.ActivityRepository.Record(tx, activity); err != nil {
+		c.Log.WithError(err).Error("error record activity")
 		return model.ErrInternalServer
 	}
 
@@ -138,49 +373,342 @@ func (c *TaskUseCase) Delete(ctx context.Context, request *model.GetTaskRequest)
 		c.Log.WithError(err).Error("error delete task")
 		return model.ErrInternalServer
 	}
+	c.Events.Publish(ctx, activity)
 
-	c.Cache.Delete(ctx, "task:"+request.ID+"email:"+request.Email)
+	c.invalidateCache(ctx, task.ProjectId, request.ID)
+	if err := forgetCachedTasks(ctx, c.DB, c.ProjectMemberRepository, c.Cache, task.ProjectId, subtaskIds); err != nil {
+		c.Log.WithError(err).Warn("error load project members for cache invalidation")
+	}
+	c.Indexer.Forget(ctx, append(subtaskIds, task.ID)...)
 	return nil
 }
 
+// Update replaces the task with the request.
 func (c *TaskUseCase) Update(ctx context.Context, request *model.UpdateTaskRequest) (*model.TaskResponse, error) {
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+
+	return c.edit(ctx, request.ID, request.Email, request.IfMatch, func(task *entity.Task) {
+		task.Title = request.Title
+		task.Description = request.Description
+		task.Status = request.Status
+		if task.Status == "" {
+			task.Status = "pending"
+		}
+		task.Priority = request.Priority
+		if task.Priority == "" {
+			task.Priority = "medium"
+		}
+		task.DueDate = helper.CalendarDate(request.DueDate)
+		task.DueTime = request.DueTime
+		task.StartDate = helper.CalendarDate(request.StartDate)
+	})
+}
+
+// Patch applies a JSON merge patch to the task. Clearing the due date
+// clears the due time too, unless the patch sets one.
+func (c *TaskUseCase) Patch(ctx context.Context, request *model.PatchTaskRequest) (*model.TaskResponse, error) {
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	for field, optional := range map[string]model.Optional[string]{"title": request.Title, "status": request.Status, "priority": request.Priority} {
+		if optional.Null {
+			return nil, model.NewApiError(model.ErrBadRequest.StatusCode, field+" cannot be null")
+		}
+	}
+
+	return c.edit(ctx, request.ID, request.Email, request.IfMatch, func(task *entity.Task) {
+		if request.Title.Set {
+			task.Title = request.Title.Value
+		}
+		if request.Description.Set {
+			task.Description = request.Description.Value
+		}
+		if request.Status.Set {
+			task.Status = request.Status.Value
+		}
+		if request.Priority.Set {
+			task.Priority = request.Priority.Value
+		}
+		if request.DueDate.Null {
+			task.DueDate, task.DueTime = nil, nil
+		} else if request.DueDate.Set {
+			task.DueDate = helper.CalendarDate(&request.DueDate.Value)
+		}
+		if request.DueTime.Null {
+			task.DueTime = nil
+		} else if request.DueTime.Set {
+			task.DueTime = &request.DueTime.Value
+		}
+		if request.StartDate.Null {
+			task.StartDate = nil
+		} else if request.StartDate.Set {
+			task.StartDate = helper.CalendarDate(&request.StartDate.Value)
+		}
+	})
+}
+
+// edit loads the task, lets change modify it and stores the result. A
+// non-empty ifMatch must name the version of the task.
+func (c *TaskUseCase) edit(ctx context.Context, id string, email string, ifMatch string, change func(task *entity.Task)) (*model.TaskResponse, error) {
 	tx := c.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	task := new(entity.Task)
-	if err := c.TaskRepository.FindByEmailAndId(tx, task, request.ID, request.Email); err != nil {
+	if err := c.TaskRepository.FindByEmailAndId(tx, task, id, email); err != nil {
 		c.Log.WithError(err).Error("error search task")
 		return nil, model.ErrNotFound
 	}
+	if err := checkVersion(ifMatch, task.Version); err != nil {
+		c.Log.WithError(err).Error("error update task")
+		return nil, err
+	}
+	before := *task
+	change(task)
+	if err := c.checkUpdateAccess(tx, &before, task, email); err != nil {
+		c.Log.WithError(err).Error("error update task")
+		return nil, err
+	}
+	if task.DueTime != nil && task.DueDate == nil {
+		c.Log.Error("error due time without due date")
+		return nil, model.NewApiError(model.ErrBadRequest.StatusCode, "due_time requires a due_date")
+	}
+	if err := checkSchedule(task.StartDate, task.DueDate); err != nil {
+		c.Log.WithError(err).Error("error validate task schedule")
+		return nil, err
+	}
+
+	if err := c.TaskRepository.Update(tx, task); err != nil {
+		c.Log.WithError(err).Error("error update task")
+		return nil, writeError(err)
+	}
+	activities := taskEditActivities(&before, task, email)
+	if err := c.ActivityRepository.Record(tx, activities...); err != nil {
+		c.Log.WithError(err).Error("error record activity")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error update task")
+		return nil, model.ErrInternalServer
+	}
+	c.Events.Publish(ctx, activities...)
+
+	c.invalidateCache(ctx, task.ProjectId, id)
+	c.Indexer.Refresh(ctx, task.ID)
+
+	return converter.TaskToResponse(task), nil
+}
+
+// checkUpdateAccess allows editors to change anything, while assignees
+// without write access may still move the task to another status.
+func (c *TaskUseCase) checkUpdateAccess(tx *gorm.DB, before *entity.Task, after *entity.Task, email string) error {
+	if len(taskChanges(before, after)) > 0 {
+		return checkWriteAccess(tx, c.ProjectMemberRepository, after.ProjectId, email)
+	}
+	return c.checkStatusAccess(tx, after, email)
+}
+
+// taskChanges names the fields other than status and position that differ
+// between two versions of a task.
+func taskChanges(before *entity.Task, after *entity.Task) []string {
+	var fields []string
+	if before.Title != after.Title {
+		fields = append(fields, "title")
+	}
+	if before.Description != after.Description {
+		fields = append(fields, "description")
+	}
+	if before.Priority != after.Priority {
+		fields = append(fields, "priority")
+	}
+	if !sameDate(before.DueDate, after.DueDate) {
+		fields = append(fields, "due_date")
+	}
+	if !sameClock(before.DueTime, after.DueTime) {
+		fields = append(fields, "due_time")
+	}
+	if !sameDate(before.StartDate, after.StartDate) {
+		fields = append(fields, "start_date")
+	}
+	return fields
+}
+
+func sameDate(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Format(time.DateOnly) == b.Format(time.DateOnly)
+}
+
+// sameClock compares times of day, which the database returns with
+// seconds.
+func sameClock(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return (*a)[:min(5, len(*a))] == (*b)[:min(5, len(*b))]
+}
+
+func (c *TaskUseCase) checkStatusAccess(tx *gorm.DB, task *entity.Task, email string) error {
+	return checkStatusAccess(tx, c.ProjectMemberRepository, c.TaskAssigneeRepository, task, email)
+}
+
+func (c *TaskUseCase) invalidateCache(ctx context.Context, projectId uint, taskId string) {
+	emails, err := c.ProjectMemberRepository.Emails(c.DB.WithContext(ctx), projectId)
+	if err != nil {
+		c.Log.WithError(err).Warn("error load project members for cache invalidation")
+		return
+	}
+	c.Cache.Delete(ctx, memberCacheKeys(emails, "task:"+taskId, "stats:")...)
+}
+func (c *TaskUseCase) Board(ctx context.Context, request *model.GetBoardRequest) ([]model.BoardColumnResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
 	if err := c.Validate.Struct(request); err != nil {
 		c.Log.WithError(err).Error("error validate request query")
 		return nil, model.ErrBadRequest
 	}
-	if request.Title != "" {
-		task.Title = request.Title
+	member := new(entity.ProjectMember)
+	if err := c.ProjectMemberRepository.FindByProjectAndEmail(tx, member, request.ProjectId, request.Email); err != nil {
+		c.Log.WithError(err).Error("error search project member")
+		return nil, model.ErrNotFound
 	}
-	if request.Description != "" {
-		task.Description = request.Description
+
+	statuses := request.Statuses
+	if len(statuses) == 0 {
+		statuses = model.TaskStatuses
 	}
-	if request.Status != "" {
-		task.Status = request.Status
+	columns := make([]model.BoardColumnResponse, len(statuses))
+	for i, status := range statuses {
+		page := request.Pages[status]
+		if page == 0 {
+			page = 1
+		}
+		tasks, paging, err := c.TaskRepository.SearchColumn(tx, request, status, page)
+		if err != nil {
+			c.Log.WithError(err).Error("error search board column")
+			return nil, pageError(err, model.ErrInternalServer)
+		}
+		responses := make([]model.TaskResponse, len(tasks))
+		for j, task := range tasks {
+			task.Email = ""
+			responses[j] = *converter.TaskToResponse(&task)
+		}
+		columns[i] = model.BoardColumnResponse{
+			Status: status,
+			Tasks:  responses,
+			Paging: paging,
+		}
 	}
-	if !request.DueDate.IsZero() { 
-		task.DueDate = request.DueDate
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error search board")
+		return nil, model.ErrInternalServer
 	}
-	
+
+	return columns, nil
+}
+
+// Move puts a task into a status column between two neighbours. Only the
+// moved task gets a new position, the rest of the column is left as is
+// unless positions in it got too long and are spread out again.
+func (c *TaskUseCase) Move(ctx context.Context, request *model.MoveTaskRequest) (*model.TaskResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	task := new(entity.Task)
+	if err := c.TaskRepository.FindByEmailAndId(tx, task, request.ID, request.Email); err != nil {
+		c.Log.WithError(err).Error("error search task")
+		return nil, model.ErrNotFound
+	}
+	if err := c.checkStatusAccess(tx, task, request.Email); err != nil {
+		c.Log.WithError(err).Error("error move task")
+		return nil, err
+	}
+
+	prev, next, err := c.neighbours(tx, task, request)
+	if err != nil {
+		return nil, err
+	}
+	position := helper.RankBetween(prev, next)
+	var spread []uint
+	if len(position) > helper.RankMaxLength {
+		// too many moves into the same gap, give the column new positions
+		if spread, err = c.TaskRepository.SpreadColumn(tx, task.ProjectId, request.Status, task.ID); err != nil {
+			c.Log.WithError(err).Error("error spread task positions")
+			return nil, model.ErrInternalServer
+		}
+		if prev, next, err = c.neighbours(tx, task, request); err != nil {
+			return nil, err
+		}
+		position = helper.RankBetween(prev, next)
+	}
+
+	before := *task
+	task.Status = request.Status
+	task.Position = position
 	if err := c.TaskRepository.Update(tx, task); err != nil {
-		c.Log.WithError(err).Error("error update task")
+		c.Log.WithError(err).Error("error move task")
+		return nil, writeError(err)
+	}
+	activities := taskEditActivities(&before, task, request.Email)
+	if err := c.ActivityRepository.Record(tx, activities...); err != nil {
+		c.Log.WithError(err).Error("error record activity")
 		return nil, model.ErrInternalServer
 	}
 	if err := tx.Commit().Error; err != nil {
-		c.Log.WithError(err).Error("error update task")
+		c.Log.WithError(err).Error("error move task")
 		return nil, model.ErrInternalServer
 	}
+	c.Events.Publish(ctx, activities...)
 
-	taskResponse := converter.TaskToResponse(task)
-    taskResponseJSON, _ := json.Marshal(taskResponse)
-    c.Cache.Set(ctx, "task:"+request.ID+"email:"+request.Email, taskResponseJSON, 30*time.Minute)
+	c.invalidateCache(ctx, task.ProjectId, request.ID)
+	if len(spread) > 0 {
+		if err := forgetCachedTasks(ctx, c.DB.WithContext(ctx), c.ProjectMemberRepository, c.Cache, task.ProjectId, spread); err != nil {
+			c.Log.WithError(err).Warn("error forget cached tasks")
+		}
+	}
+	return converter.TaskToResponse(task), nil
+}
 
-	return taskResponse, nil
-}
\ No newline at end of file
+// neighbours returns the positions the moved task goes between: those of
+// the tasks named by the request, or the end of the target column when it
+// names none.
+func (c *TaskUseCase) neighbours(tx *gorm.DB, task *entity.Task, request *model.MoveTaskRequest) (string, string, error) {
+	var prev, next string
+	if request.AfterId != 0 {
+		after := new(entity.Task)
+		if err := c.TaskRepository.FindInColumn(tx, after, request.AfterId, task.ProjectId, request.Status); err != nil {
+			c.Log.WithError(err).Error("error search previous task")
+			return "", "", model.ErrBadRequest
+		}
+		prev = after.Position
+	}
+	if request.BeforeId != 0 {
+		before := new(entity.Task)
+		if err := c.TaskRepository.FindInColumn(tx, before, request.BeforeId, task.ProjectId, request.Status); err != nil {
+			c.Log.WithError(err).Error("error search next task")
+			return "", "", model.ErrBadRequest
+		}
+		next = before.Position
+	}
+	if request.AfterId == 0 && request.BeforeId == 0 {
+		last, err := c.TaskRepository.MaxPosition(tx, task.ProjectId, request.Status)
+		if err != nil {
+			c.Log.WithError(err).Error("error search task position")
+			return "", "", model.ErrInternalServer
+		}
+		prev = last
+	}
+	if next != "" && prev >= next {
+		return "", "", model.ErrBadRequest
+	}
+	return prev, next, nil
+}
@@ -0,0 +1,606 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
+	"github.com/abdisetiakawan/go-clean-arch/internal/importer"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// exportBatchSize is the number of tasks loaded per query while exporting.
+const exportBatchSize = 500
+
+// TransferUseCase moves tasks in and out of the service in bulk.
+type TransferUseCase struct {
+	DB                      *gorm.DB
+	Log                     *logrus.Logger
+	Validate                *validator.Validate
+	TaskRepository          *repository.TaskRepository
+	TagRepository           *repository.TagRepository
+	TaskTagRepository       *repository.TaskTagRepository
+	ProjectMemberRepository *repository.ProjectMemberRepository
+	Indexer                 *SearchUseCase
+	Cache                   *helper.CacheHelper
+	ActivityRepository      *repository.ActivityRepository
+	Events                  *EventUseCase
+}
+
+func NewTransferUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, taskRepository *repository.TaskRepository, tagRepository *repository.TagRepository, taskTagRepository *repository.TaskTagRepository, projectMemberRepository *repository.ProjectMemberRepository, indexer *SearchUseCase, cache *helper.CacheHelper, activityRepository *repository.ActivityRepository, events *EventUseCase) *TransferUseCase {
+	return &TransferUseCase{
+		DB:                      db,
+		Log:                     log,
+		Validate:                validate,
+		TaskRepository:          taskRepository,
+		TagRepository:           tagRepository,
+		TaskTagRepository:       taskTagRepository,
+		ProjectMemberRepository: projectMemberRepository,
+		Indexer:                 indexer,
+		Cache:                   cache,
+		ActivityRepository:      activityRepository,
+		Events:                  events,
+	}
+}
+
+// taskRow is a record read from an import file.
+type taskRow = importer.Row
+
+// Export writes every task matching the request, with its tags, to w.
+func (c *TransferUseCase) Export(ctx context.Context, request *model.ExportTasksRequest, w io.Writer) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return model.ErrBadRequest
+	}
+	expr, err := parseFilter(request.Query)
+	if err != nil {
+		c.Log.WithError(err).Error("error parse task filter")
+		return err
+	}
+
+	writer := newRecordWriter(request.Format, w)
+	search := &model.SearchTaskRequest{
+		Email:     request.Email,
+		ProjectId: request.ProjectId,
+		Timezone:  request.Timezone,
+	}
+	var afterId uint
+	for {
+		tasks, err := c.TaskRepository.FindExportBatch(tx, search, expr, afterId, exportBatchSize)
+		if err != nil {
+			c.Log.WithError(err).Error("error search task")
+			return model.ErrInternalServer
+		}
+		for i := range tasks {
+			if err := writer.Write(taskToRecord(&tasks[i])); err != nil {
+				c.Log.WithError(err).Error("error write task record")
+				return model.ErrInternalServer
+			}
+		}
+		if len(tasks) < exportBatchSize {
+			break
+		}
+		afterId = tasks[len(tasks)-1].ID
+	}
+	if err := writer.Flush(); err != nil {
+		c.Log.WithError(err).Error("error write task record")
+		return model.ErrInternalServer
+	}
+	return nil
+}
+
+// Import creates or updates a task for every record of the file. Records
+// are matched to existing tasks of the project by uid, then by id.
+//
+// Rows that fail validation are reported as skipped. A dry run writes
+// nothing, an atomic import writes nothing unless every row is valid, and
+// any other import commits every ChunkSize rows so an interrupted import
+// can be resumed from the reported offset.
+func (c *TransferUseCase) Import(ctx context.Context, request *model.ImportTasksRequest) (*model.ImportResponse, error) {
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	projectId, err := c.ProjectMemberRepository.ResolveWritable(c.DB.WithContext(ctx), request.ProjectId, request.Email)
+	if err != nil {
+		c.Log.WithError(err).Error("error resolve project")
+		return nil, model.ErrForbidden
+	}
+	rows, err := readTaskRows(request.Format, request.Data, request.Mapping)
+	if err != nil {
+		c.Log.WithError(err).Error("error read import file")
+		return nil, model.NewApiError(model.ErrBadRequest.StatusCode, err.Error())
+	}
+	if request.Offset < len(rows) {
+		rows = rows[request.Offset:]
+	} else {
+		rows = nil
+	}
+
+	writer := c.newImporter(request.Email, projectId, request.CreateTags)
+	response := &model.ImportResponse{Skipped: []model.ImportSkipped{}, DryRun: request.DryRun}
+	chunkSize := request.ChunkSize
+	if request.DryRun || request.Atomic {
+		chunkSize = len(rows)
+	}
+	for start := 0; start < len(rows); start += chunkSize {
+		chunk := rows[start:min(start+chunkSize, len(rows))]
+		result, err := writer.run(ctx, chunk)
+		if err == nil && (request.DryRun || request.Atomic && len(result.Skipped) > 0) {
+			writer.rollback()
+			response.Rejected = !request.DryRun
+		} else if err == nil {
+			err = writer.commit(ctx, result)
+		}
+		if err != nil {
+			c.Log.WithError(err).Error("error import tasks")
+			if start == 0 {
+				return nil, model.ErrInternalServer
+			}
+			resumeFrom := request.Offset + start
+			response.ResumeFrom = &resumeFrom
+			return response, nil
+		}
+		response.Processed += len(chunk)
+		response.Skipped = append(response.Skipped, result.Skipped...)
+		if !response.Rejected {
+			response.Created += len(result.Created)
+			response.Updated += len(result.Updated)
+		}
+	}
+	return response, nil
+}
+
+// taskImporter writes import rows into one project. The tag resolver is
+// shared by every chunk so tags are looked up once per import.
+type taskImporter struct {
+	useCase   *TransferUseCase
+	email     string
+	projectId uint
+	tags      *tagResolver
+	tx        *gorm.DB
+}
+
+func (c *TransferUseCase) newImporter(email string, projectId uint, createTags bool) *taskImporter {
+	return &taskImporter{
+		useCase:   c,
+		email:     email,
+		projectId: projectId,
+		tags:      newTagResolver(c.TagRepository, projectId, email, createTags),
+	}
+}
+
+type importResult struct {
+	Created    []uint
+	Updated    []uint
+	Skipped    []model.ImportSkipped
+	Activities []*entity.Activity
+}
+
+// run imports the rows in a new transaction. On success the transaction
+// is left open for the caller to commit or roll back.
+func (i *taskImporter) run(ctx context.Context, rows []taskRow) (*importResult, error) {
+	c := i.useCase
+	i.tx = c.DB.WithContext(ctx).Begin()
+	ends := newColumnEnds(c.TaskRepository, i.projectId)
+	result := &importResult{Skipped: []model.ImportSkipped{}}
+	for _, row := range rows {
+		task, before, tagIds, reason, err := i.prepare(row)
+		if err != nil {
+			i.rollback()
+			return nil, err
+		}
+		if reason != "" {
+			result.Skipped = append(result.Skipped, model.ImportSkipped{Item: row.Item, UID: row.Record.Uid, Reason: reason})
+			continue
+		}
+		if task.ID != 0 {
+			err = c.TaskRepository.Update(i.tx, task)
+			result.Updated = append(result.Updated, task.ID)
+			result.Activities = append(result.Activities, taskEditActivities(&before, task, i.email)...)
+		} else {
+			task.Email = i.email
+			task.ProjectId = i.projectId
+			task.Position, err = ends.next(i.tx, task.Status)
+			if err == nil {
+				err = c.TaskRepository.Create(i.tx, task)
+			}
+			result.Created = append(result.Created, task.ID)
+			result.Activities = append(result.Activities, taskCreatedActivity(task, i.email))
+		}
+		if err == nil {
+			err = c.TaskTagRepository.Attach(i.tx, task.ID, tagIds)
+		}
+		if err != nil {
+			i.rollback()
+			return nil, err
+		}
+	}
+	if err := c.ActivityRepository.Record(i.tx, result.Activities...); err != nil {
+		i.rollback()
+		return nil, err
+	}
+	return result, nil
+}
+
+func (i *taskImporter) rollback() {
+	i.tx.Rollback()
+	// tags created in the rolled back transaction no longer exist
+	i.tags.ids = map[string]uint{}
+}
+
+// commit commits the open transaction, publishes the activities of the
+// imported tasks and refreshes what depends on them.
+func (i *taskImporter) commit(ctx context.Context, result *importResult) error {
+	c := i.useCase
+	if err := i.tx.Commit().Error; err != nil {
+		i.tags.ids = map[string]uint{}
+		return err
+	}
+	c.Events.Publish(ctx, result.Activities...)
+	if err := forgetCachedTasks(ctx, c.DB, c.ProjectMemberRepository, c.Cache, i.projectId, result.Updated); err != nil {
+		c.Log.WithError(err).Warn("error load project members for cache invalidation")
+	}
+	c.Indexer.Refresh(ctx, append(result.Created, result.Updated...)...)
+	return nil
+}
+
+// prepare validates the row and loads the task it updates, if any, along
+// with a copy of it as stored. It returns the reason when the row cannot be
+// imported.
+func (i *taskImporter) prepare(row taskRow) (task *entity.Task, before entity.Task, tagIds []uint, reason string, err error) {
+	c := i.useCase
+	if row.Error != "" {
+		return nil, before, nil, row.Error, nil
+	}
+	record := &row.Record
+	record.Title = strings.TrimSpace(record.Title)
+	if err := c.Validate.Struct(record); err != nil {
+		return nil, before, nil, recordError(err), nil
+	}
+
+	task = new(entity.Task)
+	err = gorm.ErrRecordNotFound
+	if record.Uid != "" {
+		var id uint
+		if match := generatedUid.FindStringSubmatch(record.Uid); match != nil {
+			parsed, _ := strconv.ParseUint(match[1], 10, 32)
+			id = uint(parsed)
+		}
+		err = c.TaskRepository.FindByUid(i.tx, task, i.projectId, record.Uid, id)
+	} else if record.ID != 0 {
+		err = c.TaskRepository.FindInProject(i.tx, task, record.ID, i.projectId)
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, before, nil, "", err
+	}
+	if err != nil {
+		task = new(entity.Task)
+	}
+	before = *task
+	if reason := recordToTask(task, record); reason != "" {
+		return nil, before, nil, reason, nil
+	}
+
+	tagIds, err = i.tags.resolve(i.tx, record.Tags)
+	if errors.Is(err, errUnknownTag) {
+		return nil, before, nil, err.Error(), nil
+	}
+	if err != nil {
+		return nil, before, nil, "", err
+	}
+	return task, before, tagIds, "", nil
+}
+
+// recordToTask copies a validated record onto the task. An empty status or
+// priority keeps the current value, or the default for a new task. It
+// returns the reason when the record cannot be imported.
+func recordToTask(task *entity.Task, record *model.TaskRecord) string {
+	task.Title = record.Title
+	task.Description = record.Description
+	if record.Status != "" {
+		task.Status = record.Status
+	} else if task.Status == "" {
+		task.Status = "pending"
+	}
+	if record.Priority != "" {
+		task.Priority = record.Priority
+	} else if task.Priority == "" {
+		task.Priority = "medium"
+	}
+	if record.Uid != "" && task.Uid == nil {
+		uid := record.Uid
+		task.Uid = &uid
+	}
+
+	task.DueDate, task.DueTime, task.StartDate, task.Recurrence = nil, nil, nil, nil
+	if record.DueDate != "" {
+		due, _ := time.Parse(time.DateOnly, record.DueDate)
+		task.DueDate = &due
+	}
+	if record.DueTime != "" {
+		clock := record.DueTime
+		task.DueTime = &clock
+	}
+	if record.StartDate != "" {
+		start, _ := time.Parse(time.DateOnly, record.StartDate)
+		task.StartDate = &start
+	}
+	if err := checkSchedule(task.StartDate, task.DueDate); err != nil {
+		return err.Error()
+	}
+	if record.Recurrence != "" {
+		recurrence := record.Recurrence
+		task.Recurrence = &recurrence
+	}
+	return ""
+}
+
+func taskToRecord(task *entity.Task) model.TaskRecord {
+	record := model.TaskRecord{
+		ID:          task.ID,
+		ProjectId:   task.ProjectId,
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      task.Status,
+		Priority:    task.Priority,
+		Tags:        make([]string, len(task.Tags)),
+		CreatedAt:   task.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:   task.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if task.Uid != nil {
+		record.Uid = *task.Uid
+	}
+	if task.DueDate != nil {
+		record.DueDate = task.DueDate.Format(time.DateOnly)
+	}
+	if task.DueTime != nil {
+		record.DueTime = (*task.DueTime)[:min(len(*task.DueTime), 5)]
+	}
+	if task.StartDate != nil {
+		record.StartDate = task.StartDate.Format(time.DateOnly)
+	}
+	if task.Recurrence != nil {
+		record.Recurrence = *task.Recurrence
+	}
+	for i, tag := range task.Tags {
+		record.Tags[i] = tag.Name
+	}
+	return record
+}
+
+// recordError describes the first failed validation of a record, e.g.
+// "due_date is invalid (datetime)".
+func recordError(err error) string {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) || len(errs) == 0 {
+		return err.Error()
+	}
+	field := errs[0].Field()
+	var b strings.Builder
+	for i, r := range field {
+		if unicode.IsUpper(r) && i > 0 && !unicode.IsUpper(rune(field[i-1])) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return fmt.Sprintf("%s is invalid (%s)", b.String(), errs[0].Tag())
+}
+
+// recordWriter writes task records in an export format.
+type recordWriter interface {
+	Write(record model.TaskRecord) error
+	Flush() error
+}
+
+func newRecordWriter(format string, w io.Writer) recordWriter {
+	if format == "ndjson" {
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		return &ndjsonWriter{encoder: encoder}
+	}
+	return &csvWriter{writer: csv.NewWriter(w)}
+}
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *ndjsonWriter) Write(record model.TaskRecord) error {
+	return w.encoder.Encode(record)
+}
+
+func (w *ndjsonWriter) Flush() error {
+	return nil
+}
+
+// csvWriter writes a header row followed by one row per record. Tags are
+// joined with commas.
+type csvWriter struct {
+	writer *csv.Writer
+	header bool
+}
+
+func (w *csvWriter) Write(record model.TaskRecord) error {
+	if !w.header {
+		w.header = true
+		if err := w.writer.Write(model.TaskRecordFields); err != nil {
+			return err
+		}
+	}
+	return w.writer.Write([]string{
+		formatId(record.ID), record.Uid, formatId(record.ProjectId), record.Title, record.Description,
+		record.Status, record.Priority, record.DueDate, record.DueTime, record.StartDate, record.Recurrence,
+		strings.Join(record.Tags, ","), record.CreatedAt, record.UpdatedAt,
+	})
+}
+
+func (w *csvWriter) Flush() error {
+	if !w.header {
+		w.header = true
+		w.writer.Write(model.TaskRecordFields)
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func formatId(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// readTaskRows reads the records of a CSV or NDJSON file. Mapping renames
+// the column or key a field is read from. Rows that cannot be read are
+// returned with an error, a file that cannot be read at all is an error.
+func readTaskRows(format string, data []byte, mapping map[string]string) ([]taskRow, error) {
+	data = bytes.TrimPrefix(data, []byte("\uFEFF"))
+	if format == "ndjson" {
+		return readNDJSON(data, mapping)
+	}
+	return readCSV(data, mapping)
+}
+
+func readCSV(data []byte, mapping map[string]string) ([]taskRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("missing header row")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for _, field := range model.TaskRecordFields {
+		name, mapped := mapping[field]
+		if !mapped {
+			name = field
+		}
+		for i, column := range header {
+			if strings.EqualFold(strings.TrimSpace(column), name) {
+				columns[field] = i
+				break
+			}
+		}
+		if _, ok := columns[field]; mapped && !ok {
+			return nil, fmt.Errorf("column %q not found", name)
+		}
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, errors.New("missing title column")
+	}
+
+	var rows []taskRow
+	for {
+		values, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := taskRow{Item: len(rows) + 1, Record: model.TaskRecord{Tags: []string{}}}
+		for field, i := range columns {
+			if i >= len(values) {
+				continue
+			}
+			if err := setRecordField(&row.Record, field, strings.TrimSpace(values[i])); err != nil {
+				row.Error = err.Error()
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+// setRecordField sets a field of the record from its CSV value.
+func setRecordField(record *model.TaskRecord, field string, value string) error {
+	switch field {
+	case "id", "project_id":
+		if value == "" {
+			return nil
+		}
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return fmt.Errorf("%s is invalid (number)", field)
+		}
+		if field == "id" {
+			record.ID = uint(id)
+		} else {
+			record.ProjectId = uint(id)
+		}
+	case "uid":
+		record.Uid = value
+	case "title":
+		record.Title = value
+	case "description":
+		record.Description = value
+	case "status":
+		record.Status = value
+	case "priority":
+		record.Priority = value
+	case "due_date":
+		record.DueDate = value
+	case "due_time":
+		record.DueTime = value
+	case "start_date":
+		record.StartDate = value
+	case "recurrence":
+		record.Recurrence = value
+	case "tags":
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				record.Tags = append(record.Tags, tag)
+			}
+		}
+	}
+	return nil
+}
+
+// readNDJSON reads one JSON object per line. Blank lines are ignored.
+func readNDJSON(data []byte, mapping map[string]string) ([]taskRow, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	var rows []taskRow
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		row := taskRow{Item: len(rows) + 1, Record: model.TaskRecord{Tags: []string{}}}
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(line, &object); err != nil {
+			row.Error = "invalid JSON: " + err.Error()
+			rows = append(rows, row)
+			continue
+		}
+		for field, key := range mapping {
+			if value, ok := object[key]; ok {
+				object[field] = value
+			}
+		}
+		remapped, _ := json.Marshal(object)
+		if err := json.Unmarshal(remapped, &row.Record); err != nil {
+			row.Error = "invalid JSON: " + err.Error()
+		}
+		rows = append(rows, row)
+	}
+	return rows, scanner.Err()
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+)
+
+func checkTaskRows(t *testing.T, got []taskRow, want []taskRow) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("read %d rows, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if want[i].Record.Tags == nil {
+			want[i].Record.Tags = []string{}
+		}
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("row %d\n got: %+v\nwant: %+v", i+1, got[i], want[i])
+		}
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	data := "\uFEFFID, Name ,Notes,State,Labels,Due,Ignored\r\n" +
+		"7,Pay invoice,\"Net 30, by transfer\",completed,\"finance, q4 ,,\",2026-11-01,x\r\n" +
+		",Call bank,,,,,\r\n" +
+		"abc,Short row\r\n"
+	mapping := map[string]string{"title": "name", "description": "Notes", "status": "STATE", "tags": "Labels", "due_date": "Due"}
+
+	rows, err := readTaskRows("csv", []byte(data), mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTaskRows(t, rows, []taskRow{
+		{Item: 1, Record: model.TaskRecord{ID: 7, Title: "Pay invoice", Description: "Net 30, by transfer", Status: "completed", DueDate: "2026-11-01", Tags: []string{"finance", "q4"}}},
+		{Item: 2, Record: model.TaskRecord{Title: "Call bank"}},
+		{Item: 3, Record: model.TaskRecord{Title: "Short row"}, Error: "id is invalid (number)"},
+	})
+}
+
+func TestReadCSVInvalid(t *testing.T) {
+	tests := []struct {
+		data    string
+		mapping map[string]string
+		message string
+	}{
+		{data: "", message: "missing header row"},
+		{data: "name,notes\nPay,\n", message: "missing title column"},
+		{data: "title\nPay\n", mapping: map[string]string{"due_date": "Deadline"}, message: `column "Deadline" not found`},
+		{data: "title\n\"Pay\n", message: `extraneous or missing "`},
+	}
+	for _, test := range tests {
+		if rows, err := readTaskRows("csv", []byte(test.data), test.mapping); err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Errorf("readTaskRows(%q) = %+v, %v, want an error with %q", test.data, rows, err, test.message)
+		}
+	}
+}
+
+func TestReadNDJSON(t *testing.T) {
+	data := `{"name": "Pay invoice", "labels": ["finance"], "priority": "high", "due_date": "2026-11-01"}` + "\n" +
+		"\n" +
+		`{"title": "Call bank", "name": "mapped key wins"}` + "\r\n" +
+		`{"name": "Broken"` + "\n" +
+		`{"name": "Wrong type", "labels": "finance"}` + "\n"
+	mapping := map[string]string{"title": "name", "tags": "labels"}
+
+	rows, err := readTaskRows("ndjson", []byte(data), mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("read %d rows, want 4: %+v", len(rows), rows)
+	}
+	checkTaskRows(t, rows[:2], []taskRow{
+		{Item: 1, Record: model.TaskRecord{Title: "Pay invoice", Priority: "high", DueDate: "2026-11-01", Tags: []string{"finance"}}},
+		{Item: 2, Record: model.TaskRecord{Title: "mapped key wins"}},
+	})
+	for _, row := range rows[2:] {
+		if !strings.HasPrefix(row.Error, "invalid JSON: ") {
+			t.Errorf("row %d error = %q, want invalid JSON", row.Item, row.Error)
+		}
+	}
+}
+
+// TestExportImportRoundTrip writes a task the way the export does and
+// reads it back the way the import does.
+func TestExportImportRoundTrip(t *testing.T) {
+	due := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	start := time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)
+	uid, clock, rule := "abc@example.com", "17:30:00", "FREQ=WEEKLY"
+	task := &entity.Task{
+		ID: 42, ProjectId: 3, Uid: &uid, Title: `Pay "invoice", now`, Description: "line one\nline two",
+		Status: "in_progress", Priority: "urgent", DueDate: &due, DueTime: &clock, StartDate: &start, Recurrence: &rule,
+		Tags:      []entity.Tag{{Name: "finance"}, {Name: "q4"}},
+		CreatedAt: time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC),
+	}
+	want := taskToRecord(task)
+	if want.DueTime != "17:30" {
+		t.Errorf("exported due time = %q, want 17:30", want.DueTime)
+	}
+
+	for _, format := range []string{"csv", "ndjson"} {
+		var buffer bytes.Buffer
+		writer := newRecordWriter(format, &buffer)
+		if err := writer.Write(want); err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		rows, err := readTaskRows(format, buffer.Bytes(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := want
+		if format == "csv" {
+			// timestamps are informational and not read back from CSV
+			expected.CreatedAt, expected.UpdatedAt = "", ""
+		}
+		checkTaskRows(t, rows, []taskRow{{Item: 1, Record: expected}})
+
+		imported := new(entity.Task)
+		if reason := recordToTask(imported, &rows[0].Record); reason != "" {
+			t.Fatalf("%s import skipped the task: %s", format, reason)
+		}
+		if imported.Title != task.Title || imported.Status != task.Status || *imported.Uid != uid || !sameDate(imported.DueDate, task.DueDate) || *imported.DueTime != "17:30" {
+			t.Errorf("%s imported %+v", format, imported)
+		}
+	}
+}
+
+func TestRecordToTaskKeepsDefaults(t *testing.T) {
+	task := new(entity.Task)
+	if reason := recordToTask(task, &model.TaskRecord{Title: "New"}); reason != "" {
+		t.Fatal(reason)
+	}
+	if task.Status != "pending" || task.Priority != "medium" {
+		t.Errorf("new task = %s, %s, want pending, medium", task.Status, task.Priority)
+	}
+
+	uid := "kept@example.com"
+	existing := &entity.Task{Status: "completed", Priority: "high", Uid: &uid}
+	if reason := recordToTask(existing, &model.TaskRecord{Title: "Existing", Uid: "other@example.com"}); reason != "" {
+		t.Fatal(reason)
+	}
+	if existing.Status != "completed" || existing.Priority != "high" || *existing.Uid != uid {
+		t.Errorf("existing task = %s, %s, %s, want its values kept", existing.Status, existing.Priority, *existing.Uid)
+	}
+
+	if reason := recordToTask(new(entity.Task), &model.TaskRecord{Title: "Late", DueDate: "2026-10-01", StartDate: "2026-10-02"}); reason != "start_date must not be after due_date" {
+		t.Errorf("reason = %q", reason)
+	}
+}
@@ -21,16 +21,20 @@ type UserUseCase struct {
     Log            *logrus.Logger
     Validate       *validator.Validate
     UserRepository *repository.UserRepository
+    ProjectRepository       *repository.ProjectRepository
+    ProjectMemberRepository *repository.ProjectMemberRepository
     Jwt            *helper.JwtHelper
     Cache          *helper.CacheHelper
 }
 
-func NewUserUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, userRepository *repository.UserRepository, jwt *helper.JwtHelper, cache *helper.CacheHelper) *UserUseCase {
+func NewUserUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, userRepository *repository.UserRepository, projectRepository *repository.ProjectRepository, projectMemberRepository *repository.ProjectMemberRepository, jwt *helper.JwtHelper, cache *helper.CacheHelper) *UserUseCase {
     return &UserUseCase{
         DB:             db,
         Log:            log,
         Validate:       validate,
         UserRepository: userRepository,
+        ProjectRepository:       projectRepository,
+        ProjectMemberRepository: projectMemberRepository,
         Jwt:            jwt,
         Cache:          cache,
     }
@@ -74,8 +78,16 @@ func (c *UserUseCase) Create(ctx context.Context, request *model.CreateUserReque
         Name:     request.Name,
         Email:    request.Email,
         Password: string(password),
+        Timezone: request.Timezone,
+        Locale:   request.Locale,
         AccessToken: accessToken,
     }
+    if user.Timezone == "" {
+        user.Timezone = "UTC"
+    }
+    if user.Locale == "" {
+        user.Locale = "en"
+    }
 
     err = c.UserRepository.Create(tx, user)
     if err != nil {
@@ -83,6 +95,26 @@ func (c *UserUseCase) Create(ctx context.Context, request *model.CreateUserReque
         return nil, model.ErrInternalServer
     }
 
+    project := &entity.Project{
+        OwnerEmail: user.Email,
+        Name:       "Personal",
+        IsPersonal: true,
+    }
+    err = c.ProjectRepository.Create(tx, project)
+    if err != nil {
+        c.Log.Warnf("Failed to create personal project : %+v", err)
+        return nil, model.ErrInternalServer
+    }
+
+    err = c.ProjectMemberRepository.Create(tx, &entity.ProjectMember{
+        ProjectId: project.ID,
+        Email:     user.Email,
+        Role:      model.ProjectRoleOwner,
+    })
+    if err != nil {
+        c.Log.Warnf("Failed to create project owner : %+v", err)
+        return nil, model.ErrInternalServer
+    }
 
     err = tx.Commit().Error
     if err != nil {
@@ -94,6 +126,7 @@ func (c *UserUseCase) Create(ctx context.Context, request *model.CreateUserReque
     sessionData := map[string]string{
         "accessToken":  accessToken,
         "refreshToken": refreshToken,
+        "timezone":     user.Timezone,
     }
     sessionDataJSON, _ := json.Marshal(sessionData)
     
@@ -149,6 +182,7 @@ func (c *UserUseCase) Login(ctx context.Context, request *model.LoginUserRequest
     sessionData := map[string]string{
         "accessToken":  accessToken,
         "refreshToken": refreshToken,
+        "timezone":     user.Timezone,
     }
     sessionDataJSON, _ := json.Marshal(sessionData)
     
@@ -162,38 +196,82 @@ func (c *UserUseCase) Login(ctx context.Context, request *model.LoginUserRequest
 }
 
 
+// Update replaces the profile of the user. The password is only changed
+// when the request has one.
 func (c *UserUseCase) Update(ctx context.Context, request *model.UpdateUserRequest) (*model.UserResponse, error) {
-	tx := c.DB.WithContext(ctx).Begin()
-	defer tx.Rollback()
+	err := c.Validate.Struct(request)
+	if err != nil {
+		c.Log.Warnf("Failed to validate request body : %+v", err)
+		return nil, model.ErrBadRequest
+	}
 
+	return c.edit(ctx, request.Email, request.Password, func(user *entity.User) {
+		user.Name = request.Name
+		user.Timezone = request.Timezone
+		if user.Timezone == "" {
+			user.Timezone = "UTC"
+		}
+		user.Locale = request.Locale
+		if user.Locale == "" {
+			user.Locale = "en"
+		}
+	})
+}
+
+// Patch applies a JSON merge patch to the profile of the user.
+func (c *UserUseCase) Patch(ctx context.Context, request *model.PatchUserRequest) (*model.UserResponse, error) {
 	err := c.Validate.Struct(request)
 	if err != nil {
 		c.Log.Warnf("Failed to validate request body : %+v", err)
 		return nil, model.ErrBadRequest
 	}
-	user := new(entity.User)
+	if request.Name.Null {
+		return nil, model.NewApiError(model.ErrBadRequest.StatusCode, "name cannot be null")
+	}
+	if request.Password.Null {
+		return nil, model.NewApiError(model.ErrBadRequest.StatusCode, "password cannot be null")
+	}
 
-	err = c.UserRepository.FindByEmail(tx, user, request.Email)
+	return c.edit(ctx, request.Email, request.Password.Value, func(user *entity.User) {
+		if request.Name.Set {
+			user.Name = request.Name.Value
+		}
+		if request.Timezone.Null {
+			user.Timezone = "UTC"
+		} else if request.Timezone.Set {
+			user.Timezone = request.Timezone.Value
+		}
+		if request.Locale.Null {
+			user.Locale = "en"
+		} else if request.Locale.Set {
+			user.Locale = request.Locale.Value
+		}
+	})
+}
+
+// edit loads the user, lets change modify the profile and stores it
+// together with the new password, if any.
+func (c *UserUseCase) edit(ctx context.Context, email string, password string, change func(user *entity.User)) (*model.UserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	user := new(entity.User)
+	err := c.UserRepository.FindByEmail(tx, user, email)
 	if err != nil {
 		c.Log.Warnf("Failed to find user : %+v", err)
 		return nil, model.ErrInternalServer
 	}
 
-	if request.Name != "" {
-		user.Name = request.Name
-	}
-
-	if request.Email != "" {
-		user.Email = request.Email
-	}
+	timezone := user.Timezone
+	change(user)
 
-	if request.Password != "" {
-		password, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
+	if password != "" {
+		hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 		if err != nil {
 			c.Log.Warnf("Failed to generate password : %+v", err)
 			return nil, model.ErrInternalServer
 		}
-		user.Password = string(password)
+		user.Password = string(hashed)
 	}
 
 	err = c.UserRepository.Update(tx, user)
@@ -208,9 +286,33 @@ func (c *UserUseCase) Update(ctx context.Context, request *model.UpdateUserReque
 		return nil, model.ErrInternalServer
 	}
 
+	if user.Timezone != timezone {
+		c.updateSessionTimezone(ctx, user.Email, user.Timezone)
+	}
+
 	return converter.UserToResponse(user), nil
 }
 
+// updateSessionTimezone stores the new time zone in the active session so
+// the auth middleware picks it up without another login.
+func (c *UserUseCase) updateSessionTimezone(ctx context.Context, email string, timezone string) {
+	sessionKey := "session:" + email
+	sessionDataJSON, err := c.Cache.Get(ctx, sessionKey)
+	if err != nil {
+		return
+	}
+	var sessionData map[string]string
+	if err := json.Unmarshal([]byte(sessionDataJSON), &sessionData); err != nil {
+		c.Log.Warnf("Failed to parse session : %+v", err)
+		return
+	}
+	sessionData["timezone"] = timezone
+	updated, _ := json.Marshal(sessionData)
+	if err := c.Cache.Set(ctx, sessionKey, updated, helper.KeepTTL); err != nil {
+		c.Log.Warnf("Failed to store session : %+v", err)
+	}
+}
+
 func (c *UserUseCase) Current(ctx context.Context, request *model.GetUserRequest) (*model.UserResponse, error) {
 	tx := c.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/helper"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/repository"
+)
+
+// checkVersion enforces the If-Match header of a write. Without the header
+// any version may be changed.
+func checkVersion(ifMatch string, version uint) error {
+	if ifMatch != "" && !helper.MatchETag(ifMatch, version, false) {
+		return model.ErrPreconditionFailed
+	}
+	return nil
+}
+
+// writeError turns the error of saving a versioned row into the response:
+// a row changed by a concurrent request fails the precondition too.
+func writeError(err error) error {
+	if errors.Is(err, repository.ErrStaleVersion) {
+		return model.ErrPreconditionFailed
+	}
+	return model.ErrInternalServer
+}
@@ -0,0 +1,402 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/abdisetiakawan/go-clean-arch/internal/entity"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model"
+	"github.com/abdisetiakawan/go-clean-arch/internal/model/converter"
+	"github.com/abdisetiakawan/go-clean-arch/internal/repository"
+	"github.com/abdisetiakawan/go-clean-arch/internal/webhook"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// webhookPolicy gives up a delivery after 8 tries and disables a webhook
+// after 15 failed tries in a row.
+var webhookPolicy = webhook.Policy{MaxAttempts: 8, DisableAfter: 15}
+
+const (
+	// webhookBatchSize is the number of deliveries sent per poll.
+	webhookBatchSize = 20
+	// webhookPollInterval is the wait between polls for due deliveries.
+	webhookPollInterval = 2 * time.Second
+	// webhookTimeout bounds one try of a delivery. The lease of a claimed
+	// delivery outlasts a batch of them.
+	webhookTimeout = 10 * time.Second
+	webhookLease   = 5 * time.Minute
+)
+
+// WebhookUseCase manages webhooks and sends their deliveries. Deliveries
+// are queued with the activity they send, see ActivityRepository.Record.
+type WebhookUseCase struct {
+	DB                        *gorm.DB
+	Log                       *logrus.Logger
+	Validate                  *validator.Validate
+	WebhookRepository         *repository.WebhookRepository
+	WebhookDeliveryRepository *repository.WebhookDeliveryRepository
+	ProjectMemberRepository   *repository.ProjectMemberRepository
+	Client                    *webhook.Client
+	// Now is the clock deliveries are scheduled with.
+	Now func() time.Time
+}
+
+func NewWebhookUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, webhookRepository *repository.WebhookRepository, webhookDeliveryRepository *repository.WebhookDeliveryRepository, projectMemberRepository *repository.ProjectMemberRepository) *WebhookUseCase {
+	return &WebhookUseCase{
+		DB:                        db,
+		Log:                       log,
+		Validate:                  validate,
+		WebhookRepository:         webhookRepository,
+		WebhookDeliveryRepository: webhookDeliveryRepository,
+		ProjectMemberRepository:   projectMemberRepository,
+		Client:                    webhook.NewClient(webhookTimeout),
+		Now:                       time.Now,
+	}
+}
+
+// Create registers a webhook. The response holds the signing secret, which
+// is not shown again.
+func (c *WebhookUseCase) Create(ctx context.Context, request *model.SaveWebhookRequest) (*model.WebhookResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	if err := c.checkProject(tx, request); err != nil {
+		return nil, err
+	}
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		c.Log.WithError(err).Error("error generate webhook secret")
+		return nil, model.ErrInternalServer
+	}
+	events, _ := json.Marshal(request.Events)
+	hook := &entity.Webhook{
+		Email:     request.Email,
+		ProjectId: request.ProjectId,
+		URL:       request.URL,
+		Secret:    secret,
+		Events:    string(events),
+		Active:    request.Active == nil || *request.Active,
+	}
+	if err := c.WebhookRepository.Create(tx, hook); err != nil {
+		c.Log.WithError(err).Error("error create webhook")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error create webhook")
+		return nil, model.ErrInternalServer
+	}
+
+	response := converter.WebhookToResponse(hook)
+	response.Secret = hook.Secret
+	return response, nil
+}
+
+func (c *WebhookUseCase) List(ctx context.Context, email string) ([]model.WebhookResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	webhooks, err := c.WebhookRepository.FindAllByEmail(tx, email)
+	if err != nil {
+		c.Log.WithError(err).Error("error search webhooks")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error search webhooks")
+		return nil, model.ErrInternalServer
+	}
+
+	responses := make([]model.WebhookResponse, len(webhooks))
+	for i, hook := range webhooks {
+		responses[i] = *converter.WebhookToResponse(&hook)
+	}
+	return responses, nil
+}
+
+func (c *WebhookUseCase) Get(ctx context.Context, request *model.GetWebhookRequest) (*model.WebhookResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return nil, model.ErrBadRequest
+	}
+	hook := new(entity.Webhook)
+	if err := c.WebhookRepository.FindByEmailAndId(tx, hook, request.ID, request.Email); err != nil {
+		c.Log.WithError(err).Error("error search webhook")
+		return nil, model.ErrNotFound
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error search webhook")
+		return nil, model.ErrInternalServer
+	}
+
+	return converter.WebhookToResponse(hook), nil
+}
+
+// Update replaces the webhook with the request. Enabling a webhook clears
+// its failures; the secret is kept.
+func (c *WebhookUseCase) Update(ctx context.Context, request *model.SaveWebhookRequest) (*model.WebhookResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request body")
+		return nil, model.ErrBadRequest
+	}
+	hook := new(entity.Webhook)
+	if err := c.WebhookRepository.FindByEmailAndId(tx, hook, request.ID, request.Email); err != nil {
+		c.Log.WithError(err).Error("error search webhook")
+		return nil, model.ErrNotFound
+	}
+	if err := c.checkProject(tx, request); err != nil {
+		return nil, err
+	}
+	events, _ := json.Marshal(request.Events)
+	hook.ProjectId = request.ProjectId
+	hook.URL = request.URL
+	hook.Events = string(events)
+	if request.Active != nil {
+		if *request.Active && !hook.Active {
+			hook.FailureCount = 0
+			hook.DisabledAt = nil
+		}
+		hook.Active = *request.Active
+	}
+	if err := c.WebhookRepository.Update(tx, hook); err != nil {
+		c.Log.WithError(err).Error("error update webhook")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error update webhook")
+		return nil, model.ErrInternalServer
+	}
+
+	return converter.WebhookToResponse(hook), nil
+}
+
+func (c *WebhookUseCase) Delete(ctx context.Context, request *model.GetWebhookRequest) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return model.ErrBadRequest
+	}
+	hook := new(entity.Webhook)
+	if err := c.WebhookRepository.FindByEmailAndId(tx, hook, request.ID, request.Email); err != nil {
+		c.Log.WithError(err).Error("error search webhook")
+		return model.ErrNotFound
+	}
+	if err := c.WebhookRepository.Delete(tx, hook); err != nil {
+		c.Log.WithError(err).Error("error delete webhook")
+		return model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error delete webhook")
+		return model.ErrInternalServer
+	}
+	return nil
+}
+
+// Deliveries lists the delivery log of the webhook, newest first.
+func (c *WebhookUseCase) Deliveries(ctx context.Context, request *model.SearchWebhookDeliveryRequest) ([]model.WebhookDeliveryResponse, *model.PageMetadata, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return nil, nil, model.ErrBadRequest
+	}
+	hook := new(entity.Webhook)
+	if err := c.WebhookRepository.FindByEmailAndId(tx, hook, request.WebhookId, request.Email); err != nil {
+		c.Log.WithError(err).Error("error search webhook")
+		return nil, nil, model.ErrNotFound
+	}
+	deliveries, paging, err := c.WebhookDeliveryRepository.Search(tx, hook.ID, request)
+	if err != nil {
+		c.Log.WithError(err).Error("error search webhook deliveries")
+		return nil, nil, pageError(err, model.ErrInternalServer)
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error search webhook deliveries")
+		return nil, nil, model.ErrInternalServer
+	}
+
+	responses := make([]model.WebhookDeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		responses[i] = *converter.WebhookDeliveryToResponse(&delivery)
+	}
+	return responses, paging, nil
+}
+
+// Redeliver queues a new delivery of the activity of an earlier one. The
+// earlier delivery stays in the log as it was.
+func (c *WebhookUseCase) Redeliver(ctx context.Context, request *model.RedeliverWebhookRequest) (*model.WebhookDeliveryResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.WithError(err).Error("error validate request query")
+		return nil, model.ErrBadRequest
+	}
+	hook := new(entity.Webhook)
+	if err := c.WebhookRepository.FindByEmailAndId(tx, hook, request.WebhookId, request.Email); err != nil {
+		c.Log.WithError(err).Error("error search webhook")
+		return nil, model.ErrNotFound
+	}
+	if !hook.Active {
+		return nil, model.NewApiError(model.ErrConflict.StatusCode, "webhook is disabled, enable it before redelivering")
+	}
+	previous := new(entity.WebhookDelivery)
+	if err := c.WebhookDeliveryRepository.FindByWebhookAndId(tx, previous, request.ID, hook.ID); err != nil {
+		c.Log.WithError(err).Error("error search webhook delivery")
+		return nil, model.ErrNotFound
+	}
+	// the activity is sent again, so the owner of the webhook must still
+	// be allowed to see it
+	if err := checkWriteAccess(tx, c.ProjectMemberRepository, previous.Activity.ProjectId, request.Email); err != nil {
+		c.Log.WithError(err).Error("error check project access")
+		return nil, err
+	}
+	now := c.Now()
+	delivery := &entity.WebhookDelivery{
+		WebhookId:     hook.ID,
+		ActivityId:    previous.ActivityId,
+		Event:         previous.Event,
+		Status:        "pending",
+		NextAttemptAt: &now,
+	}
+	if err := c.WebhookDeliveryRepository.Create(tx, delivery); err != nil {
+		c.Log.WithError(err).Error("error create webhook delivery")
+		return nil, model.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.Log.WithError(err).Error("error create webhook delivery")
+		return nil, model.ErrInternalServer
+	}
+
+	return converter.WebhookDeliveryToResponse(delivery), nil
+}
+
+// checkProject makes sure a webhook limited to a project belongs to an
+// owner or editor of it.
+func (c *WebhookUseCase) checkProject(tx *gorm.DB, request *model.SaveWebhookRequest) error {
+	if request.ProjectId == nil {
+		return nil
+	}
+	if err := checkWriteAccess(tx, c.ProjectMemberRepository, *request.ProjectId, request.Email); err != nil {
+		c.Log.WithError(err).Error("error check project access")
+		return err
+	}
+	return nil
+}
+
+// Run sends due deliveries until ctx is done.
+func (c *WebhookUseCase) Run(ctx context.Context) {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+	for {
+		for {
+			sent, err := c.DeliverDue(ctx)
+			if err != nil {
+				c.Log.WithError(err).Error("error send webhook deliveries")
+			}
+			if err != nil || sent < webhookBatchSize {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue sends one batch of due deliveries and returns its size.
+func (c *WebhookUseCase) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := c.WebhookDeliveryRepository.Claim(c.DB.WithContext(ctx), c.Now(), webhookLease, webhookBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for i := range deliveries {
+		if err := c.deliver(ctx, &deliveries[i]); err != nil {
+			c.Log.WithError(err).WithField("delivery", deliveries[i].ID).Error("error record webhook delivery")
+		}
+	}
+	return len(deliveries), nil
+}
+
+// deliver makes one try of the delivery and records its outcome. A failed
+// delivery is tried again with a growing wait as long as webhookPolicy
+// allows.
+func (c *WebhookUseCase) deliver(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	body, _ := json.Marshal(model.WebhookPayload{
+		DeliveryId: delivery.ID,
+		Event:      delivery.Event,
+		Activity:   *converter.ActivityToResponse(&delivery.Activity),
+	})
+	response, err := c.Client.Send(ctx, &webhook.Request{
+		URL:      delivery.Webhook.URL,
+		Secret:   delivery.Webhook.Secret,
+		Event:    delivery.Event,
+		Delivery: strconv.Itoa(int(delivery.ID)),
+		Body:     body,
+	})
+	now := c.Now()
+	delivery.Attempts++
+	delivery.ResponseCode = nil
+	delivery.Error = ""
+	switch {
+	case err != nil:
+		delivery.Error = truncate(err.Error(), 255)
+	case !response.OK():
+		delivery.ResponseCode = &response.StatusCode
+		delivery.Error = "receiver answered " + strconv.Itoa(response.StatusCode)
+	default:
+		delivery.ResponseCode = &response.StatusCode
+	}
+
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+	if delivery.Error == "" {
+		delivery.Status = "succeeded"
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		if err := c.WebhookRepository.RecordSuccess(tx, delivery.WebhookId); err != nil {
+			return err
+		}
+	} else {
+		if next, ok := webhookPolicy.Retry(delivery.Attempts, now); ok {
+			delivery.NextAttemptAt = &next
+		} else {
+			delivery.Status = "failed"
+			delivery.NextAttemptAt = nil
+		}
+		failures, err := c.WebhookRepository.RecordFailure(tx, delivery.WebhookId)
+		if err != nil {
+			return err
+		}
+		if webhookPolicy.Disable(failures) {
+			disabled, err := c.WebhookRepository.Disable(tx, delivery.WebhookId, now)
+			if err != nil {
+				return err
+			}
+			if disabled {
+				c.Log.WithField("webhook", delivery.WebhookId).Warn("webhook disabled after repeated failures")
+			}
+		}
+	}
+	if err := c.WebhookDeliveryRepository.Save(tx, delivery); err != nil {
+		return err
+	}
+	return tx.Commit().Error
+}
@@ -0,0 +1,237 @@
+// Package webhook signs and sends webhook deliveries.
+//
+// A delivery is a POST of a JSON body with the headers
+//
+//	X-Webhook-Timestamp: 1760866200
+//	X-Webhook-Signature: sha256=<hex>
+//
+// where the signature is the HMAC-SHA256, keyed with the secret of the
+// webhook, of the timestamp, a dot and the body. Receivers check the
+// signature with Verify and reject old timestamps so a captured delivery
+// cannot be replayed later.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Tolerance is how far the timestamp of a delivery may be from the clock
+// of the receiver.
+const Tolerance = 5 * time.Minute
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrInvalidTimestamp = errors.New("webhook timestamp outside tolerance")
+)
+
+// NewSecret returns a random secret for signing deliveries.
+func NewSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
+
+// Sign returns the signature header value of a body sent at timestamp.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the timestamp and signature headers of a delivery received
+// at now.
+func Verify(secret string, timestamp string, signature string, body []byte, now time.Time) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+	sent := time.Unix(seconds, 0)
+	if sent.Before(now.Add(-Tolerance)) || sent.After(now.Add(Tolerance)) {
+		return ErrInvalidTimestamp
+	}
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, sent, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Backoff returns the wait before the next try of a delivery that failed
+// attempts times: 30 seconds doubling up to 4 hours.
+func Backoff(attempts int) time.Duration {
+	wait := 30 * time.Second
+	for i := 1; i < attempts && wait < 4*time.Hour; i++ {
+		wait *= 2
+	}
+	return min(wait, 4*time.Hour)
+}
+
+// Policy is how often a failing delivery is tried and how many failures
+// in a row disable its webhook.
+type Policy struct {
+	MaxAttempts  int
+	DisableAfter int
+}
+
+// Retry returns when a delivery that failed attempts times is tried next,
+// or false once it has been tried MaxAttempts times.
+func (p Policy) Retry(attempts int, now time.Time) (time.Time, bool) {
+	if attempts >= p.MaxAttempts {
+		return time.Time{}, false
+	}
+	return now.Add(Backoff(attempts)), true
+}
+
+// Disable reports whether a webhook whose last failures tries failed is
+// disabled.
+func (p Policy) Disable(failures int) bool {
+	return p.DisableAfter > 0 && failures >= p.DisableAfter
+}
+
+// Request is one try of a delivery.
+type Request struct {
+	URL      string
+	Secret   string
+	Event    string
+	Delivery string
+	Body     []byte
+}
+
+// Response is what the receiver answered. Body is cut off at maxBody
+// bytes.
+type Response struct {
+	StatusCode int
+	Body       string
+}
+
+// OK reports whether the receiver accepted the delivery.
+func (r *Response) OK() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+const maxBody = 1024
+
+// ErrBlockedAddress is returned for a receiver that resolves to an address
+// of the host or its network, which webhooks may not reach.
+var ErrBlockedAddress = errors.New("address is not public")
+
+// reservedPrefixes are ranges netip counts as global unicast though they
+// never belong to a public receiver: "this network" and carrier-grade NAT.
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// publicAddress reports whether a webhook may connect to the address: not
+// loopback, private, link-local (which holds cloud metadata services),
+// multicast or unspecified.
+func publicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// Client sends deliveries.
+type Client struct {
+	HTTP *http.Client
+	// Now is the clock the timestamp of a delivery is taken from.
+	Now func() time.Time
+}
+
+// NewClient returns a client that only connects to public addresses and
+// does not follow redirects, so a webhook cannot be pointed at services
+// inside the network. Addresses are checked when connecting, after name
+// resolution, so a name cannot resolve to a public address when the URL
+// is saved and to a private one later.
+func NewClient(timeout time.Duration) *Client {
+	return newClient(timeout, publicAddress)
+}
+
+func newClient(timeout time.Duration, allow func(netip.Addr) bool) *Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !allow(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", ErrBlockedAddress, addrPort.Addr())
+			}
+			return nil
+		},
+	}
+	return &Client{
+		HTTP: &http.Client{
+			Timeout: timeout,
+			// no Proxy, the address checked must be the receiver's
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+				MaxIdleConns:        100,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		Now: time.Now,
+	}
+}
+
+// Send posts the delivery. A response is returned for every answer of the
+// receiver, whatever its status, and a redirect counts as an answer; the
+// error is set when there was none.
+func (c *Client) Send(ctx context.Context, request *Request) (*Response, error) {
+	now := c.Now()
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, request.URL, bytes.NewReader(request.Body))
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("User-Agent", "go-clean-arch-webhook/1.0")
+	httpRequest.Header.Set(HeaderEvent, request.Event)
+	httpRequest.Header.Set(HeaderDelivery, request.Delivery)
+	httpRequest.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	httpRequest.Header.Set(HeaderSignature, Sign(request.Secret, now, request.Body))
+
+	httpResponse, err := c.HTTP.Do(httpRequest)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResponse.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(httpResponse.Body, maxBody))
+	return &Response{StatusCode: httpResponse.StatusCode, Body: strings.ToValidUTF8(string(body), "")}, nil
+}
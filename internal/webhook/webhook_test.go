@@ -0,0 +1,361 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const secret = "whsec_test"
+
+var sentAt = time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"event":"task.created"}`)
+	signature := Sign(secret, sentAt, body)
+	if !strings.HasPrefix(signature, "sha256=") {
+		t.Fatalf("signature = %q, want sha256= prefix", signature)
+	}
+	if Sign(secret, sentAt, body) != signature {
+		t.Error("signing the same delivery twice gave different signatures")
+	}
+	timestamp := strconv.FormatInt(sentAt.Unix(), 10)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		body      []byte
+		now       time.Time
+		err       error
+	}{
+		{name: "valid", now: sentAt},
+		{name: "late within tolerance", now: sentAt.Add(Tolerance)},
+		{name: "early within tolerance", now: sentAt.Add(-Tolerance)},
+		{name: "too old", now: sentAt.Add(Tolerance + time.Second), err: ErrInvalidTimestamp},
+		{name: "from the future", now: sentAt.Add(-Tolerance - time.Second), err: ErrInvalidTimestamp},
+		{name: "timestamp not a number", timestamp: "yesterday", now: sentAt, err: ErrInvalidTimestamp},
+		{name: "other secret", secret: "whsec_other", now: sentAt, err: ErrInvalidSignature},
+		{name: "changed body", body: []byte(`{"event":"task.deleted"}`), now: sentAt, err: ErrInvalidSignature},
+		{name: "changed timestamp", timestamp: strconv.FormatInt(sentAt.Unix()+1, 10), now: sentAt, err: ErrInvalidSignature},
+		{name: "missing signature", signature: "-", now: sentAt, err: ErrInvalidSignature},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			key, ts, sig, payload := secret, timestamp, signature, body
+			if test.secret != "" {
+				key = test.secret
+			}
+			if test.timestamp != "" {
+				ts = test.timestamp
+			}
+			if test.signature == "-" {
+				sig = ""
+			}
+			if test.body != nil {
+				payload = test.body
+			}
+			if err := Verify(key, ts, sig, payload, test.now); !errors.Is(err, test.err) {
+				t.Errorf("Verify() = %v, want %v", err, test.err)
+			}
+		})
+	}
+}
+
+func TestNewSecret(t *testing.T) {
+	first, err := NewSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(first, "whsec_") || len(first) != len("whsec_")+64 {
+		t.Errorf("secret = %q, want whsec_ and 64 hex digits", first)
+	}
+	if first == second {
+		t.Error("two secrets are equal")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{8, 64 * time.Minute},
+		{10, 4 * time.Hour},
+		{100, 4 * time.Hour},
+	}
+	for _, test := range tests {
+		if got := Backoff(test.attempts); got != test.want {
+			t.Errorf("Backoff(%d) = %v, want %v", test.attempts, got, test.want)
+		}
+	}
+}
+
+func TestPolicyRetry(t *testing.T) {
+	policy := Policy{MaxAttempts: 3, DisableAfter: 5}
+	for attempts := 1; attempts < 3; attempts++ {
+		next, ok := policy.Retry(attempts, sentAt)
+		if !ok || !next.Equal(sentAt.Add(Backoff(attempts))) {
+			t.Errorf("Retry(%d) = %v, %v, want %v", attempts, next, ok, sentAt.Add(Backoff(attempts)))
+		}
+	}
+	if _, ok := policy.Retry(3, sentAt); ok {
+		t.Error("Retry(3) retried past MaxAttempts")
+	}
+}
+
+// receiver is a local endpoint checking deliveries like a subscriber
+// would. It fails the first failures deliveries with a 503.
+type receiver struct {
+	*httptest.Server
+	failures int32
+	received atomic.Int32
+	last     atomic.Value
+}
+
+func newReceiver(t *testing.T, failures int32) *receiver {
+	r := &receiver{failures: failures}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+		r.last.Store(request.Header.Clone())
+		if err := Verify(secret, request.Header.Get(HeaderTimestamp), request.Header.Get(HeaderSignature), body, sentAt); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if r.received.Add(1) <= r.failures {
+			http.Error(w, "try again later", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+// testClient reaches the receivers of the tests, which listen on
+// loopback.
+func testClient() *Client {
+	client := newClient(time.Second, func(netip.Addr) bool { return true })
+	client.Now = func() time.Time { return sentAt }
+	return client
+}
+
+func TestClientSend(t *testing.T) {
+	r := newReceiver(t, 0)
+	request := &Request{URL: r.URL, Secret: secret, Event: "task.created", Delivery: "42", Body: []byte(`{"id":42}`)}
+
+	response, err := testClient().Send(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !response.OK() || response.Body != "ok" {
+		t.Errorf("response = %+v, want 200 ok", response)
+	}
+	header := r.last.Load().(http.Header)
+	for name, want := range map[string]string{
+		"Content-Type":  "application/json",
+		HeaderEvent:     "task.created",
+		HeaderDelivery:  "42",
+		HeaderTimestamp: strconv.FormatInt(sentAt.Unix(), 10),
+	} {
+		if got := header.Get(name); got != want {
+			t.Errorf("header %s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestClientSendWrongSecret(t *testing.T) {
+	r := newReceiver(t, 0)
+	request := &Request{URL: r.URL, Secret: "whsec_other", Event: "task.created", Delivery: "1", Body: []byte(`{}`)}
+
+	response, err := testClient().Send(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.OK() || response.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", response.StatusCode, http.StatusUnauthorized)
+	}
+	if !strings.Contains(response.Body, ErrInvalidSignature.Error()) {
+		t.Errorf("body = %q, want the signature error", response.Body)
+	}
+}
+
+func TestClientSendCutsOffLongBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(strings.Repeat("x", 4*maxBody)))
+	}))
+	defer server.Close()
+
+	response, err := testClient().Send(context.Background(), &Request{URL: server.URL, Secret: secret})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.OK() || len(response.Body) != maxBody {
+		t.Errorf("response = %d with %d bytes, want a failure with %d bytes", response.StatusCode, len(response.Body), maxBody)
+	}
+}
+
+func TestClientSendUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	if response, err := testClient().Send(context.Background(), &Request{URL: url, Secret: secret}); err == nil {
+		t.Errorf("Send() = %+v, want an error", response)
+	}
+}
+
+func TestClientSendBlocksPrivateAddresses(t *testing.T) {
+	r := newReceiver(t, 0)
+	client := NewClient(time.Second)
+
+	response, err := client.Send(context.Background(), &Request{URL: r.URL, Secret: secret})
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("Send() = %+v, %v, want %v", response, err, ErrBlockedAddress)
+	}
+	if received := r.received.Load(); received != 0 {
+		t.Errorf("receiver got %d requests, want none", received)
+	}
+}
+
+func TestClientSendDoesNotFollowRedirects(t *testing.T) {
+	r := newReceiver(t, 0)
+	server := httptest.NewServer(http.RedirectHandler(r.URL, http.StatusTemporaryRedirect))
+	defer server.Close()
+
+	response, err := testClient().Send(context.Background(), &Request{URL: server.URL, Secret: secret})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.OK() || response.StatusCode != http.StatusTemporaryRedirect {
+		t.Errorf("status = %d, want %d", response.StatusCode, http.StatusTemporaryRedirect)
+	}
+	if received := r.received.Load(); received != 0 {
+		t.Errorf("redirect target got %d requests, want none", received)
+	}
+}
+
+func TestPublicAddress(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "93.184.216.34", want: true},
+		{addr: "2606:2800:220:1:248:1893:25c8:1946", want: true},
+		{addr: "127.0.0.1", want: false},
+		{addr: "::1", want: false},
+		{addr: "10.0.0.1", want: false},
+		{addr: "172.16.5.4", want: false},
+		{addr: "192.168.1.1", want: false},
+		{addr: "fd00::1", want: false},
+		{addr: "169.254.169.254", want: false},
+		{addr: "fe80::1", want: false},
+		{addr: "0.0.0.0", want: false},
+		{addr: "0.1.2.3", want: false},
+		{addr: "::", want: false},
+		{addr: "100.64.0.1", want: false},
+		{addr: "224.0.0.1", want: false},
+		{addr: "255.255.255.255", want: false},
+		{addr: "::ffff:127.0.0.1", want: false},
+		{addr: "::ffff:169.254.169.254", want: false},
+	}
+	for _, test := range tests {
+		if got := publicAddress(netip.MustParseAddr(test.addr)); got != test.want {
+			t.Errorf("publicAddress(%s) = %v, want %v", test.addr, got, test.want)
+		}
+	}
+}
+
+// TestRetriesUntilDelivered walks a delivery through its tries against a
+// receiver that is down for a while, the way the webhook use case does.
+func TestRetriesUntilDelivered(t *testing.T) {
+	r := newReceiver(t, 3)
+	policy := Policy{MaxAttempts: 8, DisableAfter: 15}
+	client := testClient()
+
+	now, attempts := sentAt, 0
+	for {
+		response, err := client.Send(context.Background(), &Request{URL: r.URL, Secret: secret, Body: []byte(`{}`)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		attempts++
+		if response.OK() {
+			break
+		}
+		next, ok := policy.Retry(attempts, now)
+		if !ok {
+			t.Fatalf("gave up after %d attempts", attempts)
+		}
+		if want := now.Add(Backoff(attempts)); !next.Equal(want) {
+			t.Fatalf("try %d is at %v, want %v", attempts+1, next, want)
+		}
+		now = next
+	}
+	if attempts != 4 {
+		t.Errorf("delivered after %d attempts, want 4", attempts)
+	}
+	if waited := now.Sub(sentAt); waited != 30*time.Second+time.Minute+2*time.Minute {
+		t.Errorf("waited %v before delivering", waited)
+	}
+}
+
+// TestDisablesAfterRepeatedFailures sends deliveries to a receiver that
+// is down until its webhook is disabled, and checks that a success in
+// between starts the count over.
+func TestDisablesAfterRepeatedFailures(t *testing.T) {
+	policy := Policy{MaxAttempts: 3, DisableAfter: 5}
+	client := testClient()
+
+	down := newReceiver(t, 1<<30)
+	up := newReceiver(t, 0)
+	// four failures, a success, then failures until disabled
+	urls := []string{down.URL, down.URL, down.URL, down.URL, up.URL}
+	for range 10 {
+		urls = append(urls, down.URL)
+	}
+
+	failures, disabledAt := 0, 0
+	for i, url := range urls {
+		response, err := client.Send(context.Background(), &Request{URL: url, Secret: secret, Body: []byte(`{}`)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if response.OK() {
+			failures = 0
+			continue
+		}
+		failures++
+		if policy.Disable(failures) {
+			disabledAt = i + 1
+			break
+		}
+	}
+	if disabledAt != 10 {
+		t.Errorf("disabled after delivery %d, want 10", disabledAt)
+	}
+	if got := down.received.Load(); got != 9 {
+		t.Errorf("the receiver got %d failed deliveries, want 9", got)
+	}
+	if (Policy{MaxAttempts: 3}).Disable(1000) {
+		t.Error("a policy without DisableAfter disabled a webhook")
+	}
+}